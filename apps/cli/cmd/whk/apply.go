@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/manifest"
+)
+
+// --- Apply command ---
+
+func applyCmd() *cobra.Command {
+	var (
+		file   string
+		dryRun bool
+		prune  bool
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <manifest>",
+		Short: "Create and update endpoints to match a manifest file",
+		Long: `Create and update endpoints to match a YAML manifest, so endpoint
+configuration can live in version control. Endpoints in the manifest that
+don't exist are created; existing ones get the manifest's name, mock
+response and routes. A setting missing from the manifest is removed from
+the endpoint. ttl only applies to endpoints apply creates.
+
+--prune also deletes endpoints you own that aren't in the manifest, after
+asking for confirmation. Endpoints shared with you by a team are never
+deleted.
+
+--dry-run prints the changes without making them. Use - as the file to
+read the manifest from stdin.
+
+Manifest:
+  endpoints:
+    - slug: stripe-staging
+      name: Stripe (staging)
+      ttl: 30d
+      mock:
+        status: 200
+        body: '{"received":true}'
+        headers:
+          Content-Type: application/json
+    - slug: github-dev
+      routes:
+        - prefix: /push
+          forwardUrl: https://staging.example.com/hooks/push
+
+Examples:
+  whk apply -f endpoints.yaml --dry-run
+  whk apply -f endpoints.yaml
+  whk apply -f endpoints.yaml --prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				data []byte
+				err  error
+			)
+			if file == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return err
+			}
+			m, err := manifest.Parse(data)
+			if err != nil {
+				return err
+			}
+
+			client := api.NewClient()
+			ctx := cmd.Context()
+
+			current, err := client.ListEndpointsWithContext(ctx)
+			if err != nil {
+				return err
+			}
+			// The listing doesn't carry every setting; fetch the endpoints
+			// the manifest manages in full.
+			wanted := map[string]bool{}
+			for _, e := range m.Endpoints {
+				wanted[e.Slug] = true
+			}
+			for i, e := range current {
+				if !wanted[e.Slug] {
+					continue
+				}
+				full, err := client.GetEndpoint(ctx, e.Slug)
+				if err != nil {
+					return fmt.Errorf("failed to fetch %s: %w", e.Slug, err)
+				}
+				full.FromTeam = e.FromTeam
+				current[i] = *full
+			}
+
+			changes := manifest.Plan(m, current, prune)
+			if len(changes) == 0 {
+				fmt.Println("Endpoints already match the manifest")
+				return nil
+			}
+			printPlan(changes)
+			if dryRun {
+				fmt.Println("\nDry run: no changes made")
+				return nil
+			}
+
+			deletes := 0
+			for _, c := range changes {
+				if c.Action == manifest.ActionDelete {
+					deletes++
+				}
+			}
+			if deletes > 0 && !force {
+				fmt.Printf("\nDelete %d endpoint(s)? This cannot be undone. [y/N] ", deletes)
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				answer = strings.TrimSpace(strings.ToLower(answer))
+				if answer != "y" && answer != "yes" {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+
+			fmt.Println()
+			for _, c := range changes {
+				if err := applyChange(ctx, client, c); err != nil {
+					return fmt.Errorf("failed to %s %s: %w", c.Action, c.Slug, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Manifest file to apply (- for stdin)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without making them")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete owned endpoints that aren't in the manifest")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the confirmation prompt for --prune")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// printPlan prints the changes apply is about to make.
+func printPlan(changes []manifest.Change) {
+	var creates, updates, deletes int
+	for _, c := range changes {
+		switch c.Action {
+		case manifest.ActionCreate:
+			creates++
+			fmt.Printf("+ %s (create)\n", c.Slug)
+		case manifest.ActionUpdate:
+			updates++
+			fmt.Printf("~ %s (update)\n", c.Slug)
+		case manifest.ActionDelete:
+			deletes++
+			fmt.Printf("- %s (delete)\n", c.Slug)
+		}
+		for _, f := range c.Fields {
+			switch {
+			case f.From == "":
+				fmt.Printf("    %s: %s\n", f.Field, f.To)
+			case f.From == f.To:
+				fmt.Printf("    %s: changed (%s)\n", f.Field, f.To)
+			default:
+				fmt.Printf("    %s: %s -> %s\n", f.Field, f.From, f.To)
+			}
+		}
+	}
+	fmt.Printf("\n%d to create, %d to update, %d to delete\n", creates, updates, deletes)
+}
+
+// applyChange makes one planned change.
+func applyChange(ctx context.Context, client *api.Client, c manifest.Change) error {
+	e := c.Endpoint
+
+	switch c.Action {
+	case manifest.ActionCreate:
+		params := api.CreateEndpointParams{Name: e.Name, Slug: e.Slug, MockResponse: e.Mock}
+		if ttl := e.TTLDuration(); ttl > 0 {
+			params.ExpiresAt = time.Now().Add(ttl).UnixMilli()
+		}
+		if _, err := client.CreateEndpointWithParams(ctx, params); err != nil {
+			return err
+		}
+		if len(e.Routes) > 0 {
+			if err := client.SetEndpointRoutes(ctx, e.Slug, e.Routes); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Created %s\n", c.Slug)
+	case manifest.ActionUpdate:
+		if c.Changes(manifest.FieldName) {
+			if err := client.SetEndpointName(ctx, e.Slug, e.DisplayName()); err != nil {
+				return err
+			}
+		}
+		if c.Changes(manifest.FieldMock) {
+			if err := client.SetEndpointMockResponse(ctx, e.Slug, e.Mock); err != nil {
+				return err
+			}
+		}
+		if c.Changes(manifest.FieldRoutes) {
+			if err := client.SetEndpointRoutes(ctx, e.Slug, e.Routes); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Updated %s\n", c.Slug)
+	case manifest.ActionDelete:
+		if err := client.DeleteEndpointWithContext(ctx, c.Slug); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", c.Slug)
+	}
+	return nil
+}
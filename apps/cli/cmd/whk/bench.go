@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/bench"
+)
+
+// --- Bench command ---
+
+func benchCmd() *cobra.Command {
+	var (
+		rps         int
+		duration    string
+		bodyFile    string
+		method      string
+		headers     []string
+		maxInFlight int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench <slug|url>",
+		Short: "Load-test an endpoint or a local webhook handler",
+		Long: `Send webhooks at a fixed rate and report latency percentiles and error
+rates. The target is an endpoint slug (load-tests webhooks.cc; every request
+counts towards your quota) or a URL such as http://localhost:3000/webhook.
+
+Without --body-file, each request carries a synthetic JSON event with a
+unique ID.
+
+Example:
+  whk bench http://localhost:3000/webhook --rps 200 --duration 30s --body-file payload.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := parseDuration(duration)
+			if err != nil {
+				return err
+			}
+
+			target := args[0]
+			if !strings.Contains(target, "://") {
+				slug, err := validateSlug(target)
+				if err != nil {
+					return err
+				}
+				target = api.NewClient().WebhookURL() + "/w/" + slug
+			}
+
+			cfg := bench.Config{
+				TargetURL:   target,
+				Method:      strings.ToUpper(method),
+				RPS:         rps,
+				Duration:    d,
+				MaxInFlight: maxInFlight,
+				Headers:     http.Header{},
+			}
+			for k, v := range parseHeaders(headers) {
+				cfg.Headers.Set(k, v)
+			}
+			if bodyFile != "" {
+				cfg.Body, err = os.ReadFile(bodyFile)
+				if err != nil {
+					return fmt.Errorf("failed to read body file: %w", err)
+				}
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			fmt.Printf("Sending %d req/s to %s for %s (Ctrl+C to stop early)\n", rps, target, d)
+			report, err := bench.Run(ctx, cfg)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+
+			printBenchReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&rps, "rps", 10, "Requests started per second")
+	cmd.Flags().StringVar(&duration, "duration", "10s", "How long to send requests, e.g. 30s or 5m")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "File to send as the request body (default: synthetic JSON event)")
+	cmd.Flags().StringVarP(&method, "method", "X", "POST", "HTTP method")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Add a header to every request (repeatable, format: Key:Value)")
+	cmd.Flags().IntVar(&maxInFlight, "max-in-flight", 0, "Maximum concurrent requests (default: 4x --rps)")
+
+	return cmd
+}
+
+func printBenchReport(r *bench.Report) {
+	fmt.Println()
+	fmt.Printf("Requests:   %d sent in %s (%.1f req/s)\n", r.Sent, r.Elapsed.Round(time.Millisecond), float64(r.Sent)/r.Elapsed.Seconds())
+	fmt.Printf("Succeeded:  %d (%.1f%% errors)\n", r.Succeeded(), r.ErrorRate()*100)
+	if r.Errors > 0 {
+		fmt.Printf("Failed:     %d (connection errors or timeouts)\n", r.Errors)
+	}
+	if r.Skipped > 0 {
+		fmt.Printf("Skipped:    %d (too many requests in flight; target can't keep up)\n", r.Skipped)
+	}
+
+	if len(r.Statuses) > 0 {
+		codes := make([]int, 0, len(r.Statuses))
+		for code := range r.Statuses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		parts := make([]string, len(codes))
+		for i, code := range codes {
+			parts[i] = fmt.Sprintf("%d x%d", code, r.Statuses[code])
+		}
+		fmt.Printf("Statuses:   %s\n", strings.Join(parts, ", "))
+	}
+
+	fmt.Printf("Latency:    p50 %s  p90 %s  p99 %s  max %s\n",
+		r.P50.Round(time.Microsecond*100), r.P90.Round(time.Microsecond*100),
+		r.P99.Round(time.Microsecond*100), r.Max.Round(time.Microsecond*100))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/stream"
+)
+
+// issuesURL is where crash reports should be filed
+const issuesURL = "https://github.com/kroqdotdev/webhooks-cc/issues/new"
+
+// recoverCrash turns a panic in a command into a crash report saved to a
+// temp file and a short message, instead of a raw stack trace. It must be
+// deferred directly in main. WHK_DEBUG also prints the stack to stderr.
+//
+// Only panics on the main goroutine are caught. Stream handlers, which do
+// the work of listen and tunnel, are covered because the stream re-raises
+// their panics on the caller's goroutine (see stream.HandlerPanic), and
+// net/http recovers panics in the inspector's handlers. The other
+// background goroutines (signal handlers, forward reports, the TUI's
+// stream) crash with a raw stack trace.
+func recoverCrash(root *cobra.Command) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	if p, ok := r.(*stream.HandlerPanic); ok {
+		r, stack = p.Value, p.Stack
+	}
+	report := crashReport(root, r, stack)
+
+	path, err := writeCrashReport(report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "whk crashed unexpectedly and the crash report could not be saved (%v):\n\n%s\n", err, report)
+		fmt.Fprintf(os.Stderr, "Please report this at %s\n", issuesURL)
+		os.Exit(exitError)
+	}
+	if os.Getenv("WHK_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n", r, stack)
+	}
+	fmt.Fprintf(os.Stderr, "whk crashed unexpectedly: %v\n", r)
+	fmt.Fprintf(os.Stderr, "A crash report was saved to %s\n", path)
+	fmt.Fprintf(os.Stderr, "Please open an issue at %s and attach it.\n", issuesURL)
+	os.Exit(exitError)
+}
+
+// crashReport describes the crash and the environment it happened in. It
+// leaves out anything secret: argument and flag values, the token and the
+// account's email.
+func crashReport(root *cobra.Command, r any, stack []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "whk crash report\n\n")
+	fmt.Fprintf(&b, "Time:     %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version:  %s\n", version)
+	fmt.Fprintf(&b, "Go:       %s\n", runtime.Version())
+	fmt.Fprintf(&b, "OS:       %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	command, flags := "whk", "none"
+	if cmd, _, err := root.Find(os.Args[1:]); err == nil {
+		command = cmd.CommandPath()
+		var names []string
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			names = append(names, "--"+f.Name)
+		})
+		if len(names) > 0 {
+			flags = strings.Join(names, " ")
+		}
+	}
+	fmt.Fprintf(&b, "Command:  %s\n", command)
+	fmt.Fprintf(&b, "Flags:    %s\n", flags)
+
+	fmt.Fprintf(&b, "\nConfig:\n")
+	fmt.Fprintf(&b, "  API URL:      %s\n", api.NewClient().BaseURL())
+	fmt.Fprintf(&b, "  Webhook URL:  %s\n", valueOr(os.Getenv("WHK_WEBHOOK_URL"), "default"))
+	fmt.Fprintf(&b, "  WHK_NOGUI:    %s\n", valueOr(os.Getenv("WHK_NOGUI"), "unset"))
+	fmt.Fprintf(&b, "  WHK_DEBUG:    %s\n", valueOr(os.Getenv("WHK_DEBUG"), "unset"))
+	switch tok, err := auth.LoadToken(); {
+	case err != nil:
+		fmt.Fprintf(&b, "  Logged in:    no (%v)\n", err)
+	case len(tok.Scopes) > 0:
+		fmt.Fprintf(&b, "  Logged in:    yes, scopes %s\n", strings.Join(tok.Scopes, ", "))
+	default:
+		fmt.Fprintf(&b, "  Logged in:    yes\n")
+	}
+
+	fmt.Fprintf(&b, "\npanic: %v\n\n%s", r, stack)
+	return b.String()
+}
+
+func writeCrashReport(report string) (string, error) {
+	f, err := os.CreateTemp("", "whk-crash-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(report); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
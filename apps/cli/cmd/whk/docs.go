@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// --- Documentation generation ---
+
+func docsCmd() *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "docs <man|markdown>",
+		Short: "Generate man pages or a Markdown command reference",
+		Long: `Generate documentation for every whk command from the CLI itself:
+man pages (section 1) for packages, or one Markdown file per command for
+the website's command reference.
+
+Examples:
+  whk docs man --out ./man
+  whk docs markdown --out ./docs/cli`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"man", "markdown"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(out, 0755); err != nil {
+				return err
+			}
+
+			root := cmd.Root()
+			// Keep generated files stable between runs
+			root.DisableAutoGenTag = true
+
+			switch args[0] {
+			case "man":
+				header := &doc.GenManHeader{
+					Title:   "WHK",
+					Section: "1",
+					Source:  "whk " + version,
+					Manual:  "webhooks.cc Manual",
+				}
+				if err := doc.GenManTree(root, header, out); err != nil {
+					return err
+				}
+			case "markdown":
+				if err := doc.GenMarkdownTree(root, out); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown format: %s (must be man or markdown)", args[0])
+			}
+
+			fmt.Printf("Generated %s docs in %s\n", args[0], out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&out, "out", "o", ".", "Directory to write the files to")
+
+	return cmd
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/shared/pathpattern"
+	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
+)
+
+// --- Endpoint settings commands ---
+
+func endpointCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "endpoint",
+		Short: "Manage endpoint settings",
+	}
+
+	cmd.AddCommand(endpointRetentionCmd())
+	cmd.AddCommand(endpointAliasCmd())
+	cmd.AddCommand(endpointPathsCmd())
+	cmd.AddCommand(endpointRoutesCmd())
+
+	return cmd
+}
+
+func endpointRetentionCmd() *cobra.Command {
+	var (
+		keep  string
+		reset bool
+		purge bool
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retention <slug>",
+		Short: "Show or change how long captured requests are kept",
+		Long: `Show or change an endpoint's capture retention.
+
+--keep takes either an age (e.g. 24h, 7d) or a request count (e.g. 1000).
+Plan limits still apply. --purge deletes all stored requests immediately.
+
+Examples:
+  whk endpoint retention my-endpoint
+  whk endpoint retention my-endpoint --keep 7d
+  whk endpoint retention my-endpoint --keep 1000
+  whk endpoint retention my-endpoint --purge`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			if keep != "" && reset {
+				return fmt.Errorf("--keep and --reset cannot be used together")
+			}
+
+			client := api.NewClient()
+			ctx := cmd.Context()
+
+			switch {
+			case keep != "":
+				retention, err := parseRetention(keep)
+				if err != nil {
+					return err
+				}
+				if err := client.SetEndpointRetention(ctx, slug, retention); err != nil {
+					return err
+				}
+				fmt.Printf("Retention for %s: %s\n", slug, formatRetention(retention))
+			case reset:
+				if err := client.SetEndpointRetention(ctx, slug, nil); err != nil {
+					return err
+				}
+				fmt.Printf("Retention for %s: %s\n", slug, formatRetention(nil))
+			case !purge:
+				endpoint, err := client.GetEndpoint(ctx, slug)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Retention for %s: %s\n", slug, formatRetention(endpoint.Retention))
+			}
+
+			if !purge {
+				return nil
+			}
+
+			if !force {
+				fmt.Printf("Delete all captured requests for '%s'? This cannot be undone. [y/N] ", slug)
+				reader := bufio.NewReader(os.Stdin)
+				answer, _ := reader.ReadString('\n')
+				answer = strings.TrimSpace(strings.ToLower(answer))
+				if answer != "y" && answer != "yes" {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+
+			if err := client.PurgeRequests(ctx, slug); err != nil {
+				return err
+			}
+			fmt.Printf("All captured requests for '%s' deleted\n", slug)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keep, "keep", "", "Keep requests for an age (e.g. 7d) or up to a count (e.g. 1000)")
+	cmd.Flags().BoolVar(&reset, "reset", false, "Restore the plan's default retention")
+	cmd.Flags().BoolVar(&purge, "purge", false, "Delete all stored requests for the endpoint now")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip the --purge confirmation prompt")
+
+	return cmd
+}
+
+// parseRetention parses a --keep value: a plain integer is a request count,
+// anything else is an age accepted by parseDuration.
+func parseRetention(s string) (*api.Retention, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid retention count: %s", s)
+		}
+		return &api.Retention{MaxCount: n}, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --keep value %q (use an age like 7d or a count like 1000)", s)
+	}
+	return &api.Retention{MaxAgeMs: d.Milliseconds()}, nil
+}
+
+// formatRetention describes a retention policy for display.
+func formatRetention(r *api.Retention) string {
+	if r == nil || (r.MaxAgeMs == 0 && r.MaxCount == 0) {
+		return "plan default"
+	}
+	var parts []string
+	if r.MaxAgeMs > 0 {
+		d := time.Duration(r.MaxAgeMs) * time.Millisecond
+		if d%(24*time.Hour) == 0 {
+			parts = append(parts, fmt.Sprintf("%d days", d/(24*time.Hour)))
+		} else {
+			parts = append(parts, d.String())
+		}
+	}
+	if r.MaxCount > 0 {
+		parts = append(parts, fmt.Sprintf("newest %d requests", r.MaxCount))
+	}
+	return "keep " + strings.Join(parts, ", ")
+}
+
+func endpointAliasCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "alias <slug> [alias]",
+		Short: "List, add, or remove an endpoint's alias slugs",
+		Long: `List, add, or remove alias slugs for an endpoint. Requests sent to
+/w/<alias> are captured by the endpoint just like requests to its slug.
+
+Examples:
+  whk endpoint alias my-endpoint
+  whk endpoint alias my-endpoint my-stripe-dev
+  whk endpoint alias my-endpoint my-stripe-dev --remove`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			if remove && len(args) < 2 {
+				return fmt.Errorf("--remove needs the alias to remove")
+			}
+
+			client := api.NewClient()
+			ctx := cmd.Context()
+
+			if len(args) == 1 {
+				endpoint, err := client.GetEndpoint(ctx, slug)
+				if err != nil {
+					return err
+				}
+				if len(endpoint.Aliases) == 0 {
+					fmt.Printf("%s has no aliases\n", slug)
+					return nil
+				}
+				for _, alias := range endpoint.Aliases {
+					fmt.Printf("%s/w/%s\n", client.WebhookURL(), alias)
+				}
+				return nil
+			}
+
+			alias, err := validateSlug(args[1])
+			if err != nil {
+				return err
+			}
+			if remove {
+				if err := client.RemoveEndpointAlias(ctx, slug, alias); err != nil {
+					return err
+				}
+				fmt.Printf("Alias %s removed from %s\n", alias, slug)
+				return nil
+			}
+
+			if err := client.AddEndpointAlias(ctx, slug, alias); err != nil {
+				return err
+			}
+			fmt.Printf("Alias added: %s/w/%s -> %s\n", client.WebhookURL(), alias, slug)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the alias instead of adding it")
+
+	return cmd
+}
+
+func endpointPathsCmd() *cobra.Command {
+	var (
+		add    []string
+		remove []string
+		reset  bool
+		test   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "paths <slug>",
+		Short: "Show or change the path patterns that extract path parameters",
+		Long: `Show or change an endpoint's path patterns. When a captured request's
+path matches a pattern, its named segments are stored as path parameters,
+which you can search with param.<name>:value.
+
+:name matches one path segment and a final *name matches the rest of the
+path. Patterns are tried in order and the first match wins.
+
+Examples:
+  whk endpoint paths my-endpoint
+  whk endpoint paths my-endpoint --add /orders/:id/events
+  whk endpoint paths my-endpoint --add /files/*path --remove /files/:name
+  whk endpoint paths my-endpoint --test /orders/42/events
+  whk endpoint paths my-endpoint --clear`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			if reset && (len(add) > 0 || len(remove) > 0) {
+				return fmt.Errorf("--clear cannot be used with --add or --remove")
+			}
+			for _, p := range add {
+				if _, err := pathpattern.Parse(p); err != nil {
+					return err
+				}
+			}
+
+			client := api.NewClient()
+			ctx := cmd.Context()
+
+			endpoint, err := client.GetEndpoint(ctx, slug)
+			if err != nil {
+				return err
+			}
+			patterns := endpoint.PathPatterns
+
+			if reset || len(add) > 0 || len(remove) > 0 {
+				if reset {
+					patterns = nil
+				}
+				for _, p := range remove {
+					i := slices.Index(patterns, p)
+					if i < 0 {
+						return fmt.Errorf("%s has no path pattern %s", slug, p)
+					}
+					patterns = slices.Delete(patterns, i, i+1)
+				}
+				for _, p := range add {
+					if !slices.Contains(patterns, p) {
+						patterns = append(patterns, p)
+					}
+				}
+				if len(patterns) > pathpattern.MaxPatterns {
+					return fmt.Errorf("too many path patterns (max %d)", pathpattern.MaxPatterns)
+				}
+				if err := client.SetEndpointPathPatterns(ctx, slug, patterns); err != nil {
+					return err
+				}
+			}
+
+			if test != "" {
+				parsed, err := pathpattern.ParseAll(patterns)
+				if err != nil {
+					return err
+				}
+				for _, p := range parsed {
+					params, ok := p.Match(test)
+					if !ok {
+						continue
+					}
+					fmt.Printf("%s matches %s\n", test, p)
+					for _, name := range slices.Sorted(maps.Keys(params)) {
+						fmt.Printf("  %s = %s\n", name, params[name])
+					}
+					return nil
+				}
+				fmt.Printf("%s matches no path pattern of %s\n", test, slug)
+				return nil
+			}
+
+			if len(patterns) == 0 {
+				fmt.Printf("%s has no path patterns\n", slug)
+				return nil
+			}
+			fmt.Printf("Path patterns for %s:\n", slug)
+			for _, p := range patterns {
+				fmt.Printf("  %s\n", p)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&add, "add", nil, "Add a path pattern (repeatable)")
+	cmd.Flags().StringArrayVar(&remove, "remove", nil, "Remove a path pattern (repeatable)")
+	cmd.Flags().BoolVar(&reset, "clear", false, "Remove all path patterns")
+	cmd.Flags().StringVar(&test, "test", "", "Show the path parameters a request to this path would get")
+
+	return cmd
+}
+
+func endpointRoutesCmd() *cobra.Command {
+	var (
+		add     string
+		name    string
+		status  int
+		body    string
+		headers []string
+		forward string
+		tags    []string
+		remove  string
+		reset   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "routes <slug>",
+		Short: "Split an endpoint into sub-endpoints by path prefix",
+		Long: `Show or change an endpoint's routes. A route is a sub-endpoint for
+requests whose path starts with its prefix: it answers with its own mock
+response, can forward to its own URL and tags the requests it captures,
+so a provider that posts to many paths can use one capture URL. The
+longest matching prefix wins; requests that match no route use the
+endpoint's mock response.
+
+Adding a route with an existing prefix replaces it.
+
+Examples:
+  whk endpoint routes my-endpoint
+  whk endpoint routes my-endpoint --add /stripe --status 200 --body '{"received":true}'
+  whk endpoint routes my-endpoint --add /github --forward https://staging.example.com/hooks
+  whk endpoint routes my-endpoint --add /shopify --tag shopify --tag env:staging
+  whk endpoint routes my-endpoint --remove /stripe`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			n := 0
+			for _, b := range []bool{add != "", remove != "", reset} {
+				if b {
+					n++
+				}
+			}
+			if n > 1 {
+				return fmt.Errorf("--add, --remove and --clear cannot be used together")
+			}
+			routeFlags := cmd.Flags().Changed("status") || body != "" || len(headers) > 0 || forward != "" || name != "" || len(tags) > 0
+			if routeFlags && add == "" {
+				return fmt.Errorf("--name, --status, --body, --header, --forward and --tag need --add")
+			}
+
+			var route types.EndpointRoute
+			if add != "" {
+				route, err = buildRoute(add, name, cmd.Flags().Changed("status"), status, body, headers, forward, tags)
+				if err != nil {
+					return err
+				}
+			}
+
+			client := api.NewClient()
+			ctx := cmd.Context()
+
+			endpoint, err := client.GetEndpoint(ctx, slug)
+			if err != nil {
+				return err
+			}
+			routes := endpoint.Routes
+
+			switch {
+			case add != "":
+				i := slices.IndexFunc(routes, func(r types.EndpointRoute) bool { return r.Prefix == route.Prefix })
+				if i >= 0 {
+					routes[i] = route
+				} else {
+					routes = append(routes, route)
+				}
+				if len(routes) > validation.MaxRoutes {
+					return fmt.Errorf("too many routes (max %d)", validation.MaxRoutes)
+				}
+			case remove != "":
+				i := slices.IndexFunc(routes, func(r types.EndpointRoute) bool { return r.Prefix == remove })
+				if i < 0 {
+					return fmt.Errorf("%s has no route %s", slug, remove)
+				}
+				routes = slices.Delete(routes, i, i+1)
+			case reset:
+				routes = nil
+			}
+
+			if n > 0 {
+				if err := client.SetEndpointRoutes(ctx, slug, routes); err != nil {
+					return err
+				}
+			}
+
+			if len(routes) == 0 {
+				fmt.Printf("%s has no routes\n", slug)
+				return nil
+			}
+			for _, r := range routes {
+				fmt.Printf("%s/w/%s%s  %s\n", client.WebhookURL(), slug, strings.TrimSuffix(r.Prefix, "/"), describeRoute(r))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&add, "add", "", "Add or replace the route for a path prefix (e.g. /stripe)")
+	cmd.Flags().StringVar(&name, "name", "", "Display name for the route (with --add)")
+	cmd.Flags().IntVar(&status, "status", 200, "Mock response status (with --add)")
+	cmd.Flags().StringVar(&body, "body", "", "Mock response body (with --add)")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Mock response header, Key:Value (with --add, repeatable)")
+	cmd.Flags().StringVar(&forward, "forward", "", "Forward the route's requests to this URL (with --add)")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Tag the route's captured requests (with --add, repeatable)")
+	cmd.Flags().StringVar(&remove, "remove", "", "Remove the route for a path prefix")
+	cmd.Flags().BoolVar(&reset, "clear", false, "Remove all routes")
+
+	return cmd
+}
+
+// buildRoute validates the --add flags of endpoint routes. The route only
+// overrides the endpoint's mock response if a status, body or header is given.
+func buildRoute(prefix, name string, hasStatus bool, status int, body string, headers []string, forward string, tags []string) (types.EndpointRoute, error) {
+	route := types.EndpointRoute{Prefix: prefix, Name: name}
+	if !validation.IsValidRoutePrefix(prefix) {
+		return route, fmt.Errorf("invalid route prefix: %q (a path starting with /, without ?, # or *)", prefix)
+	}
+	if hasStatus || body != "" || len(headers) > 0 {
+		if !validation.IsValidStatus(status) {
+			return route, fmt.Errorf("invalid status: %d", status)
+		}
+		mock := &types.MockResponse{Status: status, Body: body, Headers: parseHeaders(headers)}
+		for k, v := range mock.Headers {
+			if !validation.IsSafeResponseHeader(k, v) {
+				return route, fmt.Errorf("header %s cannot be sent in a mock response", k)
+			}
+		}
+		route.MockResponse = mock
+	}
+	if forward != "" {
+		u, err := url.Parse(forward)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return route, fmt.Errorf("invalid --forward URL: %s (must be http or https)", forward)
+		}
+		route.ForwardURL = forward
+	}
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return route, err
+	}
+	route.Tags = tags
+	return route, nil
+}
+
+// describeRoute summarizes what a route does for the routes listing.
+func describeRoute(r types.EndpointRoute) string {
+	var parts []string
+	if r.Name != "" {
+		parts = append(parts, r.Name+":")
+	}
+	if r.MockResponse != nil {
+		parts = append(parts, fmt.Sprintf("responds %d", r.MockResponse.Status))
+	} else {
+		parts = append(parts, "endpoint mock response")
+	}
+	if r.ForwardURL != "" {
+		parts = append(parts, "-> "+r.ForwardURL)
+	}
+	if len(r.Tags) > 0 {
+		parts = append(parts, "["+strings.Join(r.Tags, ", ")+"]")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/filters"
+	"webhooks.cc/cli/internal/stream"
+)
+
+// Exit codes, so scripts wrapping whk can branch on the kind of failure
+// instead of parsing stderr. Keep them in sync with the "Exit codes"
+// section of the CLI docs.
+const (
+	// exitError covers usage errors and failures not classified below
+	exitError = 1
+	// exitAuth means not logged in, an expired session, or a missing permission
+	exitAuth = 2
+	// exitNetwork means the API or receiver could not be reached
+	exitNetwork = 3
+	// exitNotFound means the endpoint, request or other resource doesn't exist
+	exitNotFound = 4
+	// exitQuota means a plan limit or rate limit was hit
+	exitQuota = 5
+)
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	if errors.Is(err, auth.ErrNotLoggedIn) || errors.Is(err, auth.ErrSessionExpired) {
+		return exitAuth
+	}
+	if errors.Is(err, stream.ErrEndpointDeleted) || errors.Is(err, filters.ErrNotFound) {
+		return exitNotFound
+	}
+
+	status := 0
+	var apiErr *api.APIError
+	var statusErr *stream.StatusError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.StatusCode
+	case errors.As(err, &statusErr):
+		status = statusErr.Code
+	}
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return exitAuth
+	case http.StatusNotFound, http.StatusGone:
+		return exitNotFound
+	case http.StatusPaymentRequired, http.StatusTooManyRequests:
+		return exitQuota
+	}
+
+	// url.Error also reports malformed URLs; only failed requests are
+	// network errors
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.Op != "parse" {
+		return exitNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return exitNetwork
+	}
+	return exitError
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/shared/types"
+)
+
+// expectSinceLimit bounds how many already captured requests --since checks.
+const expectSinceLimit = 100
+
+// --- Expect command ---
+
+func expectCmd() *cobra.Command {
+	var (
+		expr       string
+		filterName string
+		count      int
+		timeout    string
+		since      string
+		quiet      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "expect <slug>",
+		Short: "Wait for matching requests and fail if they don't arrive",
+		Long: `Wait until an endpoint captures requests matching a search query, for
+end-to-end tests in CI. Exits 0 once --count matching requests have
+arrived and 1 if the timeout passes first.
+
+Requests captured before whk expect starts are not counted unless --since
+is set, so start it before triggering the webhook or use --since to cover
+the gap.
+
+Examples:
+  whk expect my-endpoint --match 'method:POST body:"order_123"'
+  whk expect my-endpoint --match 'header.x-event-type:invoice.paid' --count 2 --timeout 2m
+  whk expect my-endpoint --filter stripe-paid --since 5m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			q, err := resolveQuery(expr, filterName)
+			if err != nil {
+				return err
+			}
+			if count < 1 {
+				return fmt.Errorf("--count must be at least 1")
+			}
+			wait, err := parseDuration(timeout)
+			if err != nil || wait <= 0 {
+				return fmt.Errorf("invalid --timeout: %s", timeout)
+			}
+			var lookback time.Duration
+			if since != "" {
+				lookback, err = parseDuration(since)
+				if err != nil || lookback <= 0 {
+					return fmt.Errorf("invalid --since: %s", since)
+				}
+			}
+
+			// From here on failures are assertion results, not usage errors
+			cmd.SilenceUsage = true
+
+			token, err := auth.LoadToken()
+			if err != nil {
+				return fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
+			}
+			client := api.NewClient()
+
+			sigCtx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			ctx, cancel := context.WithTimeout(sigCtx, wait)
+			defer cancel()
+
+			if !quiet {
+				desc := "any request"
+				if !q.Empty() {
+					desc = q.String()
+				}
+				fmt.Printf("Waiting up to %s for %d request(s) matching: %s\n", wait, count, desc)
+			}
+
+			seen := map[string]bool{}
+			matched := 0
+			// record counts a matching request and reports whether enough
+			// have arrived
+			record := func(req *types.CapturedRequest) bool {
+				if seen[req.ID] || !q.Match(req) {
+					return false
+				}
+				seen[req.ID] = true
+				matched++
+				if !quiet {
+					fmt.Printf("  %s\n", stream.FormatRequest(req))
+				}
+				return matched >= count
+			}
+
+			if lookback > 0 {
+				reqs, err := client.ListRequests(ctx, slug, api.ListRequestsParams{Limit: expectSinceLimit, Query: q.String()})
+				if err != nil {
+					return err
+				}
+				cutoff := time.Now().Add(-lookback).UnixMilli()
+				// Newest first from the API; count oldest first
+				for i := len(reqs) - 1; i >= 0; i-- {
+					if reqs[i].ReceivedAt >= cutoff && record(&reqs[i]) {
+						return expectDone(quiet, matched)
+					}
+				}
+			}
+
+			s := stream.New(slug, client.BaseURL(), token.AccessToken)
+			err = s.Listen(ctx, func(req *types.CapturedRequest) {
+				if record(req) {
+					cancel()
+				}
+			})
+			if matched >= count {
+				return expectDone(quiet, matched)
+			}
+			if errors.Is(err, stream.ErrEndpointDeleted) {
+				return fmt.Errorf("%w while waiting", stream.ErrEndpointDeleted)
+			}
+			if sigCtx.Err() != nil {
+				return fmt.Errorf("interrupted: saw %d of %d matching request(s)", matched, count)
+			}
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s: saw %d of %d matching request(s)", wait, matched, count)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&expr, "match", "q", "", "Search query the requests must match (default: any request)")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Saved filter the requests must match (see 'whk filter')")
+	cmd.Flags().IntVar(&count, "count", 1, "Number of matching requests to wait for")
+	cmd.Flags().StringVar(&timeout, "timeout", "60s", "How long to wait before failing")
+	cmd.Flags().StringVar(&since, "since", "", "Also count requests captured this long before starting (e.g. 5m)")
+	cmd.Flags().BoolVar(&quiet, "quiet", false, "Only print errors")
+
+	return cmd
+}
+
+func expectDone(quiet bool, matched int) error {
+	if !quiet {
+		fmt.Printf("Received %d matching request(s)\n", matched)
+	}
+	return nil
+}
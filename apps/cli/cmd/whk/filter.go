@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/filters"
+	"webhooks.cc/shared/search"
+)
+
+// --- Saved filter commands ---
+
+func filterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter",
+		Short: "Manage saved search filters",
+	}
+
+	cmd.AddCommand(filterSaveCmd())
+	cmd.AddCommand(filterListCmd())
+	cmd.AddCommand(filterDeleteCmd())
+
+	return cmd
+}
+
+func filterSaveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "save <name> <query>",
+		Short: "Save a search query under a name",
+		Long: `Save a search query (see 'whk requests list --help') under a name, replacing
+any filter with the same name. Saved filters can be used with --filter on
+'whk listen' and 'whk requests list', and picked in the TUI search bar.
+
+Example:
+  whk filter save failed-stripe 'method:POST header.stripe-signature:* status:>=400'
+  whk listen my-endpoint --filter failed-stripe`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := filters.Save(args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Saved filter %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func filterListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved filters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			saved, err := filters.List()
+			if err != nil {
+				return err
+			}
+
+			if len(saved) == 0 {
+				fmt.Println("No saved filters")
+				fmt.Println("Run 'whk filter save <name> <query>' to create one")
+				return nil
+			}
+
+			fmt.Printf("%-24s %s\n", "NAME", "QUERY")
+			fmt.Printf("%-24s %s\n", "----", "-----")
+			for _, f := range saved {
+				fmt.Printf("%-24s %s\n", f.Name, f.Query)
+			}
+			return nil
+		},
+	}
+}
+
+func filterDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved filter",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := filters.Delete(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted filter %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// resolveQuery combines an inline --query expression with a saved --filter.
+// Either may be empty; the result matches requests satisfying both.
+func resolveQuery(expr, filterName string) (*search.Query, error) {
+	q, err := search.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if filterName != "" {
+		saved, err := filters.Load(filterName)
+		if err != nil {
+			return nil, err
+		}
+		q.Terms = append(q.Terms, saved.Terms...)
+	}
+	return q, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/filters"
+	"webhooks.cc/cli/internal/project"
+)
+
+// --- Project config command ---
+
+func initCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a " + project.FileName + " project config for whk tunnel",
+		Long: `Interactively create a ` + project.FileName + ` file in the current directory.
+Running "whk tunnel" without a port anywhere in the project then uses
+these settings. Edit the file to add headers or change them later.
+
+To save settings non-interactively, use "whk tunnel <port> [flags] --init".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(project.FileName); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", project.FileName)
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			ask := func(prompt, def string) string {
+				if def != "" {
+					fmt.Printf("%s [%s]: ", prompt, def)
+				} else {
+					fmt.Printf("%s: ", prompt)
+				}
+				answer, _ := reader.ReadString('\n')
+				answer = strings.TrimSpace(answer)
+				if answer == "" {
+					return def
+				}
+				return answer
+			}
+
+			cfg := &project.Config{}
+			cfg.Target = ask("Local port and optional path, e.g. 3000/api/webhooks", "3000")
+			cfg.Endpoint = ask("Existing endpoint slug (leave empty to create one per run)", "")
+			if cfg.Endpoint == "" {
+				answer := strings.ToLower(ask("Delete the created endpoint on exit? [y/N]", ""))
+				cfg.Ephemeral = answer == "y" || answer == "yes"
+			}
+			if saved, err := filters.List(); err == nil && len(saved) > 0 {
+				names := make([]string, len(saved))
+				for i, f := range saved {
+					names[i] = f.Name
+				}
+				fmt.Printf("Saved filters: %s\n", strings.Join(names, ", "))
+				cfg.Filter = ask("Only forward requests matching a saved filter (leave empty for all)", "")
+			}
+
+			return writeProjectConfig(cfg, force)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite an existing "+project.FileName)
+
+	return cmd
+}
+
+// writeProjectConfig validates cfg and writes it to the current directory.
+func writeProjectConfig(cfg *project.Config, force bool) error {
+	if _, err := parseTunnelTarget(cfg.Target); err != nil {
+		return err
+	}
+	if cfg.Endpoint != "" {
+		slug, err := validateSlug(cfg.Endpoint)
+		if err != nil {
+			return err
+		}
+		cfg.Endpoint = slug
+	}
+	if _, err := resolveQuery(cfg.Query, cfg.Filter); err != nil && !errors.Is(err, filters.ErrNotFound) {
+		return err
+	}
+
+	if _, err := os.Stat(project.FileName); err == nil && !force {
+		return fmt.Errorf("%s already exists (edit it, or remove it first)", project.FileName)
+	}
+	if err := project.Write(project.FileName, cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s. Run 'whk tunnel' in this project to use it.\n", project.FileName)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"webhooks.cc/cli/internal/intercept"
+	"webhooks.cc/shared/types"
+)
+
+// interceptor implements `whk tunnel --intercept`: each request is held
+// until the user forwards, edits or drops it.
+type interceptor struct {
+	stdin *bufio.Reader
+	// off is set once the user chose to forward everything
+	off bool
+}
+
+func newInterceptor() *interceptor {
+	return &interceptor{stdin: bufio.NewReader(os.Stdin)}
+}
+
+// review asks what to do with req and returns the request to forward,
+// possibly with an edited body, or nil to drop it.
+func (i *interceptor) review(ctx context.Context, req *types.CapturedRequest) *types.CapturedRequest {
+	if i.off {
+		return req
+	}
+	for {
+		fmt.Print("    [f]orward  [e]dit body  [d]rop  forward [a]ll > ")
+		answer, ok := i.readLine(ctx)
+		if !ok {
+			fmt.Println()
+			return nil
+		}
+		switch strings.ToLower(answer) {
+		case "", "f":
+			return req
+		case "a":
+			i.off = true
+			fmt.Println("    Intercept off: forwarding every request")
+			return req
+		case "d":
+			return nil
+		case "e":
+			edited, err := editBody(req)
+			if err != nil {
+				fmt.Printf("    Can't edit: %v\n", err)
+				continue
+			}
+			req = edited
+			fmt.Printf("    Body edited (%db)\n", req.Size)
+		}
+	}
+}
+
+// readLine reads an answer without blocking shutdown: it returns false once
+// ctx is done, or if stdin is closed.
+func (i *interceptor) readLine(ctx context.Context) (string, bool) {
+	ch := make(chan string, 1)
+	go func() {
+		line, err := i.stdin.ReadString('\n')
+		if err != nil && line == "" {
+			close(ch)
+			return
+		}
+		ch <- strings.TrimSpace(line)
+	}()
+	select {
+	case <-ctx.Done():
+		return "", false
+	case line, ok := <-ch:
+		return line, ok
+	}
+}
+
+// editBody opens req's body in the user's editor and returns the edited
+// request.
+func editBody(req *types.CapturedRequest) (*types.CapturedRequest, error) {
+	e, err := intercept.Start(req)
+	if err != nil {
+		return nil, err
+	}
+	cmd := e.Cmd()
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		e.Cancel()
+		return nil, fmt.Errorf("editor: %w", err)
+	}
+	return e.Finish()
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+)
+
+// --- API key commands ---
+
+func keysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage API keys",
+	}
+
+	cmd.AddCommand(keysListCmd())
+	cmd.AddCommand(keysCreateCmd())
+	cmd.AddCommand(keysRevokeCmd())
+
+	return cmd
+}
+
+func keysListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List your API keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := api.NewClient()
+			keys, err := client.ListAPIKeys(cmd.Context())
+			if err != nil {
+				return keysError(client, err)
+			}
+
+			if len(keys) == 0 {
+				fmt.Println("No API keys found")
+				fmt.Println("Run 'whk keys create <name>' to create one")
+				return nil
+			}
+
+			fmt.Printf("%-36s %-20s %-14s %-10s %s\n", "ID", "NAME", "PREFIX", "SCOPE", "EXPIRES")
+			fmt.Printf("%-36s %-20s %-14s %-10s %s\n", "--", "----", "------", "-----", "-------")
+			for _, k := range keys {
+				scope := api.ScopeFull
+				if len(k.Scopes) > 0 {
+					scope = strings.Join(k.Scopes, ",")
+				}
+				fmt.Printf("%-36s %-20s %-14s %-10s %s\n", k.ID, k.Name, k.KeyPrefix, scope, formatKeyDate(k.ExpiresAt))
+			}
+			return nil
+		},
+	}
+}
+
+func keysCreateCmd() *cobra.Command {
+	var (
+		scope   string
+		expires string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a new API key",
+		Long: `Create a new API key, optionally limited to a scope and lifetime.
+
+Scopes:
+  full     Full access (default)
+  read     Read-only: list endpoints and requests, stream, replay
+  capture  Capture-only: listen and tunnel on existing endpoints
+
+Example:
+  whk keys create github-ci --scope read --expires 30d`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(api.KeyScopes, scope) {
+				return fmt.Errorf("invalid scope: %s (must be one of %s)", scope, strings.Join(api.KeyScopes, ", "))
+			}
+
+			params := api.CreateAPIKeyParams{Name: args[0]}
+			if scope != api.ScopeFull {
+				params.Scopes = []string{scope}
+			}
+			if expires != "" {
+				d, err := parseDuration(expires)
+				if err != nil {
+					return err
+				}
+				params.ExpiresAt = time.Now().Add(d).UnixMilli()
+			}
+
+			client := api.NewClient()
+			key, err := client.CreateAPIKey(cmd.Context(), params)
+			if err != nil {
+				return keysError(client, err)
+			}
+			if err := checkCreatedKey(params, key); err != nil {
+				// Don't leave a key around with more access than asked for
+				if revokeErr := revokeKeyByPrefix(cmd.Context(), client, key.KeyPrefix); revokeErr != nil {
+					return fmt.Errorf("%w, and revoking it failed: %v; revoke it with 'whk keys revoke'", err, revokeErr)
+				}
+				return fmt.Errorf("%w; the key was revoked", err)
+			}
+
+			keyScope := api.ScopeFull
+			if len(key.Scopes) > 0 {
+				keyScope = strings.Join(key.Scopes, ",")
+			}
+			fmt.Printf("API key created: %s\n", key.Name)
+			fmt.Printf("Scope: %s\n", keyScope)
+			fmt.Printf("Expires: %s\n", formatKeyDate(key.ExpiresAt))
+			fmt.Println()
+			fmt.Printf("  %s\n", key.Key)
+			fmt.Println()
+			fmt.Println("Store this key now, it will not be shown again.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", api.ScopeFull, "Key scope: full, read, or capture")
+	cmd.Flags().StringVar(&expires, "expires", "", "Key lifetime (e.g. 24h, 30d); defaults to the server maximum")
+	return cmd
+}
+
+func keysRevokeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := api.NewClient()
+			if err := client.RevokeAPIKey(cmd.Context(), args[0]); err != nil {
+				return keysError(client, err)
+			}
+			fmt.Printf("API key '%s' revoked\n", args[0])
+			return nil
+		},
+	}
+}
+
+// keysError explains the 403 the API key routes answer with when called
+// with an API key, such as the token stored by whk auth login, instead of
+// a browser session.
+func keysError(client *api.Client, err error) error {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%w; API keys can only be managed with a browser session, from %s/account", err, client.BaseURL())
+	}
+	return err
+}
+
+// checkCreatedKey reports an error if the server issued a key with other
+// scopes than requested or one that outlives the requested expiry.
+func checkCreatedKey(params api.CreateAPIKeyParams, key *api.CreatedAPIKey) error {
+	want := slices.Clone(params.Scopes)
+	got := slices.Clone(key.Scopes)
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(want, got) {
+		scope := api.ScopeFull
+		if len(got) > 0 {
+			scope = strings.Join(got, ",")
+		}
+		return fmt.Errorf("the server issued a key with scope %s instead of the requested one", scope)
+	}
+	if params.ExpiresAt == 0 {
+		return nil
+	}
+	expires, err := time.Parse(time.RFC3339, key.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("the server returned no usable expiry for the key (%q)", key.ExpiresAt)
+	}
+	if expires.After(time.UnixMilli(params.ExpiresAt).Add(time.Minute)) {
+		return fmt.Errorf("the server issued a key expiring %s, after the requested expiry", expires.Local().Format("2006-01-02"))
+	}
+	return nil
+}
+
+// revokeKeyByPrefix revokes the key with the given prefix. The create
+// response has no key ID, so it is looked up in the key list.
+func revokeKeyByPrefix(ctx context.Context, client *api.Client, prefix string) error {
+	keys, err := client.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if k.KeyPrefix == prefix {
+			return client.RevokeAPIKey(ctx, k.ID)
+		}
+	}
+	return fmt.Errorf("no key with prefix %s", prefix)
+}
+
+// formatKeyDate formats an RFC 3339 timestamp from the API as a date,
+// falling back to the raw value if it cannot be parsed.
+func formatKeyDate(ts string) string {
+	if ts == "" {
+		return "never"
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02")
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/shared/types"
+)
+
+// --- Logs command ---
+
+func logsCmd() *cobra.Command {
+	var (
+		follow bool
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs <slug>",
+		Short: "Show platform events for an endpoint",
+		Long: `Show what webhooks.cc did with deliveries to an endpoint: quota denials,
+mock responses served, and forward attempts reported by 'whk tunnel
+--report'. Use it to find out why a webhook was rejected or never reached
+your handler. Size warnings report payloads approaching the body size
+limit before captures start failing.
+
+Captured requests themselves are shown by 'whk listen'.
+
+Example:
+  whk logs my-endpoint --follow`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			client := api.NewClient()
+
+			if !follow {
+				events, err := client.ListEvents(cmd.Context(), slug, limit)
+				if err != nil {
+					return err
+				}
+				if len(events) == 0 {
+					fmt.Println("No events recorded yet")
+					return nil
+				}
+				for i := range events {
+					fmt.Println(stream.FormatEvent(&events[i]))
+				}
+				return nil
+			}
+
+			token, err := auth.LoadToken()
+			if err != nil {
+				return fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			fmt.Printf("Following events for %s (Ctrl+C to stop)\n", slug)
+			fmt.Println()
+
+			s := stream.New(slug, client.BaseURL(), token.AccessToken)
+			err = s.ListenEvents(ctx, func(e *types.PlatformEvent) {
+				fmt.Println(stream.FormatEvent(e))
+			})
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			if errors.Is(err, stream.ErrEndpointDeleted) {
+				fmt.Fprintln(os.Stderr, "Endpoint was deleted")
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream new events as they happen")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Maximum number of recent events to show (default: server default)")
+
+	return cmd
+}
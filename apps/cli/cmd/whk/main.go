@@ -2,11 +2,24 @@
 // Commands:
 //   - auth: Login, logout, check authentication status
 //   - create: Create a new webhook endpoint
-//   - list: List your endpoints
+//   - list: List your endpoints, optionally refreshing with request counts
 //   - delete: Delete an endpoint by slug
+//   - endpoint: Manage endpoint settings (retention)
+//   - mock: Manage endpoint mock responses
+//   - apply: Create, update and delete endpoints to match a manifest file
 //   - tunnel: Forward webhooks to localhost
+//   - proxy: Reverse proxy to a local server that mirrors requests to an endpoint
+//   - init: Create a .whk.yaml project config for whk tunnel
 //   - listen: Stream incoming requests to terminal
+//   - expect: Wait for matching requests, for CI assertions
 //   - replay: Resend a captured request to a target URL
+//   - logs: Show platform events (rejections, mocks, forwards, size warnings) for an endpoint
+//   - bench: Load-test an endpoint or local handler
+//   - requests: List, pin, and unpin captured requests
+//   - keys: Manage API keys
+//   - filter: Save, list, and delete named search filters
+//   - tui: Open the interactive UI on a specific screen
+//   - docs: Generate man pages and a Markdown command reference
 //   - update: Self-update to the latest release
 package main
 
@@ -17,30 +30,45 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/spf13/cobra"
-	tea "github.com/charmbracelet/bubbletea"
 	"webhooks.cc/cli/internal/api"
 	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/httpcache"
+	"webhooks.cc/cli/internal/inspect"
+	"webhooks.cc/cli/internal/project"
+	"webhooks.cc/cli/internal/queue"
+	"webhooks.cc/cli/internal/resign"
+	"webhooks.cc/cli/internal/sessions"
 	"webhooks.cc/cli/internal/stream"
 	"webhooks.cc/cli/internal/tui"
-	"webhooks.cc/cli/internal/tui/screens"
 	"webhooks.cc/cli/internal/tunnel"
 	"webhooks.cc/cli/internal/update"
 	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
 )
 
 var version = "dev"
 
+// forwardReportTimeout bounds reporting one tunnel forward result
+const forwardReportTimeout = 10 * time.Second
+
 func main() {
 	var nogui bool
 
@@ -53,32 +81,11 @@ func main() {
 				return cmd.Help()
 			}
 			client := api.NewClient()
-			return tui.Run(client, version, tui.ScreenFactories{
-				Menu: func(v string) tea.Model {
-					return screens.NewMenu(v)
-				},
-				Auth: func(c *api.Client) tea.Model {
-					return screens.NewAuth(c)
-				},
-				Endpoints: func(c *api.Client, mode string) tea.Model {
-					return screens.NewEndpoints(c, mode)
-				},
-				Update: func(v string) tea.Model {
-					return screens.NewUpdate(v)
-				},
-				Listen: func(c *api.Client, slug string) tea.Model {
-					return screens.NewListen(c, slug)
-				},
-				Tunnel: func(c *api.Client) tea.Model {
-					return screens.NewTunnel(c)
-				},
-				Detail: func(req *types.CapturedRequest) tea.Model {
-					return screens.NewDetail(req)
-				},
-			})
+			return tui.Run(client, version, screenFactories())
 		},
 	}
 	rootCmd.Flags().BoolVar(&nogui, "nogui", false, "Disable TUI and show help")
+	rootCmd.PersistentFlags().BoolVar(&api.DisableCache, "no-cache", false, "Don't reuse cached API responses (or set WHK_NO_CACHE=1)")
 
 	// Auth commands
 	authCmd := &cobra.Command{
@@ -95,41 +102,120 @@ func main() {
 	listCmd := listEndpointsCmd()
 	deleteCmd := deleteEndpointCmd()
 
+	// Endpoint settings commands
+	endpointCmd := endpointCmd()
+
+	// Mock response commands
+	mockCmd := mockCmd()
+
 	// Tunnel command
 	tunnelCmd := tunnelCmd()
 
+	// Mirroring reverse proxy
+	proxyCmd := proxyCmd()
+
+	// Project config scaffolding
+	initCmd := initCmd()
+
 	// Listen command
 	listenCmd := listenCmd()
 
+	// Background watcher with desktop notifications
+	watchCmd := watchCmd()
+
+	// CI assertion command
+	expectCmd := expectCmd()
+
+	// Manifest command
+	applyCmd := applyCmd()
+
 	// Replay command
 	replayCmd := replayCmd()
 
+	// Platform event logs
+	logsCmd := logsCmd()
+
+	// TUI deep links
+	tuiCmd := tuiCmd()
+
 	// Update command
 	updateCmd := updateCmd()
 
+	// Bench command
+	benchCmd := benchCmd()
+
+	// Captured request commands
+	requestsCmd := requestsCmd()
+
+	// API key commands
+	keysCmd := keysCmd()
+
+	// Saved filter commands
+	filterCmd := filterCmd()
+
+	// Documentation generation
+	docsCmd := docsCmd()
+
 	// Add all commands
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(endpointCmd)
+	rootCmd.AddCommand(mockCmd)
+	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(tunnelCmd)
+	rootCmd.AddCommand(proxyCmd)
+	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(listenCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(expectCmd)
 	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(requestsCmd)
+	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(filterCmd)
+	rootCmd.AddCommand(docsCmd)
 
+	defer recoverCrash(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
+		if errors.Is(err, auth.ErrSessionExpired) {
+			// Streams report a rejected token as a status error, so the
+			// token may not have been marked yet
+			_ = auth.MarkExpired()
+			err = auth.ErrSessionExpired
+		}
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 // --- Auth commands ---
 
 func authLoginCmd() *cobra.Command {
-	return &cobra.Command{
+	var scope string
+
+	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log in to webhooks.cc",
+		Long: `Log in to webhooks.cc by authorizing this device in the browser.
+
+Use --scope to limit what the stored token can do, e.g. on CI machines:
+  read     View endpoints and captured requests, but change nothing
+  capture  Listen and tunnel on an existing endpoint, nothing else
+  full     Everything (default)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(api.KeyScopes, scope) {
+				return fmt.Errorf("invalid scope: %s (must be one of %s)", scope, strings.Join(api.KeyScopes, ", "))
+			}
+			var scopes []string
+			if scope != api.ScopeFull {
+				scopes = []string{scope}
+			}
+
 			client := api.NewClient()
 			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
@@ -148,13 +234,16 @@ func authLoginCmd() *cobra.Command {
 			}()
 
 			// Create device code
-			resp, err := client.CreateDeviceCode(ctx)
+			resp, err := client.CreateDeviceCode(ctx, scopes...)
 			if err != nil {
 				return fmt.Errorf("failed to start login: %w", err)
 			}
 
 			fmt.Println()
 			fmt.Printf("  Your code: %s\n", resp.UserCode)
+			if len(scopes) > 0 {
+				fmt.Printf("  Requested scope: %s\n", scope)
+			}
 			fmt.Println()
 			fmt.Printf("  Open this URL to authorize: %s\n", resp.VerificationURL)
 			fmt.Println()
@@ -188,12 +277,17 @@ func authLoginCmd() *cobra.Command {
 						if err != nil {
 							return fmt.Errorf("failed to claim token: %w", err)
 						}
+						// Never store a broader key than was asked for
+						if len(scopes) > 0 && !slices.Equal(claim.Scopes, scopes) {
+							return fmt.Errorf("the server did not apply the requested %s scope, so the issued token was not saved; revoke the \"CLI (device auth)\" key at %s/account", scope, client.BaseURL())
+						}
 
 						// Save the token
 						if err := auth.SaveToken(&auth.Token{
 							AccessToken: claim.APIKey,
 							UserID:      claim.UserID,
 							Email:       claim.Email,
+							Scopes:      claim.Scopes,
 						}); err != nil {
 							return fmt.Errorf("failed to save token: %w", err)
 						}
@@ -208,22 +302,75 @@ func authLoginCmd() *cobra.Command {
 			}
 		},
 	}
+	cmd.Flags().StringVar(&scope, "scope", api.ScopeFull, "Token scope: full, read, or capture")
+	return cmd
 }
 
 func authStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	var verbose bool
+
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show current authentication status",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Show the account the stored token belongs to. This only reads the
+local token file; --verbose also asks the server whether the token is
+still valid and prints the plan, scopes and expiry it reports, exiting
+with code 2 if the token was rejected.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			token, err := auth.LoadToken()
+			if errors.Is(err, auth.ErrSessionExpired) {
+				fmt.Println("Session expired")
+				fmt.Println("Run 'whk auth login' to log in again")
+				return nil
+			}
 			if err != nil || token.AccessToken == "" {
 				fmt.Println("Not logged in")
 				fmt.Println("Run 'whk auth login' to authenticate")
-				return
+				return nil
 			}
 			fmt.Printf("Logged in as %s\n", token.Email)
+			scope := api.ScopeFull
+			if len(token.Scopes) > 0 {
+				scope = strings.Join(token.Scopes, ",")
+			}
+			fmt.Printf("Scope: %s\n", scope)
+			if !verbose {
+				return nil
+			}
+
+			client := api.NewClient()
+			me, err := client.GetMe(cmd.Context())
+			if err != nil {
+				if errors.Is(err, auth.ErrSessionExpired) {
+					fmt.Println("Server: token rejected")
+				}
+				return err
+			}
+			fmt.Printf("Server: token valid (%s)\n", client.BaseURL())
+			if me.Plan != "" {
+				fmt.Printf("Plan: %s\n", me.Plan)
+			}
+			if len(me.Scopes) > 0 {
+				fmt.Printf("Server scopes: %s\n", strings.Join(me.Scopes, ","))
+			}
+			if me.ExpiresAt > 0 {
+				expires := time.UnixMilli(me.ExpiresAt)
+				fmt.Printf("Expires: %s (in %s)\n", expires.Format("2006-01-02 15:04"), formatDuration(time.Until(expires)))
+			}
+			if caps, err := client.Capabilities(cmd.Context()); err == nil {
+				if !caps.Supports(types.APIVersion) {
+					fmt.Printf("Warning: the server doesn't accept API version %s; run `whk update`\n", types.APIVersion)
+				}
+				if len(caps.Features) > 0 {
+					fmt.Printf("Server features: %s\n", strings.Join(caps.Features, ", "))
+				}
+			}
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Check the token with the server and show plan, scopes and expiry")
+	return cmd
 }
 
 func authLogoutCmd() *cobra.Command {
@@ -231,6 +378,10 @@ func authLogoutCmd() *cobra.Command {
 		Use:   "logout",
 		Short: "Log out of webhooks.cc",
 		Run: func(cmd *cobra.Command, args []string) {
+			// Cached responses belong to the account logging out
+			if err := httpcache.Clear(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to clear cached API responses: %v\n", err)
+			}
 			if err := auth.ClearToken(); err != nil {
 				fmt.Println("Already logged out")
 				return
@@ -243,35 +394,123 @@ func authLogoutCmd() *cobra.Command {
 // --- Endpoint commands ---
 
 func createEndpointCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		template string
+		secret   string
+		ttl      string
+		slug     string
+		noCopy   bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "create [name]",
 		Short: "Create a new endpoint",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Create a new endpoint.
+
+--template presets the endpoint for a webhook provider: a mock response with
+the status and headers the provider expects, and signature verification
+for the provider's scheme (pass the signing secret with --secret).
+
+Templates: ` + strings.Join(api.TemplateNames(), ", ") + `
+
+--ttl makes the endpoint temporary: the server deletes it once the TTL
+has passed.
+
+--slug picks the endpoint's slug instead of generating one. Add more slugs
+for the same endpoint later with 'whk endpoint alias'.
+
+In a terminal, the endpoint's URL is copied to the clipboard, ready to
+paste into the provider's dashboard. --no-copy leaves the clipboard alone.
+
+Example:
+  whk create payments --template stripe --secret whsec_...
+  whk create scratch --ttl 2h
+  whk create --slug my-stripe-dev`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := fmt.Sprintf("endpoint-%s", randomSuffix(6))
 			if len(args) > 0 {
 				name = args[0]
 			}
+			if secret != "" && template == "" {
+				return fmt.Errorf("--secret requires --template")
+			}
+
+			params := api.CreateEndpointParams{Name: name, Slug: slug}
+			if ttl != "" {
+				d, err := parseDuration(ttl)
+				if err != nil {
+					return err
+				}
+				params.ExpiresAt = time.Now().Add(d).UnixMilli()
+			}
+			if template != "" {
+				if err := api.ApplyTemplate(&params, template, secret); err != nil {
+					return err
+				}
+			}
 
 			client := api.NewClient()
-			endpoint, err := client.CreateEndpoint(name)
+			endpoint, err := client.CreateEndpointWithParams(cmd.Context(), params)
 			if err != nil {
 				return err
 			}
 
 			fmt.Printf("Endpoint created: %s\n", endpoint.Slug)
-			fmt.Printf("URL: %s/w/%s\n", client.WebhookURL(), endpoint.Slug)
+			endpointURL := fmt.Sprintf("%s/w/%s", client.WebhookURL(), endpoint.Slug)
+			fmt.Printf("URL: %s\n", endpointURL)
+			// Scripts capture the output instead; a missing clipboard
+			// tool isn't an error either, the URL is printed above
+			if !noCopy && isInteractive() && clipboard.WriteAll(endpointURL) == nil {
+				fmt.Println("Copied URL to clipboard")
+			}
+			if endpoint.ExpiresAt > 0 {
+				fmt.Printf("Expires: %s\n", time.UnixMilli(endpoint.ExpiresAt).Format("2006-01-02 15:04"))
+			}
+			if template != "" {
+				mock := params.MockResponse
+				fmt.Printf("Template: %s (responds %d, verifies %s)\n", template, mock.Status, api.EndpointTemplates[template].SignatureHeader)
+				if secret == "" {
+					fmt.Println("No --secret given: signatures are recorded but not verified")
+				}
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&template, "template", "", "Provider preset: "+strings.Join(api.TemplateNames(), ", "))
+	cmd.Flags().StringVar(&secret, "secret", "", "Provider signing secret used to verify signatures (with --template)")
+	cmd.Flags().StringVar(&ttl, "ttl", "", "Delete the endpoint server-side after this long, e.g. 2h or 7d")
+	cmd.Flags().StringVar(&slug, "slug", "", "Use this slug instead of a generated one")
+	cmd.Flags().BoolVar(&noCopy, "no-copy", false, "Don't copy the endpoint URL to the clipboard")
+
+	return cmd
 }
 
 func listEndpointsCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		watch    bool
+		interval string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List your endpoints",
+		Long: `List your endpoints.
+
+With --watch the table is redrawn every few seconds with each endpoint's
+request count and last request time, plus the account's quota usage,
+until Ctrl+C is pressed.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := api.NewClient()
+			if watch {
+				every, err := parseDuration(interval)
+				if err != nil || every < time.Second {
+					return fmt.Errorf("invalid --interval: %s (minimum 1s)", interval)
+				}
+				return watchEndpoints(cmd.Context(), client, every)
+			}
+
 			endpoints, err := client.ListEndpoints()
 			if err != nil {
 				return err
@@ -288,31 +527,144 @@ func listEndpointsCmd() *cobra.Command {
 			fmt.Printf("%-10s %-20s %-20s %s\n", "SLUG", "NAME", "TEAM", "URL")
 			fmt.Printf("%-10s %-20s %-20s %s\n", "----", "----", "----", "---")
 			for _, ep := range endpoints {
-				name := ep.Name
-				if name == "" {
-					name = "-"
-				}
-				team := "-"
-				if ep.FromTeam != nil {
-					team = ep.FromTeam.TeamName
-				} else if len(ep.SharedWith) > 0 {
-					team = "→ " + ep.SharedWith[0].TeamName
-				}
-				fmt.Printf("%-10s %-20s %-20s %s/w/%s\n", ep.Slug, name, team, webhookURL, ep.Slug)
+				fmt.Printf("%-10s %-20s %-20s %s/w/%s\n", ep.Slug, endpointName(ep), endpointTeam(ep), webhookURL, ep.Slug)
 			}
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Refresh the list with request counts and quota usage")
+	cmd.Flags().StringVar(&interval, "interval", "5s", "Refresh interval for --watch")
+
+	return cmd
+}
+
+// watchEndpoints redraws the endpoint table with request counts and quota
+// usage every interval until ctx is cancelled or the user interrupts.
+func watchEndpoints(ctx context.Context, client *api.Client, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		endpoints, err := client.ListEndpointsWithContext(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var b strings.Builder
+		now := time.Now()
+		// Clear the screen and move the cursor home before redrawing
+		b.WriteString("\033[H\033[2J")
+		fmt.Fprintf(&b, "Every %s · %s · Ctrl+C to stop\n\n", interval, now.Format("15:04:05"))
+		if err != nil {
+			// Keep watching through transient failures; the error is shown
+			// until the next successful refresh
+			fmt.Fprintf(&b, "Error: %v\n", err)
+		} else {
+			writeEndpointStats(&b, endpoints, now)
+		}
+		if usage, err := client.GetUsage(ctx); err == nil {
+			fmt.Fprintf(&b, "\n%s\n", formatUsage(usage, now))
+		}
+		fmt.Print(b.String())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeEndpointStats writes the --watch table of endpoints and their activity.
+func writeEndpointStats(b *strings.Builder, endpoints []api.Endpoint, now time.Time) {
+	if len(endpoints) == 0 {
+		b.WriteString("No endpoints found\n")
+		return
+	}
+	fmt.Fprintf(b, "%-10s %-20s %-20s %10s  %s\n", "SLUG", "NAME", "TEAM", "REQUESTS", "LAST REQUEST")
+	fmt.Fprintf(b, "%-10s %-20s %-20s %10s  %s\n", "----", "----", "----", "--------", "------------")
+	for _, ep := range endpoints {
+		last := "-"
+		if ep.LastRequestAt > 0 {
+			last = formatAgo(time.UnixMilli(ep.LastRequestAt), now)
+		}
+		fmt.Fprintf(b, "%-10s %-20s %-20s %10d  %s\n", ep.Slug, endpointName(ep), endpointTeam(ep), ep.RequestCount, last)
+	}
+}
+
+// formatUsage describes the account's quota usage in one line.
+func formatUsage(u *api.Usage, now time.Time) string {
+	percent := 0
+	if u.Limit > 0 {
+		percent = u.Used * 100 / u.Limit
+	}
+	line := fmt.Sprintf("Quota: %d / %d requests (%d%%) · %s plan", u.Used, u.Limit, percent, u.Plan)
+	if u.PeriodEnd > 0 {
+		line += " · resets in " + formatDuration(time.UnixMilli(u.PeriodEnd).Sub(now))
+	}
+	return line
+}
+
+// formatAgo formats how long before now t was, like "3m ago".
+func formatAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < time.Second {
+		return "just now"
+	}
+	return formatDuration(d) + " ago"
+}
+
+// formatDuration formats d in its largest whole unit: 45s, 3m, 5h or 2d.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func endpointName(ep api.Endpoint) string {
+	if ep.Name == "" {
+		return "-"
+	}
+	return ep.Name
+}
+
+func endpointTeam(ep api.Endpoint) string {
+	if ep.FromTeam != nil {
+		return ep.FromTeam.TeamName
+	}
+	if len(ep.SharedWith) > 0 {
+		return "→ " + ep.SharedWith[0].TeamName
+	}
+	return "-"
 }
 
 func deleteEndpointCmd() *cobra.Command {
 	var force bool
 	cmd := &cobra.Command{
-		Use:   "delete <slug>",
+		Use:   "delete [slug]",
 		Short: "Delete an endpoint",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete an endpoint. If the slug is omitted in an interactive terminal,
+pick the endpoint from a searchable list.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			slug := args[0]
+			client := api.NewClient()
+			slug, err := slugArg(cmd.Context(), client, args)
+			if errors.Is(err, errPickCancelled) {
+				fmt.Println("Cancelled")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
 
 			if !force {
 				fmt.Printf("Delete endpoint '%s'? This cannot be undone. [y/N] ", slug)
@@ -325,7 +677,6 @@ func deleteEndpointCmd() *cobra.Command {
 				}
 			}
 
-			client := api.NewClient()
 			if err := client.DeleteEndpoint(slug); err != nil {
 				return err
 			}
@@ -345,10 +696,21 @@ func tunnelCmd() *cobra.Command {
 		endpointSlug string
 		ephemeral    bool
 		headers      []string
+		headersFile  string
+		report       bool
+		expr         string
+		filterName   string
+		initConfig   bool
+		ttl          string
+		resume       bool
+		inspectAddr  string
+		useCache     bool
+		queueOffline bool
+		interceptReq bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "tunnel <port>[/path]",
+		Use:   "tunnel [<port>[/path]]",
 		Short: "Create an endpoint and forward requests to localhost",
 		Long: `Create an endpoint and forward incoming webhook requests to a local server.
 
@@ -358,29 +720,155 @@ The argument can be a port number or a port with a base path:
   whk tunnel 3000/api/polar-webhooks     # Forward to http://localhost:3000/api/polar-webhooks
 
 Incoming request paths are appended to the base path. For example, with
-"whk tunnel 8080/api", a request to /hook becomes http://localhost:8080/api/hook.`,
-		Args: cobra.ExactArgs(1),
+"whk tunnel 8080/api", a request to /hook becomes http://localhost:8080/api/hook.
+
+Settings are also read from a .whk.yaml file in the current directory or
+any parent (see 'whk init'), so a plain "whk tunnel" works inside a
+configured project. Arguments and flags override the file. Pass --init to
+save the current arguments and flags as the project's .whk.yaml.
+
+Ephemeral endpoints are remembered until the tunnel deletes them. If a
+tunnel is killed before it can, the next ephemeral tunnel offers to reuse
+or delete the leftover endpoint; --resume reuses it without asking.
+
+--inspect :4040 serves a local web page at http://localhost:4040 listing
+each forwarded request next to your server's response, updated live, with
+a button to replay a request to the local server. The page only answers
+requests addressed to localhost.
+
+--cache saves every received request locally, as with 'whk listen --cache'.
+
+--queue-offline queues requests that arrive while the local server is
+down (up to 1000, saved to disk) and delivers them in order once it is
+back, before newer requests. Requests still queued when the tunnel exits
+are delivered by the next --queue-offline tunnel for the same endpoint.
+
+--header and --headers-file (a file of "Key: Value" lines) add headers to
+every forwarded request, including credentials such as Authorization. A
+value of env:NAME is read from the NAME environment variable, so secrets
+stay out of shell history and process lists:
+  whk tunnel 3000 -H 'Authorization: env:LOCAL_API_TOKEN'
+
+--intercept holds each request and asks whether to forward it, drop it,
+or edit its body first in $VISUAL or $EDITOR. Editing a signed body
+invalidates its signature.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Parse port and optional base path from argument
-			arg := args[0]
-			portStr := arg
-			basePath := ""
-			if idx := strings.Index(arg, "/"); idx != -1 {
-				portStr = arg[:idx]
-				basePath = arg[idx:] // includes leading /
+			if initConfig {
+				if len(args) == 0 {
+					return fmt.Errorf("--init needs the port to save, e.g. whk tunnel 3000 --init")
+				}
+				cfg := &project.Config{
+					Endpoint:    endpointSlug,
+					Target:      args[0],
+					Headers:     parseHeaders(headers),
+					HeadersFile: headersFile,
+					Filter:      filterName,
+					Query:       expr,
+					Ephemeral:   ephemeral,
+				}
+				return writeProjectConfig(cfg, false)
 			}
 
-			// Validate port
-			portNum, err := strconv.Atoi(portStr)
-			if err != nil || portNum < 1 || portNum > 65535 {
-				return fmt.Errorf("invalid port: %s (must be 1-65535)", portStr)
+			// Fill in anything not given on the command line from .whk.yaml
+			if path, err := project.Find("."); err == nil {
+				cfg, err := project.Load(path)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Using settings from %s\n", path)
+				if len(args) == 0 && cfg.Target != "" {
+					args = []string{cfg.Target}
+				}
+				flags := cmd.Flags()
+				if !flags.Changed("endpoint") {
+					endpointSlug = cfg.Endpoint
+				}
+				if !flags.Changed("ephemeral") {
+					ephemeral = cfg.Ephemeral
+				}
+				if !flags.Changed("filter") {
+					filterName = cfg.Filter
+				}
+				if !flags.Changed("query") {
+					expr = cfg.Query
+				}
+				// Flag headers are applied after (and so override) the file's
+				for k, v := range cfg.Headers {
+					headers = append([]string{k + ":" + v}, headers...)
+				}
+				if !flags.Changed("headers-file") && cfg.HeadersFile != "" {
+					headersFile = cfg.HeadersFile
+					if !filepath.IsAbs(headersFile) {
+						headersFile = filepath.Join(filepath.Dir(path), headersFile)
+					}
+				}
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("a port is required (or set target in %s; see 'whk init')", project.FileName)
+			}
+
+			targetURL, err := parseTunnelTarget(args[0])
+			if err != nil {
+				return err
+			}
+			q, err := resolveQuery(expr, filterName)
+			if err != nil {
+				return err
+			}
+			customHeaders, err := tunnelHeaders(headersFile, headers)
+			if err != nil {
+				return err
+			}
+
+			if endpointSlug != "" {
+				if endpointSlug, err = validateSlug(endpointSlug); err != nil {
+					return err
+				}
+			}
+			if resume {
+				if endpointSlug != "" {
+					return fmt.Errorf("--resume reuses an ephemeral endpoint and can't be combined with --endpoint")
+				}
+				ephemeral = true
+			}
+			if interceptReq {
+				if queueOffline {
+					return fmt.Errorf("--intercept can't be combined with --queue-offline")
+				}
+				if !stdinIsTerminal() {
+					return fmt.Errorf("--intercept needs an interactive terminal")
+				}
+			}
+			var expiresAt int64
+			if ttl != "" {
+				if endpointSlug != "" {
+					return fmt.Errorf("--ttl only applies to endpoints the tunnel creates, not --endpoint")
+				}
+				d, err := parseDuration(ttl)
+				if err != nil {
+					return err
+				}
+				expiresAt = time.Now().Add(d).UnixMilli()
 			}
-			targetURL := fmt.Sprintf("http://localhost:%d%s", portNum, basePath)
 
 			// Check auth early before making any API calls
 			token, err := auth.LoadToken()
 			if err != nil {
-				return fmt.Errorf("not logged in: %w", err)
+				return fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
+			}
+
+			// Claim the inspector port before creating an endpoint so a
+			// port conflict doesn't leave one behind
+			var inspectLn net.Listener
+			if inspectAddr != "" {
+				if strings.HasPrefix(inspectAddr, ":") {
+					inspectAddr = "localhost" + inspectAddr
+				}
+				if inspectLn, err = net.Listen("tcp", inspectAddr); err != nil {
+					return fmt.Errorf("failed to start inspector: %w", err)
+				}
+				defer func() { _ = inspectLn.Close() }()
 			}
 
 			client := api.NewClient()
@@ -394,33 +882,79 @@ Incoming request paths are appended to the base path. For example, with
 			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 			defer signal.Stop(sigCh)
 
-			// Create or reuse endpoint
+			// Create or reuse endpoint. Ephemeral endpoints are recorded
+			// locally so a later tunnel can adopt them if this one dies
+			// before deleting its endpoint.
 			slug := endpointSlug
 			createdEndpoint := false
-			if slug == "" {
-				endpoint, err := client.CreateEndpointWithContext(ctx, fmt.Sprintf("tunnel-%s", randomSuffix(6)), ephemeral)
+			var session *sessions.Session
+			if slug == "" && ephemeral {
+				if session, err = adoptOrphan(ctx, client, targetURL, resume); err != nil {
+					return err
+				}
+			}
+			if session != nil {
+				slug = session.Slug
+				expiresAt = session.ExpiresAt
+				createdEndpoint = true
+			} else if slug == "" {
+				endpoint, err := client.CreateEndpointWithParams(ctx, api.CreateEndpointParams{
+					Name:        fmt.Sprintf("tunnel-%s", randomSuffix(6)),
+					IsEphemeral: ephemeral,
+					ExpiresAt:   expiresAt,
+				})
 				if err != nil {
 					return fmt.Errorf("failed to create endpoint: %w", err)
 				}
 				slug = endpoint.Slug
 				createdEndpoint = true
+				if ephemeral {
+					session = &sessions.Session{Slug: slug, CreatedAt: time.Now().UnixMilli(), ExpiresAt: expiresAt}
+				}
+			}
+			if session != nil {
+				session.Target = targetURL
+				session.PID = os.Getpid()
+				if err := sessions.Record(*session); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to save tunnel state: %v\n", err)
+				}
 			}
 
 			fmt.Printf("Forwarding %s/w/%s -> %s\n", client.WebhookURL(), slug, targetURL)
 			if ephemeral && createdEndpoint {
 				fmt.Println("Endpoint will be deleted on exit")
 			}
+			if expiresAt > 0 {
+				fmt.Printf("Endpoint expires at %s even if the tunnel is killed\n", time.UnixMilli(expiresAt).Format("2006-01-02 15:04"))
+			}
+			if !q.Empty() {
+				fmt.Printf("Forwarding only requests matching: %s\n", q)
+			}
+			if inspectLn != nil {
+				fmt.Printf("Inspector: http://%s\n", inspectAddr)
+			}
 			fmt.Println("Press Ctrl+C to stop")
 			fmt.Println()
 
+			var cacheRequest func(*types.CapturedRequest)
+			if useCache {
+				if cacheRequest, err = openRequestCache(slug); err != nil {
+					return err
+				}
+			}
+
 			// Set up tunnel forwarder
 			t := tunnel.New(slug, targetURL)
-
-			// Apply custom headers
-			customHeaders := parseHeaders(headers)
-
-			// Set up SSE stream
-			s := stream.New(slug, client.BaseURL(), token.AccessToken)
+			t.SetHeaders(customHeaders)
+
+			var insp *inspect.Inspector
+			if inspectLn != nil {
+				t.KeepResponses()
+				insp = inspect.New(t.Forward)
+				srv := &http.Server{Handler: insp, ReadHeaderTimeout: 10 * time.Second}
+				go func() { _ = srv.Serve(inspectLn) }()
+				defer func() { _ = srv.Close() }()
+			}
 
 			// Handle cleanup on exit
 			go func() {
@@ -433,6 +967,7 @@ Incoming request paths are appended to the base path. For example, with
 						if delErr := client.DeleteEndpointWithContext(delCtx, slug); delErr != nil {
 							fmt.Fprintf(os.Stderr, "Warning: failed to delete endpoint: %v\n", delErr)
 						} else {
+							_ = sessions.Remove(slug)
 							fmt.Println("Endpoint deleted")
 						}
 					}
@@ -441,24 +976,101 @@ Incoming request paths are appended to the base path. For example, with
 				}
 			}()
 
+			// With --report, record forward outcomes on each request so it's
+			// visible later whether the webhook reached this machine.
+			// Reporting is best-effort.
+			var reports sync.WaitGroup
+			defer reports.Wait()
+			reportForward := func(req *types.CapturedRequest, result *tunnel.ForwardResult, fwdErr error) {
+				if !report || req.ID == "" {
+					return
+				}
+				var attempt types.ForwardAttempt
+				if fwdErr != nil {
+					attempt = types.ForwardAttempt{
+						RequestID:   req.ID,
+						Source:      types.ForwardSourceTunnel,
+						Target:      targetURL,
+						Attempt:     1,
+						Error:       fwdErr.Error(),
+						AttemptedAt: time.Now().UnixMilli(),
+					}
+				} else {
+					attempt = result.Attempt(req.ID, 1)
+				}
+				reports.Add(1)
+				go func() {
+					defer reports.Done()
+					reportCtx, cancel := context.WithTimeout(context.Background(), forwardReportTimeout)
+					defer cancel()
+					_ = client.ReportForwardAttempt(reportCtx, attempt)
+				}()
+			}
+
+			var offline *offlineDelivery
+			if queueOffline {
+				q, err := queue.Open(slug)
+				if err != nil {
+					return fmt.Errorf("failed to open the offline queue: %w", err)
+				}
+				offline = newOfflineDelivery(q, t.Forward, func(req *types.CapturedRequest, result *tunnel.ForwardResult, err error) {
+					reportForward(req, result, err)
+					if insp != nil {
+						insp.Record(req, result, err)
+					}
+				})
+				if n := q.Len(); n > 0 {
+					fmt.Printf("Delivering %d request(s) queued by an earlier tunnel first\n", n)
+				}
+				go offline.run(ctx)
+				defer func() {
+					if n := q.Len(); n > 0 {
+						fmt.Printf("%d request(s) still queued for the next 'whk tunnel --queue-offline' on %s\n", n, slug)
+					}
+				}()
+			}
+
+			var interceptor *interceptor
+			if interceptReq {
+				interceptor = newInterceptor()
+				fmt.Println("Intercept on: each request waits for you to forward, edit or drop it")
+			}
+
+			// Set up SSE stream
+			s := stream.New(slug, client.BaseURL(), token.AccessToken)
+
 			// Listen for requests and forward them
 			err = s.Listen(ctx, func(req *types.CapturedRequest) {
-				// Print received request
-				fmt.Printf("  %s", stream.FormatRequest(req))
+				if cacheRequest != nil {
+					cacheRequest(req)
+				}
+				if !q.Match(req) {
+					return
+				}
 
-				// Copy headers before mutation to avoid modifying the
-				// deserialized struct from the stream goroutine.
-				hdrs := make(map[string]string, len(req.Headers)+len(customHeaders))
-				for k, v := range req.Headers {
-					hdrs[k] = v
+				if offline != nil {
+					offline.handle(req)
+					return
 				}
-				for k, v := range customHeaders {
-					hdrs[k] = v
+
+				if interceptor != nil && !interceptor.off {
+					fmt.Printf("  %s\n", stream.FormatRequest(req))
+					if req = interceptor.review(ctx, req); req == nil {
+						fmt.Println("    -> dropped")
+						return
+					}
+					fmt.Print("  ")
+				} else {
+					// Print received request
+					fmt.Printf("  %s", stream.FormatRequest(req))
 				}
-				req.Headers = hdrs
 
 				// Forward to local server
 				result, err := t.Forward(req)
+				reportForward(req, result, err)
+				if insp != nil {
+					insp.Record(req, result, err)
+				}
 				if err != nil {
 					fmt.Printf("  -> ERROR: %v\n", err)
 					return
@@ -469,6 +1081,9 @@ Incoming request paths are appended to the base path. For example, with
 				return nil
 			}
 			if errors.Is(err, stream.ErrEndpointDeleted) {
+				if session != nil {
+					_ = sessions.Remove(slug)
+				}
 				fmt.Fprintln(os.Stderr, "Endpoint was deleted")
 				return nil
 			}
@@ -478,21 +1093,78 @@ Incoming request paths are appended to the base path. For example, with
 
 	cmd.Flags().StringVar(&endpointSlug, "endpoint", "", "Use an existing endpoint instead of creating one")
 	cmd.Flags().BoolVarP(&ephemeral, "ephemeral", "e", false, "Delete endpoint on exit")
-	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Add custom header to forwarded requests (repeatable, format: Key:Value)")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "Add custom header to forwarded requests (repeatable, format: Key:Value, or Key:env:VAR to read the value from $VAR)")
+	cmd.Flags().StringVar(&headersFile, "headers-file", "", "Add the headers in this file (Key: Value lines) to forwarded requests")
+	cmd.Flags().BoolVar(&report, "report", false, "Report forward results (status, latency, errors) to webhooks.cc")
+	cmd.Flags().StringVarP(&expr, "query", "q", "", "Only forward requests matching a search query")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Only forward requests matching a saved filter (see 'whk filter')")
+	cmd.Flags().StringVar(&ttl, "ttl", "", "Have the server delete the created endpoint after this long, e.g. 2h")
+	cmd.Flags().BoolVar(&initConfig, "init", false, "Save the arguments and flags to "+project.FileName+" instead of starting the tunnel")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Reuse the ephemeral endpoint of a tunnel that exited without deleting it")
+	cmd.Flags().StringVar(&inspectAddr, "inspect", "", "Serve a local web inspector on this address, e.g. :4040")
+	cmd.Flags().BoolVar(&useCache, "cache", false, "Save received requests to the local cache (see 'whk requests list --local')")
+	cmd.Flags().BoolVar(&queueOffline, "queue-offline", false, "Queue requests while the local server is down and deliver them once it is back")
+	cmd.Flags().BoolVar(&interceptReq, "intercept", false, "Hold each request until you forward, edit or drop it")
 
 	return cmd
 }
 
+// parseTunnelTarget turns a tunnel argument ("8080" or "8080/api/webhooks")
+// into the local URL requests are forwarded to.
+func parseTunnelTarget(arg string) (string, error) {
+	portStr := arg
+	basePath := ""
+	if idx := strings.Index(arg, "/"); idx != -1 {
+		portStr = arg[:idx]
+		basePath = arg[idx:] // includes leading /
+	}
+
+	portNum, err := strconv.Atoi(portStr)
+	if err != nil || portNum < 1 || portNum > 65535 {
+		return "", fmt.Errorf("invalid port: %s (must be 1-65535)", portStr)
+	}
+	return fmt.Sprintf("http://localhost:%d%s", portNum, basePath), nil
+}
+
 // --- Listen command ---
 
 func listenCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "listen <slug>",
+	var (
+		recent     int
+		expr       string
+		filterName string
+		showStats  bool
+		useCache   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "listen [slug]",
 		Short: "Stream incoming requests to terminal",
-		Args:  cobra.ExactArgs(1),
+		Long: `Stream incoming requests to the terminal. If the slug is omitted in an
+interactive terminal, pick the endpoint from a searchable list.
+
+With --stats, a table refreshed every second replaces the request lines:
+requests/sec over the last 10 seconds, a method breakdown, the busiest
+paths, and counts of error statuses the endpoint answered with. Requests
+loaded with --recent are counted too.
+
+With --cache, every request received is also saved locally, so it can be
+listed with 'whk requests list --local' and browsed in the TUI history
+after the platform's retention deletes it or while offline.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			slug := args[0]
+			q, err := resolveQuery(expr, filterName)
+			if err != nil {
+				return err
+			}
 			client := api.NewClient()
+			slug, err := slugArg(cmd.Context(), client, args)
+			if errors.Is(err, errPickCancelled) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
 
 			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
@@ -511,15 +1183,70 @@ func listenCmd() *cobra.Command {
 
 			token, err := auth.LoadToken()
 			if err != nil {
-				return fmt.Errorf("not logged in: %w", err)
+				return fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
 			}
 
 			fmt.Printf("Listening on %s/w/%s\n", client.WebhookURL(), slug)
+			if !q.Empty() {
+				fmt.Printf("Showing requests matching: %s\n", q)
+			}
 			fmt.Println("Press Ctrl+C to stop")
 			fmt.Println()
 
+			var cacheRequest func(*types.CapturedRequest)
+			if useCache {
+				if cacheRequest, err = openRequestCache(slug); err != nil {
+					return err
+				}
+			}
+
+			var stats *stream.Stats
+			if showStats {
+				stats = stream.NewStats(time.Now())
+				title := fmt.Sprintf("Listening on %s/w/%s · Ctrl+C to stop", client.WebhookURL(), slug)
+				if !q.Empty() {
+					title += "\nShowing requests matching: " + q.String()
+				}
+				go drawStats(ctx, stats, title)
+			}
+
+			seen := map[string]bool{}
+			if recent > 0 {
+				reqs, err := client.ListRequests(ctx, slug, api.ListRequestsParams{Limit: recent})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not load recent requests: %v\n", err)
+				}
+				// Newest first from the API; print oldest first like the live stream
+				for i := len(reqs) - 1; i >= 0; i-- {
+					seen[reqs[i].ID] = true
+					if cacheRequest != nil {
+						cacheRequest(&reqs[i])
+					}
+					switch {
+					case !q.Match(&reqs[i]):
+					case stats != nil:
+						stats.Add(&reqs[i], time.UnixMilli(reqs[i].ReceivedAt))
+					default:
+						fmt.Printf("  %s\n", stream.FormatRequest(&reqs[i]))
+					}
+				}
+			}
+
 			s := stream.New(slug, client.BaseURL(), token.AccessToken)
 			err = s.Listen(ctx, func(req *types.CapturedRequest) {
+				if seen[req.ID] {
+					return
+				}
+				if cacheRequest != nil {
+					cacheRequest(req)
+				}
+				if !q.Match(req) {
+					return
+				}
+				if stats != nil {
+					stats.Add(req, time.Now())
+					return
+				}
 				fmt.Printf("  %s\n", stream.FormatRequest(req))
 			})
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
@@ -532,33 +1259,115 @@ func listenCmd() *cobra.Command {
 			return err
 		},
 	}
+
+	cmd.Flags().IntVar(&recent, "recent", 0, "Show the last N captured requests before streaming new ones")
+	cmd.Flags().StringVarP(&expr, "query", "q", "", "Only show requests matching a search query")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Only show requests matching a saved filter (see 'whk filter')")
+	cmd.Flags().BoolVar(&showStats, "stats", false, "Show live request statistics instead of each request")
+	cmd.Flags().BoolVar(&useCache, "cache", false, "Save received requests to the local cache (see 'whk requests list --local')")
+
+	return cmd
+}
+
+// drawStats redraws the listen --stats view every second until ctx is done.
+func drawStats(ctx context.Context, stats *stream.Stats, title string) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		// Clear the screen and move the cursor home before redrawing
+		fmt.Print("\033[H\033[2J" + title + "\n\n" + stats.Render(time.Now()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // --- Replay command ---
 
 func replayCmd() *cobra.Command {
-	var target string
+	var (
+		target   string
+		slug     string
+		provider string
+		secret   string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "replay <request-id>",
+		Use:   "replay [request-id]",
 		Short: "Replay a captured request",
-		Args:  cobra.ExactArgs(1),
+		Long: `Replay a captured request to a local server. If the request ID is
+omitted in an interactive terminal, pick the endpoint (unless --slug is
+set) and then one of its recent requests from a searchable list.
+
+--resign recomputes the provider's signature headers with --secret, so a
+handler that verifies signatures accepts the replay. Timestamped
+signatures are dated now. Providers: ` + strings.Join(resign.Providers(), ", ") + `.
+
+Examples:
+  whk replay req_123 --to http://localhost:3000
+  whk replay req_123 --resign stripe --secret $STRIPE_WEBHOOK_SECRET`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRequestIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			requestID := args[0]
-			client := api.NewClient()
+			if provider != "" {
+				if err := resign.Check(provider); err != nil {
+					return err
+				}
+				if secret == "" {
+					return fmt.Errorf("--resign requires --secret")
+				}
+			} else if secret != "" {
+				return fmt.Errorf("--secret requires --resign")
+			}
 
+			client := api.NewClient()
 			ctx := cmd.Context()
 
+			var requestID string
+			if len(args) > 0 {
+				requestID = args[0]
+			} else {
+				if !isInteractive() {
+					return fmt.Errorf("missing request ID")
+				}
+				var slugArgs []string
+				if slug != "" {
+					slugArgs = []string{slug}
+				}
+				s, err := slugArg(ctx, client, slugArgs)
+				if err == nil {
+					requestID, err = pickRequest(ctx, client, s)
+				}
+				if errors.Is(err, errPickCancelled) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+
 			// Fetch the request
 			req, err := client.GetRequest(ctx, requestID)
 			if err != nil {
 				return fmt.Errorf("failed to fetch request: %w", err)
 			}
 
+			t := tunnel.New("", target)
+			if provider != "" {
+				targetURL, err := t.TargetURL(req)
+				if err != nil {
+					return err
+				}
+				if err := resign.Sign(req, provider, secret, targetURL, time.Now()); err != nil {
+					return fmt.Errorf("failed to re-sign request: %w", err)
+				}
+			}
+
 			fmt.Printf("Replaying %s %s -> %s\n", req.Method, req.Path, target)
 
 			// Forward to target
-			t := tunnel.New("", target)
 			result, fwdErr := t.Forward(req)
 			if fwdErr != nil {
 				return fmt.Errorf("replay failed: %w", fwdErr)
@@ -570,6 +1379,9 @@ func replayCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&target, "to", "http://localhost:8080", "Target URL for replay")
+	cmd.Flags().StringVar(&slug, "slug", "", "Endpoint to pick the request from when the request ID is omitted")
+	cmd.Flags().StringVar(&provider, "resign", "", "Recompute this provider's signature headers before replaying")
+	cmd.Flags().StringVar(&secret, "secret", "", "Signing secret for --resign")
 	return cmd
 }
 
@@ -649,6 +1461,49 @@ func parseHeaders(headers []string) map[string]string {
 	return result
 }
 
+// tunnelHeaders returns the headers a tunnel adds to forwarded requests:
+// the headers file's, overridden by the --header flags, with env:VAR
+// values read from the environment.
+func tunnelHeaders(file string, flags []string) (map[string]string, error) {
+	headers := map[string]string{}
+	if file != "" {
+		fileHeaders, err := project.LoadHeaders(file)
+		if err != nil {
+			return nil, fmt.Errorf("--headers-file: %w", err)
+		}
+		maps.Copy(headers, fileHeaders)
+	}
+	maps.Copy(headers, parseHeaders(flags))
+	return project.ResolveEnv(headers)
+}
+
+// validateSlug normalizes an endpoint slug and rejects slugs the receiver
+// would never accept.
+func validateSlug(slug string) (string, error) {
+	normalized, ok := validation.NormalizeSlug(slug)
+	if !ok {
+		return "", fmt.Errorf("invalid slug: %q (1-%d characters of a-z, 0-9, - and _)", slug, validation.MaxSlugLen)
+	}
+	return normalized, nil
+}
+
+// parseDuration parses a Go duration string, additionally accepting a
+// whole number of days with a "d" suffix (e.g. "7d").
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return d, nil
+}
+
 // randomSuffix returns n hex characters from crypto/rand.
 func randomSuffix(n int) string {
 	b := make([]byte, (n+1)/2)
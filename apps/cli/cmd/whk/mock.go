@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/openapi"
+	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
+)
+
+// --- Mock response commands ---
+
+func mockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Manage endpoint mock responses",
+	}
+
+	cmd.AddCommand(mockImportCmd())
+	cmd.AddCommand(mockTestCmd())
+
+	return cmd
+}
+
+func mockImportCmd() *cobra.Command {
+	var (
+		operation string
+		status    string
+		dryRun    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <slug> <openapi-file>",
+		Short: "Set an endpoint's mock response from an OpenAPI document",
+		Long: `Set an endpoint's mock response from a response defined in an OpenAPI
+3.x or Swagger 2.0 document (YAML or JSON).
+
+The status, headers and body are taken from the response's examples, or
+built from its schema when it has none. Without --status the first 2xx
+response is used. --operation may be omitted if the document has only
+one operation.
+
+Examples:
+  whk mock import my-endpoint openapi.yaml --operation postWebhook
+  whk mock import my-endpoint openapi.yaml --operation postWebhook --status 400
+  whk mock import my-endpoint openapi.json --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			doc, err := openapi.Parse(data)
+			if err != nil {
+				return err
+			}
+
+			mock, err := doc.MockResponse(operation, status)
+			if err != nil {
+				if operation == "" || errors.Is(err, openapi.ErrOperationNotFound) {
+					printOperations(doc.Operations())
+				}
+				return err
+			}
+
+			safe := validation.SanitizeResponseHeaders(mock.Headers)
+			for name := range mock.Headers {
+				if _, ok := safe[name]; !ok {
+					fmt.Fprintf(os.Stderr, "Skipping header %s: it cannot be sent in a mock response\n", name)
+				}
+			}
+			mock.Headers = safe
+
+			fmt.Printf("Status: %d\n", mock.Status)
+			names := make([]string, 0, len(mock.Headers))
+			for name := range mock.Headers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s: %s\n", name, mock.Headers[name])
+			}
+			if mock.Body != "" {
+				fmt.Printf("\n%s\n", mock.Body)
+			}
+			if dryRun {
+				return nil
+			}
+
+			client := api.NewClient()
+			if err := client.SetEndpointMockResponse(cmd.Context(), slug, mock); err != nil {
+				return err
+			}
+			fmt.Printf("\nMock response for %s updated\n", slug)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&operation, "operation", "", "Operation to import, by operationId")
+	cmd.Flags().StringVar(&status, "status", "", "Response to import, e.g. 200, 4XX or default (default: first 2xx)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the mock response without setting it")
+
+	return cmd
+}
+
+// printOperations lists a document's operations so the user can pick one
+// for --operation.
+func printOperations(ops []openapi.Operation) {
+	if len(ops) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Operations:")
+	for _, op := range ops {
+		id := op.ID
+		if id == "" {
+			id = "(no operationId)"
+		}
+		fmt.Fprintf(os.Stderr, "  %-24s %s %s\n", id, op.Method, op.Path)
+	}
+}
+
+func mockTestCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "test <slug>",
+		Short: "Show how an endpoint's mock answers a sample request",
+		Long: `Work out locally how an endpoint answers a sample request, without
+sending it: which route matched and the response it gets.
+
+The sample is a captured request as JSON, such as the output of
+` + "`whk requests get <request-id>`" + `. A missing method is POST and a
+missing path, relative to the endpoint, is /. Use - to read it from stdin.
+
+Examples:
+  whk mock test my-endpoint --request sample.json
+  whk requests get <request-id> | whk mock test my-endpoint --request -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			var data []byte
+			if file == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return err
+			}
+			var req types.CapturedRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				return fmt.Errorf("invalid sample request: %w", err)
+			}
+			if req.Method == "" {
+				req.Method = "POST"
+			}
+			if req.Path == "" {
+				req.Path = "/"
+			}
+
+			endpoint, err := api.NewClient().GetEndpoint(cmd.Context(), slug)
+			if err != nil {
+				return err
+			}
+			result := types.ResolveMock(endpoint.MockResponse, endpoint.Routes, &req)
+
+			fmt.Printf("Request:   %s %s\n", req.Method, req.Path)
+			switch {
+			case result.Route == nil:
+				fmt.Println("Route:     none")
+			case result.Route.Name != "":
+				fmt.Printf("Route:     %s (%s)\n", result.Route.Prefix, result.Route.Name)
+			default:
+				fmt.Printf("Route:     %s\n", result.Route.Prefix)
+			}
+			if result.Route != nil && len(result.Route.Tags) > 0 {
+				fmt.Printf("Tags:      %s\n", strings.Join(result.Route.Tags, ", "))
+			}
+			switch {
+			case result.Route != nil && result.Route.MockResponse != nil:
+				fmt.Println("Answer:    route mock response")
+			case endpoint.MockResponse != nil:
+				fmt.Println("Answer:    endpoint mock response")
+			default:
+				fmt.Println("Answer:    default response")
+			}
+
+			resp := result.Response
+			if resp.Delay > 0 {
+				fmt.Printf("Delay:     %s\n", validation.ClampDelay(resp.Delay))
+			}
+			fmt.Printf("\nHTTP %d\n", resp.Status)
+			names := make([]string, 0, len(resp.Headers))
+			for name := range resp.Headers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s: %s\n", name, resp.Headers[name])
+			}
+			if resp.Body != "" {
+				fmt.Printf("\n%s\n", resp.Body)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "request", "", "Sample request JSON file, or - for stdin")
+	_ = cmd.MarkFlagRequired("request")
+
+	return cmd
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"webhooks.cc/cli/internal/queue"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/cli/internal/tunnel"
+	"webhooks.cc/shared/types"
+)
+
+// Retry delays for a target that is down while requests are queued
+const (
+	offlineInitialRetry = 2 * time.Second
+	offlineMaxRetry     = 30 * time.Second
+)
+
+// offlineDelivery implements `whk tunnel --queue-offline`: requests that
+// arrive while the local target is unreachable are queued, and the backlog
+// is delivered in order once it is back, before any newer request.
+type offlineDelivery struct {
+	queue   *queue.Queue
+	forward func(*types.CapturedRequest) (*tunnel.ForwardResult, error)
+	// done is called once a request was delivered, or failed for a reason
+	// retrying won't fix
+	done func(*types.CapturedRequest, *tunnel.ForwardResult, error)
+
+	// mu serializes deliveries so the backlog stays in order
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+func newOfflineDelivery(q *queue.Queue, forward func(*types.CapturedRequest) (*tunnel.ForwardResult, error), done func(*types.CapturedRequest, *tunnel.ForwardResult, error)) *offlineDelivery {
+	return &offlineDelivery{queue: q, forward: forward, done: done, wake: make(chan struct{}, 1)}
+}
+
+// unreachable reports whether a forward failed because the target couldn't
+// be reached, as opposed to answering with an error status.
+func unreachable(result *tunnel.ForwardResult, err error) bool {
+	return err == nil && !result.Success
+}
+
+// handle delivers a request from the stream, or queues it if the target is
+// down or older requests are still waiting, and prints the outcome.
+func (d *offlineDelivery) handle(req *types.CapturedRequest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	line := stream.FormatRequest(req)
+	if d.queue.Len() > 0 {
+		d.push(req, line, "")
+		return
+	}
+	result, err := d.forward(req)
+	if unreachable(result, err) {
+		d.push(req, line, result.String()+", ")
+		d.signal()
+		return
+	}
+	d.done(req, result, err)
+	if err != nil {
+		fmt.Printf("  %s  -> ERROR: %v\n", line, err)
+		return
+	}
+	fmt.Printf("  %s  -> %s\n", line, result)
+}
+
+// push queues req and prints its line. The caller holds d.mu.
+func (d *offlineDelivery) push(req *types.CapturedRequest, line, why string) {
+	if err := d.queue.Push(req); err != nil {
+		fmt.Printf("  %s  -> %snot queued: %v\n", line, why, err)
+		d.done(req, nil, fmt.Errorf("target unreachable and request not queued: %w", err))
+		return
+	}
+	fmt.Printf("  %s  -> %squeued (%d waiting)\n", line, why, d.queue.Len())
+}
+
+func (d *offlineDelivery) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the backlog until ctx is done, retrying with backoff while
+// the target is still down.
+func (d *offlineDelivery) run(ctx context.Context) {
+	retry := offlineInitialRetry
+	delivered := 0
+	for {
+		if d.queue.Len() > 0 {
+			if d.catchUp(&delivered) {
+				retry = offlineInitialRetry
+			} else {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retry):
+				}
+				retry = min(retry*2, offlineMaxRetry)
+				continue
+			}
+		}
+		if delivered > 0 {
+			fmt.Printf("Caught up: delivered %d queued request(s)\n", delivered)
+			delivered = 0
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		}
+	}
+}
+
+// catchUp delivers queued requests in order until the queue is empty,
+// counting them in delivered. It returns false if the target is still down.
+func (d *offlineDelivery) catchUp(delivered *int) bool {
+	for {
+		d.mu.Lock()
+		req, err := d.queue.Peek()
+		if err != nil {
+			d.mu.Unlock()
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if req == nil {
+			d.mu.Unlock()
+			return true
+		}
+		result, fwdErr := d.forward(req)
+		if unreachable(result, fwdErr) {
+			d.mu.Unlock()
+			return false
+		}
+		if err := d.queue.Pop(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove delivered request from the queue: %v\n", err)
+		}
+		*delivered++
+		left := d.queue.Len()
+		d.mu.Unlock()
+
+		d.done(req, result, fwdErr)
+		line := stream.FormatRequest(req)
+		if fwdErr != nil {
+			fmt.Printf("  [queued] %s  -> ERROR: %v (%d left)\n", line, fwdErr, left)
+		} else {
+			fmt.Printf("  [queued] %s  -> %s (%d left)\n", line, result, left)
+		}
+	}
+}
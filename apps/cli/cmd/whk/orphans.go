@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/sessions"
+)
+
+// adoptOrphan handles ephemeral endpoints left behind by tunnels that exited
+// without deleting them (crashed, killed, or lost power). With resume, the
+// newest orphan is reused, preferring one that forwarded to the same target.
+// Otherwise an interactive user is asked whether to reuse or delete them.
+// It returns the session to reuse, or nil to create a new endpoint.
+func adoptOrphan(ctx context.Context, client *api.Client, target string, resume bool) (*sessions.Session, error) {
+	orphans, err := sessions.Orphans()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read tunnel state: %v\n", err)
+		return nil, nil
+	}
+	if len(orphans) == 0 {
+		if resume {
+			fmt.Println("No orphaned tunnel endpoints to resume, creating a new one")
+		}
+		return nil, nil
+	}
+
+	pick := orphans[0]
+	for _, o := range orphans {
+		if o.Target == target {
+			pick = o
+			break
+		}
+	}
+	if resume {
+		return resumeOrphan(ctx, client, pick)
+	}
+
+	if !stdinIsTerminal() {
+		fmt.Fprintf(os.Stderr, "Note: %d ephemeral endpoint(s) from earlier tunnels were not deleted; run with --resume to reuse one\n", len(orphans))
+		return nil, nil
+	}
+
+	fmt.Println("Ephemeral endpoints left behind by earlier tunnels:")
+	for _, o := range orphans {
+		fmt.Printf("  %s -> %s (started %s)\n", o.Slug, o.Target, time.UnixMilli(o.CreatedAt).Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("[r]euse %s, [d]elete them, or [k]eep them? [k] ", pick.Slug)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	fmt.Println()
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "r", "reuse":
+		return resumeOrphan(ctx, client, pick)
+	case "d", "delete":
+		for _, o := range orphans {
+			if err := client.DeleteEndpointWithContext(ctx, o.Slug); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", o.Slug, err)
+				continue
+			}
+			if err := sessions.Remove(o.Slug); err != nil {
+				return nil, err
+			}
+			fmt.Printf("Deleted %s\n", o.Slug)
+		}
+	}
+	return nil, nil
+}
+
+// resumeOrphan checks that an orphaned endpoint still exists before reusing
+// it. Endpoints that are gone are forgotten and a new one is created.
+func resumeOrphan(ctx context.Context, client *api.Client, orphan sessions.Session) (*sessions.Session, error) {
+	if _, err := client.GetEndpoint(ctx, orphan.Slug); err != nil {
+		fmt.Fprintf(os.Stderr, "Endpoint %s is no longer available (%v), creating a new one\n", orphan.Slug, err)
+		if err := sessions.Remove(orphan.Slug); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	fmt.Printf("Resuming endpoint %s\n", orphan.Slug)
+	return &orphan, nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal, so
+// prompts are skipped when input is piped or the tunnel runs in a script.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
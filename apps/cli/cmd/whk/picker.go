@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/cli/internal/tui/screens"
+)
+
+// pickerRequestLimit bounds how many recent requests the request picker offers.
+const pickerRequestLimit = 50
+
+// errPickCancelled is returned when the user leaves a picker without choosing.
+var errPickCancelled = errors.New("cancelled")
+
+// slugArg returns the endpoint slug given as the first argument. When it's
+// omitted on an interactive terminal the user picks one instead.
+func slugArg(ctx context.Context, client *api.Client, args []string) (string, error) {
+	if len(args) > 0 {
+		return validateSlug(args[0])
+	}
+	if !isInteractive() {
+		return "", fmt.Errorf("missing endpoint slug")
+	}
+	return pickEndpoint(ctx, client)
+}
+
+// isInteractive reports whether both stdin and stdout are terminals, so a
+// picker can be drawn and answered.
+func isInteractive() bool {
+	if !stdinIsTerminal() {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// pickEndpoint lets the user choose one of their endpoints.
+func pickEndpoint(ctx context.Context, client *api.Client) (string, error) {
+	eps, err := client.ListEndpointsWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(eps) == 0 {
+		return "", fmt.Errorf("no endpoints yet; create one with 'whk create'")
+	}
+
+	items := make([]screens.PickerItem, len(eps))
+	for i, ep := range eps {
+		label := ep.Slug
+		if ep.Name != "" {
+			label = ep.Name + " (" + ep.Slug + ")"
+		}
+		items[i] = screens.PickerItem{Value: ep.Slug, Label: label, Detail: ep.URL}
+	}
+	return runPicker("Select endpoint", items)
+}
+
+// pickRequest lets the user choose one of an endpoint's recent requests.
+func pickRequest(ctx context.Context, client *api.Client, slug string) (string, error) {
+	reqs, err := client.ListRequests(ctx, slug, api.ListRequestsParams{Limit: pickerRequestLimit})
+	if err != nil {
+		return "", err
+	}
+	if len(reqs) == 0 {
+		return "", fmt.Errorf("endpoint '%s' has no captured requests", slug)
+	}
+
+	items := make([]screens.PickerItem, len(reqs))
+	for i, req := range reqs {
+		items[i] = screens.PickerItem{
+			Value: req.ID,
+			Label: fmt.Sprintf("%s  %-6s %s",
+				time.UnixMilli(req.ReceivedAt).Format("Jan 02 15:04:05"), req.Method, req.Path),
+			Detail: stream.FormatBytes(req.Size),
+		}
+	}
+	return runPicker("Select request", items)
+}
+
+func runPicker(title string, items []screens.PickerItem) (string, error) {
+	final, err := tea.NewProgram(screens.NewPicker(title, items)).Run()
+	if err != nil {
+		return "", fmt.Errorf("picker error: %w", err)
+	}
+	item, ok := final.(screens.PickerModel).Selected()
+	if !ok {
+		return "", errPickCancelled
+	}
+	return item.Value, nil
+}
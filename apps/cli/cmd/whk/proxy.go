@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/proxy"
+)
+
+// proxyShutdownTimeout bounds waiting for in-flight requests on exit
+const proxyShutdownTimeout = 5 * time.Second
+
+// --- Proxy command ---
+
+func proxyCmd() *cobra.Command {
+	var (
+		listen string
+		to     string
+		mirror string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Run a local reverse proxy that mirrors traffic to an endpoint",
+		Long: `Run a reverse proxy in front of a local server and mirror a copy of
+every request to an endpoint, so any local API traffic (not only
+webhooks) can be inspected with whk listen, the TUI, or the dashboard.
+
+Point your client at the proxy instead of the server. Responses always
+come from the local server; the endpoint's mock response is ignored.
+Credentials (Authorization, Cookie and similar headers) reach the local
+server but are stripped from the mirrored copy. Bodies over 1MB are
+proxied but not mirrored.
+
+--to takes a URL or a port with an optional base path, like whk tunnel.
+
+Examples:
+  whk proxy --to 3000 --mirror my-endpoint
+  whk proxy --listen :9999 --to http://localhost:3000 --mirror my-endpoint`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(mirror)
+			if err != nil {
+				return err
+			}
+			target := to
+			if !strings.Contains(to, "://") {
+				target, err = parseTunnelTarget(to)
+				if err != nil {
+					return err
+				}
+			}
+
+			client := api.NewClient()
+			mirrorURL := fmt.Sprintf("%s/w/%s", client.WebhookURL(), slug)
+			p, err := proxy.New(target, mirrorURL)
+			if err != nil {
+				return err
+			}
+			p.OnRequest(func(r proxy.Result) {
+				fmt.Printf("  %s  %s\n", time.Now().Format("15:04:05"), r)
+			})
+
+			ln, err := net.Listen("tcp", listen)
+			if err != nil {
+				return err
+			}
+			srv := &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Printf("Proxying http://%s -> %s\n", ln.Addr(), target)
+			fmt.Printf("Mirroring to %s\n", mirrorURL)
+			fmt.Println("Press Ctrl+C to stop")
+			fmt.Println()
+
+			errCh := make(chan error, 1)
+			go func() { errCh <- srv.Serve(ln) }()
+
+			select {
+			case err := <-errCh:
+				if !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+			case <-ctx.Done():
+				fmt.Println("\nStopping proxy...")
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), proxyShutdownTimeout)
+				defer cancel()
+				if err := srv.Shutdown(shutdownCtx); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+			p.Wait()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "localhost:9999", "Address to listen on")
+	cmd.Flags().StringVar(&to, "to", "", "Local server to proxy to: a URL, or a port with an optional path")
+	cmd.Flags().StringVar(&mirror, "mirror", "", "Endpoint slug to mirror requests to")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagRequired("mirror")
+
+	return cmd
+}
@@ -0,0 +1,674 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/cache"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/shared/search"
+	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
+)
+
+// --- Captured request commands ---
+
+func requestsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "requests",
+		Short: "Browse and manage captured requests",
+	}
+
+	cmd.AddCommand(requestsListCmd())
+	cmd.AddCommand(requestsGetCmd())
+	cmd.AddCommand(requestsPinCmd())
+	cmd.AddCommand(requestsUnpinCmd())
+	cmd.AddCommand(requestsNoteCmd())
+	cmd.AddCommand(requestsTagCmd())
+	cmd.AddCommand(requestsUntagCmd())
+	cmd.AddCommand(requestsShareCmd())
+
+	return cmd
+}
+
+func requestsListCmd() *cobra.Command {
+	var (
+		limit      int
+		pinned     bool
+		expr       string
+		filterName string
+		local      bool
+		tags       []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list <slug>",
+		Short: "List recent captured requests for an endpoint",
+		Long: `List recent captured requests for an endpoint, newest first.
+
+--query filters with the request search language: space-separated terms
+that must all match, each a field:value pair or a bare word. Fields are
+method, path, ip, body, status, tag, header.<name>, query.<name> and
+param.<name>; values may be quoted and use * as a wildcard, status also
+takes a comparison such as status:>=400, and a leading - negates a term.
+The server doesn't evaluate queries yet, so whk pages through the
+endpoint's history itself until --limit requests match (default 50),
+which can take a while on a busy endpoint.
+
+--tag only lists requests with that tag, like a tag:<name> query term;
+repeat it to require several tags.
+
+--local lists requests saved by 'whk listen --cache' or 'whk tunnel
+--cache' instead of asking the server, so it works offline and shows
+requests the platform's retention has already deleted.
+
+Example:
+  whk requests list my-endpoint --local
+  whk requests list my-endpoint --tag bug-1234
+  whk requests list my-endpoint --query 'method:POST path:/stripe/* header.x-event-type:invoice.*'
+  whk requests list my-endpoint -q 'body:"customer_123" -header.stripe-signature:*'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slug, err := validateSlug(args[0])
+			if err != nil {
+				return err
+			}
+			q, err := resolveQuery(expr, filterName)
+			if err != nil {
+				return err
+			}
+			tags, err = normalizeTags(tags)
+			if err != nil {
+				return err
+			}
+			for _, tag := range tags {
+				q.Terms = append(q.Terms, search.Term{Field: search.FieldTag, Value: tag})
+			}
+
+			var reqs []types.CapturedRequest
+			if local {
+				// Filter before limiting, as the server would
+				if reqs, err = cache.List(slug, 0); err != nil {
+					return err
+				}
+			} else if !q.Empty() && !pinned {
+				if limit <= 0 {
+					limit = searchDefaultLimit
+				}
+				if reqs, err = searchRequests(cmd.Context(), api.NewClient(), slug, q, limit); err != nil {
+					return err
+				}
+			} else {
+				client := api.NewClient()
+				params := api.ListRequestsParams{Limit: limit, Pinned: pinned, Query: q.String()}
+				if !q.Empty() {
+					// Fetch every pin, then filter and limit below
+					params.Limit = maxPinnedList
+				}
+				if reqs, err = client.ListRequests(cmd.Context(), slug, params); err != nil {
+					return err
+				}
+			}
+
+			// Also filter locally, in case the server ignores the query or
+			// --pinned.
+			if !q.Empty() || pinned {
+				matched := reqs[:0]
+				for i := range reqs {
+					if q.Match(&reqs[i]) && (!pinned || reqs[i].Pinned) {
+						matched = append(matched, reqs[i])
+					}
+				}
+				reqs = matched
+			}
+			if limit > 0 && len(reqs) > limit {
+				reqs = reqs[:limit]
+			}
+
+			if len(reqs) == 0 {
+				if !q.Empty() {
+					fmt.Println("No requests match the query")
+				} else if pinned {
+					fmt.Println("No pinned requests")
+				} else if local {
+					fmt.Printf("No cached requests for %s (use --cache with whk listen or whk tunnel)\n", slug)
+				} else {
+					fmt.Println("No requests captured yet")
+				}
+				return nil
+			}
+
+			fmt.Printf("%-36s %-19s %-7s %-10s %s\n", "ID", "RECEIVED", "METHOD", "SIZE", "PATH")
+			fmt.Printf("%-36s %-19s %-7s %-10s %s\n", "--", "--------", "------", "----", "----")
+			for _, req := range reqs {
+				path := req.Path
+				if req.Pinned {
+					path += "  (pinned)"
+				}
+				if len(req.Tags) > 0 {
+					path += "  [" + strings.Join(req.Tags, ", ") + "]"
+				}
+				received := time.UnixMilli(req.ReceivedAt).Format("2006-01-02 15:04:05")
+				fmt.Printf("%-36s %-19s %-7s %-10s %s\n", req.ID, received, req.Method, stream.FormatBytes(req.Size), path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Maximum number of requests to list (default: server default)")
+	cmd.Flags().BoolVar(&pinned, "pinned", false, "Only list pinned requests")
+	cmd.Flags().StringVarP(&expr, "query", "q", "", "Only list requests matching a search query")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Only list requests matching a saved filter (see 'whk filter')")
+	cmd.Flags().BoolVar(&local, "local", false, "List requests from the local cache instead of the server")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Only list requests with this tag (repeatable)")
+
+	return cmd
+}
+
+const (
+	// searchDefaultLimit is how many matches a --query search lists
+	// without --limit
+	searchDefaultLimit = 50
+	// searchPageSize is how many requests searchRequests fetches at a time
+	searchPageSize = 100
+	// maxPinnedList is the most requests the server lists at once, which
+	// covers every plan's pin limit
+	maxPinnedList = 1000
+)
+
+// searchRequests pages through an endpoint's requests, newest first, until
+// limit of them match q or the retention window runs out. The server
+// doesn't evaluate queries yet, so matching happens here.
+func searchRequests(ctx context.Context, client *api.Client, slug string, q *search.Query, limit int) ([]types.CapturedRequest, error) {
+	var matched []types.CapturedRequest
+	cursor := ""
+	for {
+		page, err := client.ListRequestsPage(ctx, slug, searchPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.Requests {
+			if q.Match(&page.Requests[i]) {
+				matched = append(matched, page.Requests[i])
+				if len(matched) == limit {
+					return matched, nil
+				}
+			}
+		}
+		if page.Cursor == "" {
+			return matched, nil
+		}
+		cursor = page.Cursor
+	}
+}
+
+// openRequestCache opens the local request cache for --cache and returns a
+// function that saves a request to it. Write failures are reported once
+// and otherwise ignored, so a full disk doesn't interrupt the stream.
+func openRequestCache(slug string) (func(*types.CapturedRequest), error) {
+	c, err := cache.Open(slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request cache: %w", err)
+	}
+	var warn sync.Once
+	return func(req *types.CapturedRequest) {
+		if err := c.Add(req); err != nil {
+			warn.Do(func() {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache request: %v\n", err)
+			})
+		}
+	}, nil
+}
+
+// completionLimit is how many cached request IDs shell completion offers
+const completionLimit = 50
+
+// completeRequestIDs completes a request ID argument from the local cache,
+// newest first, describing each with its method, path, endpoint and age.
+// Commands with a --slug flag only offer that endpoint's requests.
+func completeRequestIDs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	const directive = cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var entries []cache.Entry
+	if f := cmd.Flags().Lookup("slug"); f != nil && f.Value.String() != "" {
+		slug := f.Value.String()
+		reqs, err := cache.List(slug, completionLimit)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, req := range reqs {
+			entries = append(entries, cache.Entry{Slug: slug, Request: req})
+		}
+	} else {
+		var err error
+		if entries, err = cache.Recent(completionLimit); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+	}
+
+	now := time.Now()
+	var completions []cobra.Completion
+	for _, e := range entries {
+		req := e.Request
+		if req.ID == "" || !strings.HasPrefix(req.ID, toComplete) {
+			continue
+		}
+		desc := fmt.Sprintf("%s %s · %s · %s", req.Method, req.Path, e.Slug, formatAgo(time.UnixMilli(req.ReceivedAt), now))
+		completions = append(completions, cobra.CompletionWithDesc(req.ID, desc))
+	}
+	return completions, directive
+}
+
+func requestsGetCmd() *cobra.Command {
+	var (
+		fields string
+		tmpl   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get <request-id>",
+		Short: "Print a captured request, or selected fields of it",
+		Long: `Print a captured request as JSON, or only the parts a script needs.
+
+--fields takes a comma-separated list of dotted paths into the request's
+JSON (method, path, ip, size, receivedAt, contentType, headers.<name>,
+query.<name>, param.<name>, body, body.<path>) and prints each value
+on its own line. Header and other map keys match case-insensitively;
+body.<path> reads from a JSON body, with numbers selecting array items.
+Strings print as-is and objects as compact JSON.
+
+--template renders a Go template with the request as its data. Besides
+the fields ({{.Method}}, {{.Path}}, {{.Body}}, ...) it offers header
+"name", field "dotted.path", json, and time (for {{time .ReceivedAt}}).
+
+Example:
+  whk requests get <request-id> --fields method,path,headers.stripe-signature,body
+  whk requests get <request-id> --fields body.data.object.id
+  whk requests get <request-id> --template '{{.Method}} {{.Path}} {{header "content-type"}}'`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fields != "" && tmpl != "" {
+				return fmt.Errorf("--fields and --template cannot be used together")
+			}
+			var t *template.Template
+			if tmpl != "" {
+				var err error
+				// Parse up front so a bad template fails before any request
+				if t, err = template.New("request").Funcs(requestTemplateFuncs(nil)).Parse(tmpl); err != nil {
+					return fmt.Errorf("invalid --template: %w", err)
+				}
+			}
+
+			client := api.NewClient()
+			req, err := client.GetRequest(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case t != nil:
+				var b strings.Builder
+				if err := t.Funcs(requestTemplateFuncs(req)).Execute(&b, req); err != nil {
+					return err
+				}
+				out := b.String()
+				if !strings.HasSuffix(out, "\n") {
+					out += "\n"
+				}
+				fmt.Print(out)
+			case fields != "":
+				// Resolve every field before printing, so a typo doesn't
+				// leave a script with partial output
+				var values []string
+				for _, f := range strings.Split(fields, ",") {
+					v, err := requestField(req, strings.TrimSpace(f))
+					if err != nil {
+						return err
+					}
+					values = append(values, formatField(v))
+				}
+				fmt.Println(strings.Join(values, "\n"))
+			default:
+				data, err := json.MarshalIndent(req, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated dotted paths to print, e.g. method,path,headers.stripe-signature")
+	cmd.Flags().StringVar(&tmpl, "template", "", "Go template to render, e.g. '{{.Method}} {{.Path}}'")
+
+	return cmd
+}
+
+// requestTemplateFuncs returns the extra functions requests get --template
+// offers for req.
+func requestTemplateFuncs(req *types.CapturedRequest) template.FuncMap {
+	return template.FuncMap{
+		"header": func(name string) string {
+			if values := req.HeaderValues(name); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		},
+		"field": func(path string) (string, error) {
+			v, err := requestField(req, path)
+			return formatField(v), err
+		},
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"time": func(ms int64) string {
+			return time.UnixMilli(ms).Format(time.RFC3339)
+		},
+	}
+}
+
+// requestFieldAliases are the short names --fields accepts for JSON fields,
+// matching the search language's field names.
+var requestFieldAliases = map[string]string{
+	"id":    "_id",
+	"query": "queryParams",
+	"param": "pathParams",
+}
+
+// requestField returns the value at a dotted path into the request's JSON
+// form. Map keys match case-insensitively, and requestFieldAliases are
+// accepted too. Below body, the path reads from the decoded JSON body.
+func requestField(req *types.CapturedRequest, path string) (any, error) {
+	parts := strings.Split(path, ".")
+	if path == "" || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("invalid field %q", path)
+	}
+
+	var v any
+	if strings.EqualFold(parts[0], "body") {
+		body, err := req.BodyBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) == 1 {
+			return string(body), nil
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("field %q: body is not JSON", path)
+		}
+		parts = parts[1:]
+	} else {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if alias, ok := requestFieldAliases[strings.ToLower(parts[0])]; ok {
+			parts[0] = alias
+		}
+	}
+
+	for _, part := range parts {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				for k, val := range node {
+					if strings.EqualFold(k, part) {
+						next, ok = val, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("no field %q in request", path)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("no field %q in request", path)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("no field %q in request", path)
+		}
+	}
+	return v, nil
+}
+
+// formatField renders a field value for --fields: strings and numbers as
+// they are, null as an empty line, and objects and arrays as compact JSON.
+func formatField(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func requestsPinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "pin <request-id>",
+		Short:             "Pin a request so retention cleanup keeps it",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := api.NewClient()
+			if err := client.PinRequest(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Pinned request %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func requestsUnpinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unpin <request-id>",
+		Short:             "Remove the pin from a request",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := api.NewClient()
+			if err := client.UnpinRequest(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Unpinned request %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func requestsNoteCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "note <request-id> [text]",
+		Short: "Attach a note to a request",
+		Long: `Attach a note to a captured request, replacing any existing note.
+Notes are shown in the TUI request detail view.
+
+Example:
+  whk requests note <request-id> "reproduces #1234"
+  whk requests note <request-id> --clear`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var note string
+			if len(args) == 2 {
+				note = strings.TrimSpace(args[1])
+			}
+			if remove && note != "" {
+				return fmt.Errorf("--clear cannot be used with note text")
+			}
+			if !remove && note == "" {
+				return fmt.Errorf("note text is required (use --clear to remove a note)")
+			}
+
+			client := api.NewClient()
+			if err := client.SetRequestNote(cmd.Context(), args[0], note); err != nil {
+				return err
+			}
+			if remove {
+				fmt.Printf("Removed note from request %s\n", args[0])
+			} else {
+				fmt.Printf("Noted request %s\n", args[0])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "clear", false, "Remove the note")
+
+	return cmd
+}
+
+func requestsTagCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tag <request-id> <tag>...",
+		Short: "Add tags to a request",
+		Long: `Add tags to a captured request, to find it again with
+'whk requests list --tag' or a tag:<name> search term.
+
+Tags are case-insensitive and stored lowercase: up to 64 letters, digits
+and . _ : / -, starting with a letter or digit. A request has at most 20.
+
+Example:
+  whk requests tag <request-id> bug-1234
+  whk requests tag <request-id> env:staging stripe`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := normalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			client := api.NewClient()
+			all, err := client.UpdateRequestTags(cmd.Context(), args[0], tags, nil)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Tagged request %s: %s\n", args[0], strings.Join(all, ", "))
+			return nil
+		},
+	}
+}
+
+func requestsUntagCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "untag <request-id> <tag>...",
+		Short:             "Remove tags from a request",
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := normalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			client := api.NewClient()
+			all, err := client.UpdateRequestTags(cmd.Context(), args[0], nil, tags)
+			if err != nil {
+				return err
+			}
+			if len(all) == 0 {
+				fmt.Printf("Request %s has no tags\n", args[0])
+			} else {
+				fmt.Printf("Request %s is tagged %s\n", args[0], strings.Join(all, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// normalizeTags validates tags given on the command line, lowercasing them
+// and dropping duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	var out []string
+	for _, tag := range tags {
+		norm, ok := validation.NormalizeTag(tag)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag: %q (up to %d letters, digits and . _ : / -, starting with a letter or digit)", tag, validation.MaxTagLen)
+		}
+		if !slices.Contains(out, norm) {
+			out = append(out, norm)
+		}
+	}
+	if len(out) > validation.MaxTags {
+		return nil, fmt.Errorf("too many tags (max %d)", validation.MaxTags)
+	}
+	return out, nil
+}
+
+func requestsShareCmd() *cobra.Command {
+	var expires string
+
+	cmd := &cobra.Command{
+		Use:   "share <request-id>",
+		Short: "Create a public, expiring link to a request",
+		Long: `Create a public link to a captured request. Anyone with the link can
+view the request, without its sender IP and note, until it expires (at
+most 30 days), without a webhooks.cc account.
+
+Example:
+  whk requests share <request-id> --expires 24h`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRequestIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := parseDuration(expires)
+			if err != nil {
+				return err
+			}
+
+			client := api.NewClient()
+			share, err := client.ShareRequest(cmd.Context(), args[0], time.Now().Add(d).UnixMilli())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(share.URL)
+			expiresAt := share.ExpiresAt
+			if t, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+				expiresAt = t.Local().Format("2006-01-02 15:04")
+			}
+			// URL alone on stdout so it can be piped or captured
+			fmt.Fprintf(os.Stderr, "Expires %s\n", expiresAt)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&expires, "expires", "24h", "Link lifetime, e.g. 1h or 7d")
+
+	return cmd
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/tui"
+	"webhooks.cc/cli/internal/tui/screens"
+	"webhooks.cc/shared/types"
+)
+
+// screenFactories wires the screens package into the TUI.
+func screenFactories() tui.ScreenFactories {
+	return tui.ScreenFactories{
+		Menu: func(v string) tea.Model {
+			return screens.NewMenu(v)
+		},
+		Auth: func(c *api.Client) tea.Model {
+			return screens.NewAuth(c)
+		},
+		Endpoints: func(c *api.Client, mode string) tea.Model {
+			return screens.NewEndpoints(c, mode)
+		},
+		Update: func(v string) tea.Model {
+			return screens.NewUpdate(v)
+		},
+		Listen: func(c *api.Client, slug string) tea.Model {
+			return screens.NewListen(c, slug)
+		},
+		Tunnel: func(c *api.Client) tea.Model {
+			return screens.NewTunnel(c)
+		},
+		Detail: func(req *types.CapturedRequest) tea.Model {
+			return screens.NewDetail(req)
+		},
+		Requests: func(c *api.Client, slug string) tea.Model {
+			return screens.NewRequests(c, slug)
+		},
+	}
+}
+
+func tuiCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui [tunnel | listen [slug] | requests [slug]]",
+		Short: "Open the interactive UI, optionally on a specific screen",
+		Long: `Open the interactive UI. With no arguments it starts at the menu.
+
+  whk tui tunnel            Start the tunnel screen
+  whk tui listen <slug>     Stream requests for an endpoint
+  whk tui requests <slug>   Browse captured requests for an endpoint
+
+Omitting the slug for listen or requests shows an endpoint picker.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := parseTUITarget(args)
+			if err != nil {
+				return err
+			}
+			return tui.RunScreen(api.NewClient(), version, screenFactories(), start)
+		},
+	}
+}
+
+// parseTUITarget maps `whk tui` arguments to the screen to start on.
+func parseTUITarget(args []string) (tui.NavigateMsg, error) {
+	if len(args) == 0 {
+		return tui.NavigateMsg{Screen: tui.ScreenMenu}, nil
+	}
+
+	var screen tui.Screen
+	switch args[0] {
+	case "tunnel":
+		if len(args) > 1 {
+			return tui.NavigateMsg{}, fmt.Errorf("tunnel does not take a slug")
+		}
+		return tui.NavigateMsg{Screen: tui.ScreenTunnel}, nil
+	case "listen":
+		screen = tui.ScreenListen
+	case "requests":
+		screen = tui.ScreenRequests
+	default:
+		return tui.NavigateMsg{}, fmt.Errorf("unknown screen %q (expected tunnel, listen, or requests)", args[0])
+	}
+
+	if len(args) == 1 {
+		return tui.NavigateMsg{Screen: screen}, nil
+	}
+	slug, err := validateSlug(args[1])
+	if err != nil {
+		return tui.NavigateMsg{}, err
+	}
+	return tui.NavigateMsg{Screen: screen, Data: slug}, nil
+}
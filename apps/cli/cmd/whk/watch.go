@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/notify"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/cli/internal/watch"
+	"webhooks.cc/shared/search"
+	"webhooks.cc/shared/types"
+)
+
+const (
+	// notifyInterval is the least time between two notifications; requests
+	// arriving sooner are shown together in the next one
+	notifyInterval = 3 * time.Second
+	// watchStartupWait is how long `whk watch` waits for the background
+	// watcher to fail before reporting it started
+	watchStartupWait = 1500 * time.Millisecond
+)
+
+func watchCmd() *cobra.Command {
+	var (
+		notifyReqs bool
+		expr       string
+		filterName string
+		foreground bool
+		detached   bool
+		stop       bool
+		list       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch [slug]",
+		Short: "Watch an endpoint in the background and notify on each request",
+		Long: `Watch an endpoint in the background, without keeping a terminal busy.
+With --notify, each request raises a desktop notification with its method
+and path; a burst of requests is shown as one notification every few
+seconds. Every request is also logged to ~/.config/whk/watch/<slug>.log.
+
+Notifications use osascript on macOS, notify-send on Linux and a
+PowerShell toast on Windows.
+
+--foreground runs the watcher in the terminal instead, for example under
+a service manager. An endpoint has at most one watcher.
+
+Example:
+  whk watch my-endpoint --notify
+  whk watch my-endpoint --notify -q 'method:POST status:>=400'
+  whk watch --list
+  whk watch my-endpoint --stop`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				if len(args) > 0 || stop {
+					return fmt.Errorf("--list takes no slug and can't be combined with --stop")
+				}
+				return listWatchers()
+			}
+			if stop {
+				if len(args) == 0 {
+					return fmt.Errorf("--stop needs the slug of the endpoint to stop watching")
+				}
+				return stopWatcher(args[0])
+			}
+
+			q, err := resolveQuery(expr, filterName)
+			if err != nil {
+				return err
+			}
+			if notifyReqs {
+				if err := notify.Available(); err != nil {
+					return err
+				}
+			}
+			client := api.NewClient()
+			slug, err := slugArg(cmd.Context(), client, args)
+			if errors.Is(err, errPickCancelled) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if w, err := watch.Get(slug); err != nil {
+				return err
+			} else if w != nil && w.PID != os.Getpid() {
+				return fmt.Errorf("%s is already watched (pid %d); stop it with 'whk watch %s --stop'", slug, w.PID, slug)
+			}
+			token, err := auth.LoadToken()
+			if err != nil {
+				return fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
+			}
+
+			if !foreground {
+				childArgs := []string{"watch", slug, "--foreground", "--detached"}
+				if notifyReqs {
+					childArgs = append(childArgs, "--notify")
+				}
+				if !q.Empty() {
+					childArgs = append(childArgs, "--query", q.String())
+				}
+				return startWatcher(slug, childArgs)
+			}
+
+			if detached {
+				// Keep running when the terminal that started us closes
+				signal.Ignore(syscall.SIGHUP)
+			}
+			ctx, stopSignals := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stopSignals()
+			return runWatcher(ctx, client, token.AccessToken, slug, q, notifyReqs)
+		},
+	}
+
+	cmd.Flags().BoolVar(&notifyReqs, "notify", false, "Raise a desktop notification for each request")
+	cmd.Flags().StringVarP(&expr, "query", "q", "", "Only watch requests matching a search query")
+	cmd.Flags().StringVar(&filterName, "filter", "", "Only watch requests matching a saved filter (see 'whk filter')")
+	cmd.Flags().BoolVar(&foreground, "foreground", false, "Run the watcher in this terminal instead of the background")
+	cmd.Flags().BoolVar(&stop, "stop", false, "Stop the endpoint's watcher")
+	cmd.Flags().BoolVar(&list, "list", false, "List running watchers")
+	cmd.Flags().BoolVar(&detached, "detached", false, "")
+	_ = cmd.Flags().MarkHidden("detached")
+
+	return cmd
+}
+
+// startWatcher runs `whk watch` again in the background with args, its
+// output appended to the endpoint's watch log, and reports whether it
+// started.
+func startWatcher(slug string, args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logPath, err := watch.LogPath(slug)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logFile.Close() }()
+
+	child := exec.Command(exe, args...)
+	child.Stdout, child.Stderr = logFile, logFile
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start the watcher: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- child.Wait() }()
+	select {
+	case <-exited:
+		return fmt.Errorf("the watcher exited right away, see %s", logPath)
+	case <-time.After(watchStartupWait):
+	}
+
+	fmt.Printf("Watching %s in the background (pid %d)\n", slug, child.Process.Pid)
+	fmt.Printf("Log: %s\n", logPath)
+	fmt.Printf("Stop with: whk watch %s --stop\n", slug)
+	return nil
+}
+
+// runWatcher streams the endpoint's requests until ctx is done, logging
+// each one and notifying if asked to.
+func runWatcher(ctx context.Context, client *api.Client, token, slug string, q *search.Query, notifyReqs bool) error {
+	w := watch.Watcher{Slug: slug, PID: os.Getpid(), StartedAt: time.Now().UnixMilli(), Notify: notifyReqs, Query: q.String()}
+	if err := watch.Record(w); err != nil {
+		return err
+	}
+	defer func() { _ = watch.Remove(slug, w.PID) }()
+
+	fmt.Printf("%s  Watching %s/w/%s\n", time.Now().Format(time.DateTime), client.WebhookURL(), slug)
+	if !q.Empty() {
+		fmt.Printf("Showing requests matching: %s\n", q)
+	}
+
+	var notifications chan *types.CapturedRequest
+	if notifyReqs {
+		notifications = make(chan *types.CapturedRequest, 100)
+		go notifyRequests(ctx, slug, notifications)
+	}
+
+	s := stream.New(slug, client.BaseURL(), token)
+	err := s.Listen(ctx, func(req *types.CapturedRequest) {
+		if !q.Match(req) {
+			return
+		}
+		received := time.UnixMilli(req.ReceivedAt).Format(time.DateTime)
+		fmt.Printf("%s  %-7s %s  %s\n", received, req.Method, req.Path, stream.FormatBytes(req.Size))
+		if notifications != nil {
+			select {
+			case notifications <- req:
+			default:
+				// The notifier is stuck; the log still has the request
+			}
+		}
+	})
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		fmt.Printf("%s  Stopped watching\n", time.Now().Format(time.DateTime))
+		return nil
+	}
+	if errors.Is(err, stream.ErrEndpointDeleted) {
+		fmt.Fprintln(os.Stderr, "Endpoint was deleted")
+		return nil
+	}
+	return err
+}
+
+// notifyRequests raises a notification for the requests read from reqs.
+// After a notification, requests are held for notifyInterval and shown
+// together, so a burst doesn't flood the desktop.
+func notifyRequests(ctx context.Context, slug string, reqs <-chan *types.CapturedRequest) {
+	title := "whk: " + slug
+	var (
+		pending []*types.CapturedRequest
+		last    time.Time
+		timer   = time.NewTimer(0)
+		armed   = true
+	)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		if err := notify.Send(title, notify.Message(pending)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: notification failed: %v\n", err)
+		}
+		pending = nil
+		last = time.Now()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case req := <-reqs:
+			pending = append(pending, req)
+			if wait := notifyInterval - time.Since(last); wait <= 0 {
+				flush()
+			} else if !armed {
+				timer.Reset(wait)
+				armed = true
+			}
+		case <-timer.C:
+			armed = false
+			flush()
+		}
+	}
+}
+
+func listWatchers() error {
+	watchers, err := watch.List()
+	if err != nil {
+		return err
+	}
+	if len(watchers) == 0 {
+		fmt.Println("No endpoints are being watched")
+		return nil
+	}
+	fmt.Printf("%-30s %-8s %-19s %-7s %s\n", "SLUG", "PID", "STARTED", "NOTIFY", "QUERY")
+	for _, w := range watchers {
+		notifies := "no"
+		if w.Notify {
+			notifies = "yes"
+		}
+		started := time.UnixMilli(w.StartedAt).Format(time.DateTime)
+		fmt.Printf("%-30s %-8d %-19s %-7s %s\n", w.Slug, w.PID, started, notifies, w.Query)
+	}
+	return nil
+}
+
+func stopWatcher(arg string) error {
+	slug, err := validateSlug(arg)
+	if err != nil {
+		return err
+	}
+	w, err := watch.Get(slug)
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		fmt.Printf("%s is not being watched\n", slug)
+		return nil
+	}
+	if err := w.Stop(); err != nil {
+		return fmt.Errorf("failed to stop the watcher (pid %d): %w", w.PID, err)
+	}
+	fmt.Printf("Stopped watching %s\n", slug)
+	return nil
+}
@@ -7,16 +7,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/httpcache"
 	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
 )
 
 const (
@@ -27,11 +31,34 @@ const (
 	maxSuccessResponseSize = 10 * 1024 * 1024 // 10MB for success responses
 )
 
+// APIError is returned when the API responds with an error status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// DisableCache turns off the ETag cache of GET responses for clients
+// created afterwards (`whk --no-cache`). Setting WHK_NO_CACHE=1 does the
+// same.
+var DisableCache bool
+
 // Client provides methods to interact with the webhooks.cc API.
 // Create a new Client using NewClient().
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL          string
+	httpClient       *http.Client
+	onSessionExpired func()
+	// cache revalidates authenticated GETs with their ETags (see package
+	// httpcache)
+	cache bool
+
+	capsMu sync.Mutex
+	// caps is the server's capabilities once asked (see Capabilities)
+	caps *types.Capabilities
 }
 
 // NewClient creates a new API client. By default it connects to
@@ -58,6 +85,7 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: httpTimeout,
 		},
+		cache: !DisableCache && os.Getenv("WHK_NO_CACHE") != "1",
 	}
 }
 
@@ -81,10 +109,27 @@ func (c *Client) WebhookURL() string {
 	return defaultWebhookURL
 }
 
+// OnSessionExpired registers fn to be called when the API rejects the
+// stored token, after it has been marked expired. The TUI uses it to send
+// the user to the Auth screen.
+func (c *Client) OnSessionExpired(fn func()) {
+	c.onSessionExpired = fn
+}
+
+func (c *Client) sessionExpired() {
+	if c.onSessionExpired != nil {
+		c.onSessionExpired()
+	}
+}
+
 func (c *Client) getToken() (string, error) {
 	token, err := auth.LoadToken()
+	if errors.Is(err, auth.ErrSessionExpired) {
+		c.sessionExpired()
+		return "", err
+	}
 	if err != nil {
-		return "", fmt.Errorf("not logged in: %w", err)
+		return "", fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)
 	}
 	return token.AccessToken, nil
 }
@@ -117,7 +162,11 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(types.APIVersionHeader, types.APIVersion)
 
+	if c.cache && authenticated && method == http.MethodGet && result != nil {
+		return c.executeCachedRequest(req, httpcache.Key(req.URL.String(), token), result)
+	}
 	return c.executeRequest(req, result)
 }
 
@@ -128,24 +177,85 @@ func (c *Client) executeRequest(req *http.Request, result interface{}) error {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if err := c.checkResponse(req, resp); err != nil {
+		return err
+	}
+
+	if result != nil {
+		limitedReader := io.LimitReader(resp.Body, maxSuccessResponseSize)
+		if err := json.NewDecoder(limitedReader).Decode(result); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// executeCachedRequest makes a GET conditional on the ETag of the cached
+// response, if any, and decodes the cached body when the server answers
+// 304 Not Modified. Responses with an ETag are cached. Failing to read or
+// write the cache only costs a full transfer.
+func (c *Client) executeCachedRequest(req *http.Request, key string, result interface{}) error {
+	cached := httpcache.Get(key)
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if err := json.Unmarshal(cached.Body, result); err != nil {
+			_ = httpcache.Delete(key)
+			return fmt.Errorf("failed to parse cached response: %w", err)
+		}
+		return nil
+	}
+	if err := c.checkResponse(req, resp); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSuccessResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		_ = httpcache.Put(key, etag, data)
+	} else if cached != nil {
+		_ = httpcache.Delete(key)
+	}
+	return nil
+}
+
+// checkResponse turns an error status into an error.
+func (c *Client) checkResponse(req *http.Request, resp *http.Response) error {
+	// A 401 on an authenticated request means the stored token was revoked
+	// or has expired; every later request would fail the same way.
+	if resp.StatusCode == http.StatusUnauthorized && req.Header.Get("Authorization") != "" {
+		if err := auth.MarkExpired(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update stored token: %v\n", err)
+		}
+		c.sessionExpired()
+		return auth.ErrSessionExpired
+	}
+
 	if resp.StatusCode >= 400 {
 		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponseSize))
 		if err != nil {
-			return fmt.Errorf("API error (%d): failed to read response", resp.StatusCode)
+			return &APIError{StatusCode: resp.StatusCode, Message: "failed to read response"}
 		}
 		// Truncate long error bodies for readability
 		bodyStr := string(body)
 		if len(bodyStr) > 200 {
 			bodyStr = bodyStr[:200] + "..."
 		}
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, bodyStr)
-	}
-
-	if result != nil {
-		limitedReader := io.LimitReader(resp.Body, maxSuccessResponseSize)
-		if err := json.NewDecoder(limitedReader).Decode(result); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
-		}
+		return &APIError{StatusCode: resp.StatusCode, Message: bodyStr}
 	}
 
 	return nil
@@ -167,6 +277,8 @@ type DeviceCodeResponse struct {
 	UserCode        string `json:"userCode"`
 	ExpiresAt       int64  `json:"expiresAt"`
 	VerificationURL string `json:"verificationUrl"`
+	// Scopes echoes the requested scopes the user is asked to approve
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // PollResponse is returned by PollDeviceCode
@@ -179,12 +291,19 @@ type ClaimResponse struct {
 	APIKey string `json:"apiKey"`
 	UserID string `json:"userId"`
 	Email  string `json:"email"`
+	// Scopes the issued key is limited to; empty means full access
+	Scopes []string `json:"scopes,omitempty"`
 }
 
-// CreateDeviceCode initiates the device authorization flow
-func (c *Client) CreateDeviceCode(ctx context.Context) (*DeviceCodeResponse, error) {
+// CreateDeviceCode initiates the device authorization flow. Scopes (see
+// KeyScopes) limit what the issued key may do; none requests full access.
+func (c *Client) CreateDeviceCode(ctx context.Context, scopes ...string) (*DeviceCodeResponse, error) {
+	var body interface{}
+	if len(scopes) > 0 {
+		body = map[string][]string{"scopes": scopes}
+	}
 	var result DeviceCodeResponse
-	err := c.requestNoAuth(ctx, "POST", "/api/auth/device-code", nil, &result)
+	err := c.requestNoAuth(ctx, "POST", "/api/auth/device-code", body, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -227,6 +346,30 @@ type Endpoint struct {
 	URL        string      `json:"url"`
 	SharedWith []TeamShare `json:"sharedWith,omitempty"`
 	FromTeam   *TeamShare  `json:"fromTeam,omitempty"`
+	Retention  *Retention  `json:"retention,omitempty"`
+	// ExpiresAt is when the server deletes the endpoint (Unix ms), or 0.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// Aliases are additional slugs that capture into this endpoint.
+	Aliases []string `json:"aliases,omitempty"`
+	// PathPatterns extract path parameters from captured requests, in
+	// match order (see package pathpattern).
+	PathPatterns []string `json:"pathPatterns,omitempty"`
+	// Routes are sub-endpoints selected by path prefix.
+	Routes []types.EndpointRoute `json:"routes,omitempty"`
+	// MockResponse is what the receiver answers with, or nil for the default.
+	MockResponse *types.MockResponse `json:"mockResponse,omitempty"`
+	// RequestCount is how many requests the endpoint has captured.
+	RequestCount int `json:"requestCount,omitempty"`
+	// LastRequestAt is when the newest request was captured (Unix ms), or 0.
+	LastRequestAt int64 `json:"lastRequestAt,omitempty"`
+}
+
+// Retention limits how long captured requests are kept for an endpoint.
+// Requests are deleted once they are older than MaxAgeMs or beyond the
+// newest MaxCount; zero fields are unlimited (up to the plan's limits).
+type Retention struct {
+	MaxAgeMs int64 `json:"maxAgeMs,omitempty"`
+	MaxCount int   `json:"maxCount,omitempty"`
 }
 
 // endpointsResponse is the new response shape from GET /api/endpoints.
@@ -244,19 +387,87 @@ func (c *Client) CreateEndpoint(name string) (*Endpoint, error) {
 // If ephemeral is true, the endpoint will auto-expire after the server-configured TTL.
 // If name is empty, the server will use the generated slug as the display name.
 func (c *Client) CreateEndpointWithContext(ctx context.Context, name string, ephemeral bool) (*Endpoint, error) {
+	return c.CreateEndpointWithParams(ctx, CreateEndpointParams{Name: name, IsEphemeral: ephemeral})
+}
+
+// CreateEndpointParams configures a new endpoint. Zero fields use server defaults.
+type CreateEndpointParams struct {
+	Name         string              `json:"name,omitempty"`
+	Slug         string              `json:"slug,omitempty"` // custom slug; generated if empty
+	IsEphemeral  bool                `json:"isEphemeral,omitempty"`
+	MockResponse *types.MockResponse `json:"mockResponse,omitempty"`
+	Verification *Verification       `json:"verification,omitempty"`
+	// ExpiresAt (Unix ms) makes the server delete the endpoint at that time,
+	// even if the CLI that created it never cleans up.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// Verification configures provider signature checks on captured requests.
+type Verification struct {
+	Provider string `json:"provider"`
+	Secret   string `json:"secret,omitempty"`
+}
+
+// CreateEndpointWithParams creates a new endpoint, optionally with a mock
+// response and signature verification.
+func (c *Client) CreateEndpointWithParams(ctx context.Context, params CreateEndpointParams) (*Endpoint, error) {
+	if params.Slug != "" {
+		slug, err := customSlug(params.Slug)
+		if err != nil {
+			return nil, err
+		}
+		params.Slug = slug
+	}
 	var result Endpoint
-	body := map[string]interface{}{}
-	if name != "" {
-		body["name"] = name
+	err := c.request(ctx, "POST", "/api/endpoints", params, &result)
+	if err != nil {
+		return nil, slugConflict(err, params.Slug)
+	}
+	return &result, nil
+}
+
+// ErrSlugTaken is returned when a requested slug or alias is already used
+// by another endpoint.
+var ErrSlugTaken = errors.New("slug is already taken")
+
+// customSlug normalizes a user-chosen slug or alias and checks it against
+// the rules the server applies (validation.IsValidCustomSlug).
+func customSlug(slug string) (string, error) {
+	normalized, _ := validation.NormalizeSlug(slug)
+	if validation.ReservedSlugs[normalized] {
+		return "", fmt.Errorf("slug %q is reserved", slug)
+	}
+	if !validation.IsValidCustomSlug(normalized) {
+		return "", fmt.Errorf("invalid slug %q: use %d-%d characters of a-z, 0-9, - and _, starting and ending with a letter or digit",
+			slug, validation.MinCustomSlugLen, validation.MaxSlugLen)
 	}
-	if ephemeral {
-		body["isEphemeral"] = true
+	return normalized, nil
+}
+
+// slugConflict reports a 409 from creating a slug or alias as ErrSlugTaken.
+func slugConflict(err error, slug string) error {
+	var apiErr *APIError
+	if slug != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return fmt.Errorf("%w: %s", ErrSlugTaken, slug)
 	}
-	err := c.request(ctx, "POST", "/api/endpoints", body, &result)
+	return err
+}
+
+// AddEndpointAlias makes alias a second slug for the endpoint: requests to
+// /w/<alias> are captured by it as well.
+func (c *Client) AddEndpointAlias(ctx context.Context, slug, alias string) error {
+	alias, err := customSlug(alias)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &result, nil
+	body := map[string]string{"alias": alias}
+	err = c.request(ctx, "POST", "/api/endpoints/"+url.PathEscape(slug)+"/aliases", body, nil)
+	return slugConflict(err, alias)
+}
+
+// RemoveEndpointAlias removes an alias from the endpoint.
+func (c *Client) RemoveEndpointAlias(ctx context.Context, slug, alias string) error {
+	return c.request(ctx, "DELETE", "/api/endpoints/"+url.PathEscape(slug)+"/aliases/"+url.PathEscape(alias), nil, nil)
 }
 
 // ListEndpoints returns all endpoints for the user
@@ -289,6 +500,62 @@ func (c *Client) DeleteEndpointWithContext(ctx context.Context, slug string) err
 	return c.request(ctx, "DELETE", "/api/endpoints/"+url.PathEscape(slug), nil, nil)
 }
 
+// GetEndpoint fetches a single endpoint by slug
+func (c *Client) GetEndpoint(ctx context.Context, slug string) (*Endpoint, error) {
+	var result Endpoint
+	err := c.request(ctx, "GET", "/api/endpoints/"+url.PathEscape(slug), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetEndpointName changes an endpoint's display name. An empty name shows
+// the slug again.
+func (c *Client) SetEndpointName(ctx context.Context, slug, name string) error {
+	body := map[string]interface{}{"name": name}
+	return c.request(ctx, "PATCH", "/api/endpoints/"+url.PathEscape(slug), body, nil)
+}
+
+// SetEndpointRetention sets an endpoint's retention policy. A nil retention
+// restores the plan default.
+func (c *Client) SetEndpointRetention(ctx context.Context, slug string, retention *Retention) error {
+	body := map[string]interface{}{"retention": retention}
+	return c.request(ctx, "PATCH", "/api/endpoints/"+url.PathEscape(slug), body, nil)
+}
+
+// PurgeRequests immediately deletes all stored requests for an endpoint
+func (c *Client) PurgeRequests(ctx context.Context, slug string) error {
+	return c.request(ctx, "DELETE", "/api/endpoints/"+url.PathEscape(slug)+"/requests", nil, nil)
+}
+
+// SetEndpointMockResponse sets the response the receiver sends for captured
+// requests. A nil mock restores the default 200 OK.
+func (c *Client) SetEndpointMockResponse(ctx context.Context, slug string, mock *types.MockResponse) error {
+	body := map[string]interface{}{"mockResponse": mock}
+	return c.request(ctx, "PATCH", "/api/endpoints/"+url.PathEscape(slug), body, nil)
+}
+
+// SetEndpointPathPatterns replaces the path patterns the receiver uses to
+// extract path parameters from new captures. An empty list removes them.
+func (c *Client) SetEndpointPathPatterns(ctx context.Context, slug string, patterns []string) error {
+	if patterns == nil {
+		patterns = []string{}
+	}
+	body := map[string]interface{}{"pathPatterns": patterns}
+	return c.request(ctx, "PATCH", "/api/endpoints/"+url.PathEscape(slug), body, nil)
+}
+
+// SetEndpointRoutes replaces an endpoint's sub-endpoint routes. An empty
+// list sends every request to the endpoint's own mock response again.
+func (c *Client) SetEndpointRoutes(ctx context.Context, slug string, routes []types.EndpointRoute) error {
+	if routes == nil {
+		routes = []types.EndpointRoute{}
+	}
+	body := map[string]interface{}{"routes": routes}
+	return c.request(ctx, "PATCH", "/api/endpoints/"+url.PathEscape(slug), body, nil)
+}
+
 // --- Request methods ---
 
 // GetRequest fetches a single captured request by ID
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -100,6 +101,19 @@ func setupTestClient(t *testing.T, handler http.Handler) *Client {
 	}
 }
 
+// setupAuthedTestClient is like setupTestClient but also stores a token in a
+// temporary HOME so authenticated requests can be made.
+func setupAuthedTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	if err := auth.SaveToken(&auth.Token{AccessToken: "test-key"}); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	return setupTestClient(t, handler)
+}
+
 func TestCreateDeviceCode(t *testing.T) {
 	c := setupTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/auth/device-code" {
@@ -131,6 +145,29 @@ func TestCreateDeviceCode(t *testing.T) {
 	}
 }
 
+func TestCreateDeviceCode_Scopes(t *testing.T) {
+	c := setupTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Scopes []string `json:"scopes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if len(body.Scopes) != 1 || body.Scopes[0] != ScopeRead {
+			t.Errorf("scopes = %v, want [read]", body.Scopes)
+		}
+		_ = json.NewEncoder(w).Encode(DeviceCodeResponse{DeviceCode: "dev-code-123", Scopes: body.Scopes})
+	}))
+
+	resp, err := c.CreateDeviceCode(context.Background(), ScopeRead)
+	if err != nil {
+		t.Fatalf("CreateDeviceCode: %v", err)
+	}
+	if len(resp.Scopes) != 1 || resp.Scopes[0] != ScopeRead {
+		t.Errorf("Scopes = %v, want [read]", resp.Scopes)
+	}
+}
+
 func TestPollDeviceCode(t *testing.T) {
 	c := setupTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.HasPrefix(r.URL.Path, "/api/auth/device-poll") {
@@ -301,3 +338,86 @@ func TestNewClient_FtpSchemeRejected(t *testing.T) {
 		t.Errorf("FTP scheme should be rejected, got %q", c.BaseURL())
 	}
 }
+
+func TestUnauthorized_MarksSessionExpired(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid token"}`))
+	}))
+	notified := 0
+	c.OnSessionExpired(func() { notified++ })
+
+	_, err := c.ListEndpoints()
+	if !errors.Is(err, auth.ErrSessionExpired) {
+		t.Fatalf("expected ErrSessionExpired, got %v", err)
+	}
+	if !auth.SessionExpired() {
+		t.Error("expected the stored token to be marked expired")
+	}
+	if notified != 1 {
+		t.Errorf("expected OnSessionExpired to be called once, got %d", notified)
+	}
+
+	// Later requests fail without reaching the server
+	c.httpClient.Transport = roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("unexpected request with an expired session")
+		return nil, nil
+	})
+	if _, err := c.ListEndpoints(); !errors.Is(err, auth.ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+}
+
+func TestUnauthorized_UnauthenticatedRequestKeepsToken(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	if _, err := c.CreateDeviceCode(context.Background()); err == nil || errors.Is(err, auth.ErrSessionExpired) {
+		t.Fatalf("expected a plain API error, got %v", err)
+	}
+	if auth.SessionExpired() {
+		t.Error("a 401 on an unauthenticated request must not expire the session")
+	}
+}
+
+func TestCachedGet_RevalidatesWithETag(t *testing.T) {
+	var requests, notModified int
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"owned":[{"slug":"cached-ep"}]}`))
+	}))
+	c.cache = true
+
+	for i := range 2 {
+		endpoints, err := c.ListEndpointsWithContext(context.Background())
+		if err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+		if len(endpoints) != 1 || endpoints[0].Slug != "cached-ep" {
+			t.Fatalf("request %d: unexpected endpoints %+v", i+1, endpoints)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("expected the second request to be answered 304, got %d requests, %d not modified", requests, notModified)
+	}
+
+	// Without the cache no ETag is sent
+	c.cache = false
+	if _, err := c.ListEndpointsWithContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if notModified != 1 {
+		t.Error("expected an unconditional request with the cache disabled")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestGetEndpoint_Retention(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"id":"ep-1","slug":"my-slug","retention":{"maxAgeMs":604800000}}`))
+	}))
+
+	ep, err := c.GetEndpoint(context.Background(), "my-slug")
+	if err != nil {
+		t.Fatalf("GetEndpoint: %v", err)
+	}
+	if ep.Retention == nil || ep.Retention.MaxAgeMs != 604800000 || ep.Retention.MaxCount != 0 {
+		t.Errorf("unexpected retention: %+v", ep.Retention)
+	}
+}
+
+func TestSetEndpointRetention(t *testing.T) {
+	var bodies []map[string]any
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+	}))
+
+	if err := c.SetEndpointRetention(context.Background(), "my-slug", &Retention{MaxCount: 1000}); err != nil {
+		t.Fatalf("SetEndpointRetention: %v", err)
+	}
+	if err := c.SetEndpointRetention(context.Background(), "my-slug", nil); err != nil {
+		t.Fatalf("SetEndpointRetention (reset): %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if r, ok := bodies[0]["retention"].(map[string]any); !ok || r["maxCount"] != float64(1000) || r["maxAgeMs"] != nil {
+		t.Errorf("unexpected retention body: %v", bodies[0])
+	}
+	if v, ok := bodies[1]["retention"]; !ok || v != nil {
+		t.Errorf("reset should send a null retention, got %v", bodies[1])
+	}
+}
+
+func TestSetEndpointName(t *testing.T) {
+	var body map[string]any
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+
+	if err := c.SetEndpointName(context.Background(), "my-slug", "Stripe (staging)"); err != nil {
+		t.Fatalf("SetEndpointName: %v", err)
+	}
+	if body["name"] != "Stripe (staging)" || len(body) != 1 {
+		t.Errorf("unexpected body: %v", body)
+	}
+}
+
+func TestPurgeRequests(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/api/endpoints/my-slug/requests" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := c.PurgeRequests(context.Background(), "my-slug"); err != nil {
+		t.Fatalf("PurgeRequests: %v", err)
+	}
+}
+
+func TestSetEndpointPathPatterns(t *testing.T) {
+	var bodies []map[string]any
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	patterns := []string{"/orders/:id/events", "/files/*path"}
+	if err := c.SetEndpointPathPatterns(context.Background(), "my-slug", patterns); err != nil {
+		t.Fatalf("SetEndpointPathPatterns: %v", err)
+	}
+	if err := c.SetEndpointPathPatterns(context.Background(), "my-slug", nil); err != nil {
+		t.Fatalf("SetEndpointPathPatterns (clear): %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if got, _ := bodies[0]["pathPatterns"].([]any); len(got) != 2 || got[0] != patterns[0] {
+		t.Errorf("unexpected patterns: %v", bodies[0])
+	}
+	if got, ok := bodies[1]["pathPatterns"].([]any); !ok || len(got) != 0 {
+		t.Errorf("clearing should send an empty list, got %v", bodies[1])
+	}
+}
+
+func TestSetEndpointMockResponse(t *testing.T) {
+	var body struct {
+		MockResponse *types.MockResponse `json:"mockResponse"`
+	}
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	mock := &types.MockResponse{Status: 202, Body: `{"queued":true}`, Headers: map[string]string{"Content-Type": "application/json"}}
+	if err := c.SetEndpointMockResponse(context.Background(), "my-slug", mock); err != nil {
+		t.Fatalf("SetEndpointMockResponse: %v", err)
+	}
+	if body.MockResponse == nil || body.MockResponse.Status != 202 || body.MockResponse.Body != mock.Body {
+		t.Errorf("unexpected mock sent: %+v", body.MockResponse)
+	}
+}
+
+func TestSetEndpointRoutes(t *testing.T) {
+	var body struct {
+		Routes []types.EndpointRoute `json:"routes"`
+	}
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/endpoints/my-slug" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	routes := []types.EndpointRoute{
+		{Prefix: "/stripe", MockResponse: &types.MockResponse{Status: 200, Body: "ok"}},
+		{Prefix: "/github", ForwardURL: "https://example.com/hooks"},
+	}
+	if err := c.SetEndpointRoutes(context.Background(), "my-slug", routes); err != nil {
+		t.Fatalf("SetEndpointRoutes: %v", err)
+	}
+	if len(body.Routes) != 2 || body.Routes[0].MockResponse == nil || body.Routes[1].ForwardURL != routes[1].ForwardURL {
+		t.Errorf("unexpected routes sent: %+v", body.Routes)
+	}
+}
+
+func TestCreateEndpointWithParams_CustomSlug(t *testing.T) {
+	var requested []string
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params CreateEndpointParams
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		requested = append(requested, params.Slug)
+		if params.Slug == "taken-slug" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":"slug already exists"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Endpoint{Slug: params.Slug})
+	}))
+	ctx := context.Background()
+
+	ep, err := c.CreateEndpointWithParams(ctx, CreateEndpointParams{Slug: "My-Stripe-Dev"})
+	if err != nil {
+		t.Fatalf("CreateEndpointWithParams: %v", err)
+	}
+	if ep.Slug != "my-stripe-dev" {
+		t.Errorf("expected the slug to be normalized, got %q", ep.Slug)
+	}
+
+	if _, err := c.CreateEndpointWithParams(ctx, CreateEndpointParams{Slug: "taken-slug"}); !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("expected ErrSlugTaken, got %v", err)
+	}
+
+	for _, slug := range []string{"ab", "api", "-dash", "has space"} {
+		if _, err := c.CreateEndpointWithParams(ctx, CreateEndpointParams{Slug: slug}); err == nil {
+			t.Errorf("expected %q to be rejected", slug)
+		}
+	}
+	if len(requested) != 2 {
+		t.Errorf("invalid slugs should be rejected before any request, got %v", requested)
+	}
+}
+
+func TestEndpointAliases(t *testing.T) {
+	var calls []string
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["alias"] != "stripe-dev" {
+				t.Errorf("unexpected alias: %v", body)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	ctx := context.Background()
+
+	if err := c.AddEndpointAlias(ctx, "my-slug", "Stripe-Dev"); err != nil {
+		t.Fatalf("AddEndpointAlias: %v", err)
+	}
+	if err := c.RemoveEndpointAlias(ctx, "my-slug", "stripe-dev"); err != nil {
+		t.Fatalf("RemoveEndpointAlias: %v", err)
+	}
+	want := []string{"POST /api/endpoints/my-slug/aliases", "DELETE /api/endpoints/my-slug/aliases/stripe-dev"}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
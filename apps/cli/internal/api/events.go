@@ -0,0 +1,25 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"webhooks.cc/shared/types"
+)
+
+// --- Platform events ---
+
+// ListEvents returns the most recent platform events for an endpoint, oldest
+// first. A zero limit uses the server default.
+func (c *Client) ListEvents(ctx context.Context, slug string, limit int) ([]types.PlatformEvent, error) {
+	path := "/api/endpoints/" + url.PathEscape(slug) + "/events"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	var result []types.PlatformEvent
+	if err := c.request(ctx, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestListEvents(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/endpoints/my-slug/events" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "10" {
+			t.Errorf("limit = %q, want 10", r.URL.Query().Get("limit"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id":"evt-1","type":"quota_denied","message":"Monthly request limit reached","timestamp":1},
+			{"id":"evt-2","type":"mock_served","requestId":"req-1","message":"200","details":{"status":"200"},"timestamp":2}
+		]`))
+	}))
+
+	events, err := c.ListEvents(context.Background(), "my-slug", 10)
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != types.EventQuotaDenied || events[1].RequestID != "req-1" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
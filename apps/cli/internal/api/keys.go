@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/url"
+)
+
+// --- API keys ---
+
+// API key scopes. A key with no scopes has full access.
+const (
+	ScopeFull    = "full"
+	ScopeRead    = "read"
+	ScopeCapture = "capture"
+)
+
+// KeyScopes lists the scopes accepted by CreateAPIKey.
+var KeyScopes = []string{ScopeFull, ScopeRead, ScopeCapture}
+
+// APIKey is an API key as returned by GET /api/api-keys.
+// The raw key is never returned after creation; only its prefix.
+type APIKey struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	KeyPrefix  string   `json:"key_prefix"`
+	Scopes     []string `json:"scopes,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+}
+
+// CreateAPIKeyParams configures a new API key.
+// ExpiresAt is a Unix timestamp in milliseconds; zero uses the server default.
+type CreateAPIKeyParams struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes,omitempty"`
+	ExpiresAt int64    `json:"expiresAt,omitempty"`
+}
+
+// CreatedAPIKey is returned by CreateAPIKey. Key holds the raw secret and is
+// only available in this response.
+type CreatedAPIKey struct {
+	Key       string   `json:"key"`
+	Name      string   `json:"name"`
+	KeyPrefix string   `json:"keyPrefix"`
+	Scopes    []string `json:"scopes,omitempty"`
+	ExpiresAt string   `json:"expiresAt"`
+}
+
+// ListAPIKeys returns all API keys for the user
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var result []APIKey
+	err := c.request(ctx, "GET", "/api/api-keys", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateAPIKey creates a new, optionally scoped and expiring, API key
+func (c *Client) CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (*CreatedAPIKey, error) {
+	var result CreatedAPIKey
+	err := c.request(ctx, "POST", "/api/api-keys", params, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeAPIKey deletes an API key by ID
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) error {
+	return c.request(ctx, "DELETE", "/api/api-keys?id="+url.QueryEscape(id), nil, nil)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCreateAPIKey_SendsScopesAndExpiry(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/api-keys" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body CreateAPIKeyParams
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.Name != "ci" || len(body.Scopes) != 1 || body.Scopes[0] != ScopeRead || body.ExpiresAt != 1700000000000 {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		_ = json.NewEncoder(w).Encode(CreatedAPIKey{Key: "whk_secret", Name: "ci", KeyPrefix: "whk_sec", Scopes: body.Scopes})
+	}))
+
+	key, err := c.CreateAPIKey(context.Background(), CreateAPIKeyParams{
+		Name:      "ci",
+		Scopes:    []string{ScopeRead},
+		ExpiresAt: 1700000000000,
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if key.Key != "whk_secret" {
+		t.Errorf("Key = %q, want whk_secret", key.Key)
+	}
+}
+
+func TestListAPIKeys(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"k1","name":"ci","key_prefix":"whk_abc","scopes":["capture"],"created_at":"2026-01-01T00:00:00Z"}]`))
+	}))
+
+	keys, err := c.ListAPIKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListAPIKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].KeyPrefix != "whk_abc" || keys[0].Scopes[0] != ScopeCapture {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	var gotID string
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		gotID = r.URL.Query().Get("id")
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+
+	if err := c.RevokeAPIKey(context.Background(), "k&1"); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if gotID != "k&1" {
+		t.Errorf("id = %q, want k&1", gotID)
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"webhooks.cc/shared/types"
+)
+
+// --- Captured request history ---
+
+// RequestShare is an expiring public link to a captured request.
+type RequestShare struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// ListRequestsParams filters ListRequests. A zero Limit uses the server default.
+type ListRequestsParams struct {
+	Limit  int
+	Pinned bool
+	// Query is a search expression (see package webhooks.cc/shared/search),
+	// sent as q. The server doesn't evaluate it yet, so callers must filter
+	// the results themselves.
+	Query string
+}
+
+// RequestPage is one page of an endpoint's captured requests. Cursor fetches
+// the next page and is empty on the last one.
+type RequestPage struct {
+	Requests []types.CapturedRequest
+	Cursor   string
+}
+
+// requestRecord is a captured request as returned by the REST API, which
+// uses "id" where the stream payload uses "_id".
+type requestRecord struct {
+	RecordID string `json:"id"`
+	types.CapturedRequest
+}
+
+// ListRequests returns the most recent captured requests for an endpoint,
+// newest first.
+func (c *Client) ListRequests(ctx context.Context, slug string, params ListRequestsParams) ([]types.CapturedRequest, error) {
+	query := url.Values{}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Pinned {
+		query.Set("pinned", "true")
+	}
+	if params.Query != "" {
+		query.Set("q", params.Query)
+	}
+	path := "/api/endpoints/" + url.PathEscape(slug) + "/requests"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var records []requestRecord
+	if err := c.request(ctx, "GET", path, nil, &records); err != nil {
+		return nil, err
+	}
+	return toCapturedRequests(records), nil
+}
+
+// ListRequestsPage returns one page of an endpoint's captured requests
+// within the retention window, newest first. An empty cursor starts at the
+// newest request; a zero limit uses the server default.
+func (c *Client) ListRequestsPage(ctx context.Context, slug string, limit int, cursor string) (*RequestPage, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	path := "/api/endpoints/" + url.PathEscape(slug) + "/requests/paginated"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var page struct {
+		Items   []requestRecord `json:"items"`
+		Cursor  string          `json:"cursor"`
+		HasMore bool            `json:"hasMore"`
+	}
+	if err := c.request(ctx, "GET", path, nil, &page); err != nil {
+		return nil, err
+	}
+	result := &RequestPage{Requests: toCapturedRequests(page.Items)}
+	if page.HasMore {
+		result.Cursor = page.Cursor
+	}
+	return result, nil
+}
+
+func toCapturedRequests(records []requestRecord) []types.CapturedRequest {
+	result := make([]types.CapturedRequest, len(records))
+	for i, r := range records {
+		result[i] = r.CapturedRequest
+		if result[i].ID == "" {
+			result[i].ID = r.RecordID
+		}
+	}
+	return result
+}
+
+// PinRequest pins a captured request so retention cleanup keeps it
+func (c *Client) PinRequest(ctx context.Context, requestID string) error {
+	return c.request(ctx, "PUT", "/api/requests/"+url.PathEscape(requestID)+"/pin", nil, nil)
+}
+
+// UnpinRequest removes the pin from a captured request
+func (c *Client) UnpinRequest(ctx context.Context, requestID string) error {
+	return c.request(ctx, "DELETE", "/api/requests/"+url.PathEscape(requestID)+"/pin", nil, nil)
+}
+
+// SetRequestNote sets the note on a captured request. An empty note removes it.
+func (c *Client) SetRequestNote(ctx context.Context, requestID, note string) error {
+	body := map[string]string{"note": note}
+	return c.request(ctx, "PUT", "/api/requests/"+url.PathEscape(requestID)+"/note", body, nil)
+}
+
+// UpdateRequestTags adds and removes tags on a captured request and returns
+// its tags afterwards. Tags are normalized by the caller (see
+// validation.NormalizeTag).
+func (c *Client) UpdateRequestTags(ctx context.Context, requestID string, add, remove []string) ([]string, error) {
+	var result struct {
+		Tags []string `json:"tags"`
+	}
+	body := struct {
+		Add    []string `json:"add,omitempty"`
+		Remove []string `json:"remove,omitempty"`
+	}{add, remove}
+	if err := c.request(ctx, "PATCH", "/api/requests/"+url.PathEscape(requestID)+"/tags", body, &result); err != nil {
+		return nil, err
+	}
+	return result.Tags, nil
+}
+
+// ShareRequest creates a public share link for a captured request that
+// expires at expiresAt (Unix milliseconds).
+func (c *Client) ShareRequest(ctx context.Context, requestID string, expiresAt int64) (*RequestShare, error) {
+	var result RequestShare
+	body := map[string]int64{"expiresAt": expiresAt}
+	err := c.request(ctx, "POST", "/api/requests/"+url.PathEscape(requestID)+"/share", body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReportForwardAttempt records the outcome of forwarding a captured request
+// (e.g. by `whk tunnel --report`) so it shows whether it was delivered.
+func (c *Client) ReportForwardAttempt(ctx context.Context, attempt types.ForwardAttempt) error {
+	return c.request(ctx, "POST", "/api/requests/"+url.PathEscape(attempt.RequestID)+"/forwards", attempt, nil)
+}
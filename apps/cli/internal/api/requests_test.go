@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestListRequests(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/endpoints/my-slug/requests" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "25" {
+			t.Errorf("limit = %q, want 25", r.URL.Query().Get("limit"))
+		}
+		if r.URL.Query().Has("pinned") {
+			t.Errorf("pinned should only be sent when filtering")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id":"req-2","method":"POST","path":"/b","headers":{},"queryParams":{},"ip":"","size":2,"receivedAt":2},
+			{"id":"req-1","method":"GET","path":"/a","headers":{},"queryParams":{},"ip":"","size":0,"receivedAt":1}
+		]`))
+	}))
+
+	reqs, err := c.ListRequests(context.Background(), "my-slug", ListRequestsParams{Limit: 25})
+	if err != nil {
+		t.Fatalf("ListRequests: %v", err)
+	}
+	if len(reqs) != 2 || reqs[0].ID != "req-2" || reqs[1].Path != "/a" {
+		t.Errorf("unexpected requests: %+v", reqs)
+	}
+}
+
+func TestListRequests_Pinned(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pinned") != "true" {
+			t.Errorf("pinned = %q, want true", r.URL.Query().Get("pinned"))
+		}
+		_, _ = w.Write([]byte(`[{"id":"req-1","method":"POST","path":"/","headers":{},"queryParams":{},"ip":"","size":0,"receivedAt":1,"pinned":true}]`))
+	}))
+
+	reqs, err := c.ListRequests(context.Background(), "my-slug", ListRequestsParams{Pinned: true})
+	if err != nil {
+		t.Fatalf("ListRequests: %v", err)
+	}
+	if len(reqs) != 1 || !reqs[0].Pinned {
+		t.Errorf("unexpected requests: %+v", reqs)
+	}
+}
+
+func TestListRequests_Query(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != `method:POST body:"customer 123"` {
+			t.Errorf("q = %q", got)
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+
+	if _, err := c.ListRequests(context.Background(), "my-slug", ListRequestsParams{Query: `method:POST body:"customer 123"`}); err != nil {
+		t.Fatalf("ListRequests: %v", err)
+	}
+}
+
+func TestListRequestsPage(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/endpoints/my-slug/requests/paginated" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[{"id":"req-2","method":"POST","path":"/b","receivedAt":2}],"cursor":"next","hasMore":true}`))
+		case "next":
+			_, _ = w.Write([]byte(`{"items":[{"id":"req-1","method":"GET","path":"/a","receivedAt":1}],"hasMore":false}`))
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+
+	page, err := c.ListRequestsPage(context.Background(), "my-slug", 1, "")
+	if err != nil {
+		t.Fatalf("ListRequestsPage: %v", err)
+	}
+	if len(page.Requests) != 1 || page.Requests[0].ID != "req-2" || page.Cursor != "next" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	page, err = c.ListRequestsPage(context.Background(), "my-slug", 1, page.Cursor)
+	if err != nil {
+		t.Fatalf("ListRequestsPage: %v", err)
+	}
+	if len(page.Requests) != 1 || page.Requests[0].ID != "req-1" || page.Cursor != "" {
+		t.Errorf("unexpected last page: %+v", page)
+	}
+}
+
+func TestPinAndUnpinRequest(t *testing.T) {
+	var calls []string
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := c.PinRequest(context.Background(), "req-1"); err != nil {
+		t.Fatalf("PinRequest: %v", err)
+	}
+	if err := c.UnpinRequest(context.Background(), "req-1"); err != nil {
+		t.Fatalf("UnpinRequest: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "PUT /api/requests/req-1/pin" || calls[1] != "DELETE /api/requests/req-1/pin" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestSetRequestNote(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/requests/req-1/note" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["note"] != "reproduces #1234" {
+			t.Errorf("note = %q", body["note"])
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := c.SetRequestNote(context.Background(), "req-1", "reproduces #1234"); err != nil {
+		t.Fatalf("SetRequestNote: %v", err)
+	}
+}
+
+func TestUpdateRequestTags(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/api/requests/req-1/tags" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string][]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body["add"]) != 1 || body["add"][0] != "bug-1234" || len(body["remove"]) != 1 || body["remove"][0] != "triage" {
+			t.Errorf("body = %v", body)
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"tags": {"bug-1234", "stripe"}})
+	}))
+
+	tags, err := c.UpdateRequestTags(context.Background(), "req-1", []string{"bug-1234"}, []string{"triage"})
+	if err != nil {
+		t.Fatalf("UpdateRequestTags: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "bug-1234" || tags[1] != "stripe" {
+		t.Errorf("tags = %v", tags)
+	}
+}
+
+func TestShareRequest(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/requests/req-1/share" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]int64
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["expiresAt"] != 1700000000000 {
+			t.Errorf("expiresAt = %d", body["expiresAt"])
+		}
+		_ = json.NewEncoder(w).Encode(RequestShare{
+			URL:       "https://webhooks.cc/s/abc.sig",
+			ExpiresAt: "2023-11-14T22:13:20Z",
+		})
+	}))
+
+	share, err := c.ShareRequest(context.Background(), "req-1", 1700000000000)
+	if err != nil {
+		t.Fatalf("ShareRequest: %v", err)
+	}
+	if share.URL != "https://webhooks.cc/s/abc.sig" {
+		t.Errorf("URL = %q", share.URL)
+	}
+}
+
+func TestReportForwardAttempt(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/requests/req-1/forwards" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var got types.ForwardAttempt
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got.Source != types.ForwardSourceTunnel || got.StatusCode != 502 || got.LatencyMs != 12 {
+			t.Errorf("unexpected attempt: %+v", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	err := c.ReportForwardAttempt(context.Background(), types.ForwardAttempt{
+		RequestID:  "req-1",
+		Source:     types.ForwardSourceTunnel,
+		Target:     "http://localhost:3000/hook",
+		Attempt:    1,
+		StatusCode: 502,
+		LatencyMs:  12,
+	})
+	if err != nil {
+		t.Fatalf("ReportForwardAttempt: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"webhooks.cc/shared/types"
+)
+
+// --- Endpoint templates ---
+
+// EndpointTemplate presets an endpoint for a webhook provider: the mock
+// response the provider expects and the signature scheme it uses.
+type EndpointTemplate struct {
+	Description  string
+	MockResponse types.MockResponse
+	// SignatureHeader is the header the provider signs requests with.
+	SignatureHeader string
+}
+
+// EndpointTemplates are the presets accepted by `whk create --template`,
+// keyed by provider name (the same names the SDK uses for verification).
+var EndpointTemplates = map[string]EndpointTemplate{
+	"stripe": {
+		Description: "Stripe events (JSON ack, Stripe-Signature)",
+		MockResponse: types.MockResponse{
+			Status:  200,
+			Body:    `{"received":true}`,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		},
+		SignatureHeader: "Stripe-Signature",
+	},
+	"github": {
+		Description: "GitHub webhooks (empty 204, X-Hub-Signature-256)",
+		MockResponse: types.MockResponse{
+			Status:  204,
+			Headers: map[string]string{},
+		},
+		SignatureHeader: "X-Hub-Signature-256",
+	},
+	"twilio": {
+		Description: "Twilio callbacks (empty TwiML, X-Twilio-Signature)",
+		MockResponse: types.MockResponse{
+			Status:  200,
+			Body:    `<?xml version="1.0" encoding="UTF-8"?><Response></Response>`,
+			Headers: map[string]string{"Content-Type": "text/xml"},
+		},
+		SignatureHeader: "X-Twilio-Signature",
+	},
+}
+
+// TemplateNames returns the available endpoint template names, sorted.
+func TemplateNames() []string {
+	names := make([]string, 0, len(EndpointTemplates))
+	for name := range EndpointTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTemplate configures params from the named template. secret, if set,
+// is the provider's signing secret used to verify captured requests.
+func ApplyTemplate(params *CreateEndpointParams, name, secret string) error {
+	t, ok := EndpointTemplates[name]
+	if !ok {
+		return fmt.Errorf("unknown template: %s (must be one of %s)", name, strings.Join(TemplateNames(), ", "))
+	}
+	mock := t.MockResponse
+	params.MockResponse = &mock
+	params.Verification = &Verification{Provider: name, Secret: secret}
+	return nil
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	var params CreateEndpointParams
+	if err := ApplyTemplate(&params, "stripe", "whsec_test"); err != nil {
+		t.Fatalf("ApplyTemplate: %v", err)
+	}
+	if params.MockResponse == nil || params.MockResponse.Status != 200 || params.MockResponse.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected mock response: %+v", params.MockResponse)
+	}
+	if params.Verification == nil || params.Verification.Provider != "stripe" || params.Verification.Secret != "whsec_test" {
+		t.Errorf("unexpected verification: %+v", params.Verification)
+	}
+
+	if err := ApplyTemplate(&params, "unknown", ""); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestEndpointTemplates_ValidMockResponses(t *testing.T) {
+	for _, name := range TemplateNames() {
+		tmpl := EndpointTemplates[name]
+		if tmpl.MockResponse.Status < 200 || tmpl.MockResponse.Status > 299 {
+			t.Errorf("%s: providers expect a 2xx ack, got %d", name, tmpl.MockResponse.Status)
+		}
+		if tmpl.SignatureHeader == "" {
+			t.Errorf("%s: missing signature header", name)
+		}
+	}
+}
+
+func TestCreateEndpointWithParams_ExpiresAt(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["expiresAt"] != float64(1700000000000) {
+			t.Errorf("expiresAt = %v", body["expiresAt"])
+		}
+		_, _ = w.Write([]byte(`{"slug":"abc123","expiresAt":1700000000000}`))
+	}))
+
+	ep, err := c.CreateEndpointWithParams(context.Background(), CreateEndpointParams{ExpiresAt: 1700000000000})
+	if err != nil {
+		t.Fatalf("CreateEndpointWithParams: %v", err)
+	}
+	if ep.ExpiresAt != 1700000000000 {
+		t.Errorf("ExpiresAt = %d", ep.ExpiresAt)
+	}
+}
+
+func TestCreateEndpointWithParams(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mock, _ := body["mockResponse"].(map[string]any)
+		if body["name"] != "payments" || mock["status"] != float64(204) {
+			t.Errorf("unexpected body: %v", body)
+		}
+		if v, _ := body["verification"].(map[string]any); v["provider"] != "github" {
+			t.Errorf("unexpected verification: %v", body["verification"])
+		}
+		_ = json.NewEncoder(w).Encode(Endpoint{Slug: "abc123", Name: "payments"})
+	}))
+
+	params := CreateEndpointParams{Name: "payments"}
+	if err := ApplyTemplate(&params, "github", ""); err != nil {
+		t.Fatal(err)
+	}
+	ep, err := c.CreateEndpointWithParams(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CreateEndpointWithParams: %v", err)
+	}
+	if ep.Slug != "abc123" {
+		t.Errorf("Slug = %q", ep.Slug)
+	}
+}
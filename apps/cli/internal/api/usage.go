@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"webhooks.cc/shared/types"
+)
+
+// --- Usage ---
+
+// Usage is the account's request quota for the current billing period.
+type Usage struct {
+	Used      int    `json:"used"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Plan      string `json:"plan"`
+	// PeriodEnd is when the quota resets (Unix ms), or 0 if no period is active.
+	PeriodEnd int64 `json:"periodEnd,omitempty"`
+}
+
+// GetUsage returns the account's request quota usage
+func (c *Client) GetUsage(ctx context.Context) (*Usage, error) {
+	var result Usage
+	err := c.request(ctx, "GET", "/api/usage", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// --- Account ---
+
+// Me describes the account and token the client is authenticated with.
+type Me struct {
+	Email string `json:"email,omitempty"`
+	Plan  string `json:"plan"`
+	// Scopes limits what the token may do; empty means full access.
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is when the token expires (Unix ms), or 0 if it doesn't.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// GetMe asks the server about the stored token, which confirms it is still
+// accepted. Servers without /api/me are asked for usage instead, which
+// confirms the token and gives the plan but leaves the other fields empty.
+func (c *Client) GetMe(ctx context.Context) (*Me, error) {
+	var result Me
+	err := c.request(ctx, "GET", "/api/me", nil, &result)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		usage, err := c.GetUsage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &Me{Plan: usage.Plan}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// --- Server capabilities ---
+
+// Capabilities returns what the server reports about its wire format
+// versions and optional features, asking it once per client. Servers
+// without /api/capabilities predate it: they speak version 1 and have none
+// of the optional features, so callers fall back to what every server has.
+func (c *Client) Capabilities(ctx context.Context) (*types.Capabilities, error) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+	if c.caps != nil {
+		return c.caps, nil
+	}
+
+	var result types.Capabilities
+	err := c.requestNoAuth(ctx, "GET", "/api/capabilities", nil, &result)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		err = nil
+		result = types.Capabilities{}
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.caps = &result
+	return c.caps, nil
+}
+
+// HasFeature reports whether the server supports an optional feature (see
+// the Feature constants in package types). A server that can't be asked
+// is treated as not supporting it.
+func (c *Client) HasFeature(ctx context.Context, feature string) bool {
+	caps, err := c.Capabilities(ctx)
+	return err == nil && caps.Has(feature)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestGetUsage(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/usage" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"used":120,"limit":200,"remaining":80,"plan":"free","periodEnd":1700000000000}`))
+	}))
+
+	u, err := c.GetUsage(context.Background())
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if u.Used != 120 || u.Limit != 200 || u.Remaining != 80 || u.Plan != "free" || u.PeriodEnd != 1700000000000 {
+		t.Errorf("unexpected usage: %+v", u)
+	}
+}
+
+func TestGetMe(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/me" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"email":"a@example.com","plan":"pro","scopes":["read"],"expiresAt":1700000000000}`))
+	}))
+
+	me, err := c.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+	if me.Email != "a@example.com" || me.Plan != "pro" || len(me.Scopes) != 1 || me.ExpiresAt != 1700000000000 {
+		t.Errorf("unexpected account: %+v", me)
+	}
+}
+
+func TestGetMe_FallsBackToUsage(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/me" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"used":1,"limit":200,"remaining":199,"plan":"free"}`))
+	}))
+
+	me, err := c.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+	if me.Plan != "free" {
+		t.Errorf("expected the plan from usage, got %+v", me)
+	}
+}
+
+func TestListEndpoints_RequestCounts(t *testing.T) {
+	c := setupAuthedTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"owned":[{"slug":"a","requestCount":42,"lastRequestAt":1700000000000},{"slug":"b"}],"shared":[]}`))
+	}))
+
+	eps, err := c.ListEndpointsWithContext(context.Background())
+	if err != nil {
+		t.Fatalf("ListEndpoints: %v", err)
+	}
+	if len(eps) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(eps))
+	}
+	if eps[0].RequestCount != 42 || eps[0].LastRequestAt != 1700000000000 {
+		t.Errorf("unexpected counts: %+v", eps[0])
+	}
+	if eps[1].RequestCount != 0 || eps[1].LastRequestAt != 0 {
+		t.Errorf("missing counts should be zero: %+v", eps[1])
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	calls := 0
+	c := setupTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/capabilities" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.Header.Get(types.APIVersionHeader); got != types.APIVersion {
+			t.Errorf("expected %s %s, got %q", types.APIVersionHeader, types.APIVersion, got)
+		}
+		_, _ = w.Write([]byte(`{"versions":["1"],"features":["pagination"]}`))
+	}))
+
+	if !c.HasFeature(context.Background(), types.FeaturePagination) {
+		t.Error("expected pagination to be supported")
+	}
+	if c.HasFeature(context.Background(), types.FeatureReplay) {
+		t.Error("expected replay not to be supported")
+	}
+	if calls != 1 {
+		t.Errorf("expected capabilities to be fetched once, got %d calls", calls)
+	}
+}
+
+func TestCapabilities_OlderServer(t *testing.T) {
+	c := setupTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+
+	caps, err := c.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if len(caps.Features) != 0 || !caps.Supports(types.APIVersion) {
+		t.Errorf("expected a version 1 server without optional features, got %+v", caps)
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 )
@@ -9,10 +10,24 @@ import (
 const configDir = ".config/whk"
 const tokenFile = "token.json"
 
+// ErrSessionExpired is returned when the API rejects the stored token, and
+// by LoadToken once the token has been marked expired.
+var ErrSessionExpired = errors.New("your session expired, run `whk auth login`")
+
+// ErrNotLoggedIn wraps the error from LoadToken when a command needs a
+// stored token and none could be read.
+var ErrNotLoggedIn = errors.New("not logged in")
+
 type Token struct {
 	AccessToken string `json:"access_token"`
 	UserID      string `json:"user_id"`
 	Email       string `json:"email"`
+	// Scopes limits what the token may do; empty means full access.
+	Scopes []string `json:"scopes,omitempty"`
+	// Expired is set when the API rejected the token. The token is kept so
+	// the CLI can say the session expired rather than that it was never
+	// logged in.
+	Expired bool `json:"expired,omitempty"`
 }
 
 // GetConfigPath returns the path to the config directory
@@ -24,7 +39,8 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(home, configDir), nil
 }
 
-// SaveToken saves the authentication token
+// SaveToken saves the authentication token. The file is locked and replaced
+// atomically, so concurrent whk processes can't leave it half-written.
 func SaveToken(token *Token) error {
 	configPath, err := GetConfigPath()
 	if err != nil {
@@ -40,11 +56,26 @@ func SaveToken(token *Token) error {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(configPath, tokenFile), data, 0600)
+	path := filepath.Join(configPath, tokenFile)
+	return WithFileLock(path, func() error {
+		return WriteFileAtomic(path, data, 0600)
+	})
 }
 
-// LoadToken loads the authentication token
+// LoadToken loads the authentication token. It returns ErrSessionExpired if
+// the token was marked expired.
 func LoadToken() (*Token, error) {
+	token, err := readToken()
+	if err != nil {
+		return nil, err
+	}
+	if token.Expired {
+		return nil, ErrSessionExpired
+	}
+	return token, nil
+}
+
+func readToken() (*Token, error) {
 	configPath, err := GetConfigPath()
 	if err != nil {
 		return nil, err
@@ -63,6 +94,38 @@ func LoadToken() (*Token, error) {
 	return &token, nil
 }
 
+// MarkExpired flags the stored token as rejected by the API, so later
+// commands report an expired session until the user logs in again. It does
+// nothing if no token is stored.
+func MarkExpired() error {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(configPath, tokenFile)
+	return WithFileLock(path, func() error {
+		token, err := readToken()
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil || token.Expired {
+			return err
+		}
+		token.Expired = true
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		return WriteFileAtomic(path, data, 0600)
+	})
+}
+
+// SessionExpired reports whether the stored token was marked expired.
+func SessionExpired() bool {
+	token, err := readToken()
+	return err == nil && token.Expired
+}
+
 // ClearToken removes the stored token
 func ClearToken() error {
 	configPath, err := GetConfigPath()
@@ -70,7 +133,10 @@ func ClearToken() error {
 		return err
 	}
 
-	return os.Remove(filepath.Join(configPath, tokenFile))
+	path := filepath.Join(configPath, tokenFile)
+	return WithFileLock(path, func() error {
+		return os.Remove(path)
+	})
 }
 
 // IsLoggedIn checks if user is authenticated
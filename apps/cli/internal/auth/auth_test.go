@@ -2,6 +2,7 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -204,3 +205,41 @@ func TestLoadToken_ValidJSON(t *testing.T) {
 		t.Errorf("AccessToken = %q, want tok-abc", got.AccessToken)
 	}
 }
+
+func TestMarkExpired(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	// No stored token is not an error
+	if err := MarkExpired(); err != nil {
+		t.Fatalf("MarkExpired without token: %v", err)
+	}
+
+	if err := SaveToken(&Token{AccessToken: "old", Email: "test@example.com"}); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	if err := MarkExpired(); err != nil {
+		t.Fatalf("MarkExpired: %v", err)
+	}
+
+	if _, err := LoadToken(); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired from LoadToken, got %v", err)
+	}
+	if IsLoggedIn() {
+		t.Error("expected IsLoggedIn to be false for an expired token")
+	}
+	if !SessionExpired() {
+		t.Error("expected SessionExpired to be true")
+	}
+
+	// Logging in again replaces the expired token
+	if err := SaveToken(&Token{AccessToken: "new"}); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+	if tok, err := LoadToken(); err != nil || tok.AccessToken != "new" {
+		t.Errorf("expected fresh token after login, got %+v, %v", tok, err)
+	}
+	if SessionExpired() {
+		t.Error("expected SessionExpired to be false after login")
+	}
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockRetryInterval = 10 * time.Millisecond
+	lockTimeout       = 5 * time.Second
+)
+
+// ErrLockTimeout is returned when a config file stays locked by another
+// whk process for longer than the lock timeout.
+var ErrLockTimeout = errors.New("timed out waiting for config file lock")
+
+// WriteFileAtomic writes data to path by writing a temporary file in the
+// same directory and renaming it over path, so concurrent readers see either
+// the old or the new contents, never a partial write.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	// Clean up the temp file if anything below fails
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// WithFileLock runs fn while holding an exclusive lock on path+".lock", so
+// that read-modify-write cycles from concurrent whk processes don't
+// overwrite each other. Every writer of path must take it for the lock to be
+// effective. The lock is an OS file lock (flock on Unix, LockFileEx on
+// Windows), so it is released if the process dies while holding it; the lock
+// file itself is left in place.
+func WithFileLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s", ErrLockTimeout, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	return fn()
+}
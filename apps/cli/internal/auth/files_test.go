@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := WriteFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "new" {
+		t.Errorf("expected new contents, got %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("expected file permissions 0644, got %o", perm)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temp files to be cleaned up, got %d entries", len(entries))
+	}
+}
+
+func TestWithFileLock_SerializesUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	if err := os.WriteFile(path, []byte("0"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithFileLock(path, func() error {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				n, err := strconv.Atoi(string(data))
+				if err != nil {
+					return err
+				}
+				return WriteFileAtomic(path, []byte(strconv.Itoa(n+1)), 0600)
+			})
+			if err != nil {
+				t.Errorf("WithFileLock: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != strconv.Itoa(workers) {
+		t.Errorf("expected %d updates, got %s", workers, data)
+	}
+}
+
+func TestWithFileLock_IgnoresLeftoverLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	// A lock file left behind by an earlier process doesn't hold the lock
+	if err := os.WriteFile(path+".lock", nil, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	ran := false
+	if err := WithFileLock(path, func() error { ran = true; return nil }); err != nil {
+		t.Fatalf("WithFileLock: %v", err)
+	}
+	if !ran {
+		t.Error("expected fn to run despite the leftover lock file")
+	}
+}
@@ -0,0 +1,222 @@
+// Package bench generates webhook load against an endpoint or a local
+// handler at a fixed request rate and reports latency percentiles and
+// error rates.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultTimeout bounds a single benchmark request
+const defaultTimeout = 30 * time.Second
+
+// Config describes a benchmark run.
+type Config struct {
+	// TargetURL receives every request.
+	TargetURL string
+	// Method defaults to POST.
+	Method string
+	// RPS is the number of requests started per second.
+	RPS int
+	// Duration is how long requests are started for.
+	Duration time.Duration
+	// MaxInFlight caps concurrent requests; ticks that would exceed it are
+	// counted as skipped rather than queued. Defaults to 4*RPS.
+	MaxInFlight int
+	// Body is sent with every request. If nil, a synthetic webhook event
+	// with a unique ID is generated per request.
+	Body []byte
+	// Headers are added to every request.
+	Headers http.Header
+	// Timeout bounds a single request. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// Report summarizes a benchmark run.
+type Report struct {
+	Sent     int
+	Skipped  int
+	Errors   int
+	Statuses map[int]int
+	Elapsed  time.Duration
+
+	// Latency percentiles over requests that received a response
+	P50, P90, P99, Max time.Duration
+}
+
+// Succeeded returns the number of requests that got a 2xx response.
+func (r *Report) Succeeded() int {
+	n := 0
+	for status, count := range r.Statuses {
+		if status >= 200 && status < 300 {
+			n += count
+		}
+	}
+	return n
+}
+
+// ErrorRate returns the share of sent requests that failed or got a non-2xx
+// response, between 0 and 1.
+func (r *Report) ErrorRate() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Succeeded()) / float64(r.Sent)
+}
+
+// Run sends requests at cfg.RPS until cfg.Duration elapses or ctx is
+// cancelled, then waits for in-flight requests and returns the report.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.TargetURL == "" {
+		return nil, errors.New("target URL is required")
+	}
+	if cfg.RPS <= 0 {
+		return nil, errors.New("rps must be greater than 0")
+	}
+	if cfg.Duration <= 0 {
+		return nil, errors.New("duration must be greater than 0")
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 4 * cfg.RPS
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: cfg.MaxInFlight,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies []time.Duration
+		report    = &Report{Statuses: map[int]int{}}
+		inFlight  = make(chan struct{}, cfg.MaxInFlight)
+	)
+
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.RPS))
+	defer ticker.Stop()
+
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	start := time.Now()
+loop:
+	for {
+		select {
+		case <-runCtx.Done():
+			break loop
+		case <-ticker.C:
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			report.Skipped++
+			continue
+		}
+
+		report.Sent++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			status, latency, err := send(ctx, client, cfg)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Errors++
+				return
+			}
+			report.Statuses[status]++
+			latencies = append(latencies, latency)
+		}()
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.P50 = percentile(latencies, 50)
+	report.P90 = percentile(latencies, 90)
+	report.P99 = percentile(latencies, 99)
+	if len(latencies) > 0 {
+		report.Max = latencies[len(latencies)-1]
+	}
+
+	if ctx.Err() != nil {
+		return report, ctx.Err()
+	}
+	return report, nil
+}
+
+// send performs one request and returns its status and latency.
+func send(ctx context.Context, client *http.Client, cfg Config) (int, time.Duration, error) {
+	body := cfg.Body
+	if body == nil {
+		body = syntheticEvent()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	for k, values := range cfg.Headers {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode, time.Since(start), nil
+}
+
+// syntheticEvent returns a small webhook-style JSON event with a unique ID,
+// so receivers that deduplicate on event ID see distinct deliveries.
+func syntheticEvent() []byte {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Appendf(nil,
+		`{"id":"evt_bench_%s","type":"bench.event","created":%d,"data":{"object":{"amount":2000,"currency":"usd","status":"succeeded"}}}`,
+		hex.EncodeToString(b), time.Now().Unix())
+}
+
+// percentile returns the p-th percentile of sorted latencies (nearest rank).
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
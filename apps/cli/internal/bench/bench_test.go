@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun_CountsStatusesAndErrors(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ids = map[string]bool{}
+		n   int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("synthetic body is not JSON: %v", err)
+		}
+		if r.Header.Get("X-Test") != "1" {
+			t.Errorf("missing custom header")
+		}
+
+		mu.Lock()
+		ids[event.ID] = true
+		n++
+		fail := n%4 == 0
+		mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	report, err := Run(context.Background(), Config{
+		TargetURL: server.URL,
+		RPS:       200,
+		Duration:  200 * time.Millisecond,
+		Headers:   http.Header{"X-Test": {"1"}},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if report.Sent == 0 || report.Errors != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Statuses[200]+report.Statuses[500] != report.Sent {
+		t.Errorf("statuses %v don't add up to %d sent", report.Statuses, report.Sent)
+	}
+	if report.Statuses[500] == 0 || report.ErrorRate() == 0 {
+		t.Errorf("expected some 500s to count as errors, got %+v", report)
+	}
+	if len(ids) != report.Sent {
+		t.Errorf("expected a unique event ID per request, got %d IDs for %d requests", len(ids), report.Sent)
+	}
+	if report.P50 > report.P99 || report.P99 > report.Max {
+		t.Errorf("percentiles out of order: p50=%v p99=%v max=%v", report.P50, report.P99, report.Max)
+	}
+}
+
+func TestRun_ConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	report, err := Run(context.Background(), Config{
+		TargetURL: url,
+		RPS:       50,
+		Duration:  100 * time.Millisecond,
+		Body:      []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Sent == 0 || report.Errors != report.Sent || report.ErrorRate() != 1 {
+		t.Errorf("expected every request to fail, got %+v", report)
+	}
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	tests := []Config{
+		{RPS: 1, Duration: time.Second},
+		{TargetURL: "http://x", Duration: time.Second},
+		{TargetURL: "http://x", RPS: 1},
+	}
+	for _, cfg := range tests {
+		if _, err := Run(context.Background(), cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentile(sorted, 50); got != 50*time.Millisecond {
+		t.Errorf("p50 = %v", got)
+	}
+	if got := percentile(sorted, 99); got != 99*time.Millisecond {
+		t.Errorf("p99 = %v", got)
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("empty p50 = %v", got)
+	}
+}
@@ -0,0 +1,212 @@
+// Package cache keeps a local copy of captured requests received by
+// `whk listen --cache` and `whk tunnel --cache`, so they can be inspected
+// offline or after the platform's retention window has deleted them.
+//
+// Each endpoint's requests are appended as JSON lines to
+// cache/<slug>.jsonl in the whk config directory.
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/shared/types"
+)
+
+const (
+	cacheDir = "cache"
+	// MaxPerEndpoint is how many requests are kept for each endpoint. The
+	// file may grow to twice this before the oldest requests are dropped.
+	MaxPerEndpoint = 1000
+)
+
+// Path returns the cache file for an endpoint.
+func Path(slug string) (string, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configPath, cacheDir, slug+".jsonl"), nil
+}
+
+// Cache appends requests to one endpoint's cache file. It is safe for
+// concurrent use.
+type Cache struct {
+	path string
+
+	mu sync.Mutex
+	// lines approximates the number of lines in the file, to decide when
+	// to compact it
+	lines int
+}
+
+// Open opens the cache for an endpoint, creating it on the first Add.
+func Open(slug string) (*Cache, error) {
+	path, err := Path(slug)
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{path: path, lines: len(reqs)}, nil
+}
+
+// Add appends a request to the cache.
+func (c *Cache) Add(req *types.CapturedRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return auth.WithFileLock(c.path, func() error {
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		c.lines++
+		if c.lines > 2*MaxPerEndpoint {
+			return c.compact()
+		}
+		return nil
+	})
+}
+
+// compact rewrites the file with only the newest MaxPerEndpoint requests.
+// The caller holds the file lock.
+func (c *Cache) compact() error {
+	reqs, err := load(c.path)
+	if err != nil {
+		return err
+	}
+	if len(reqs) > MaxPerEndpoint {
+		reqs = reqs[len(reqs)-MaxPerEndpoint:]
+	}
+	var data []byte
+	for i := range reqs {
+		line, err := json.Marshal(&reqs[i])
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := auth.WriteFileAtomic(c.path, data, 0600); err != nil {
+		return err
+	}
+	c.lines = len(reqs)
+	return nil
+}
+
+// List returns an endpoint's cached requests, newest first. A limit of 0
+// returns all of them. An endpoint that was never cached has none.
+func List(slug string, limit int) ([]types.CapturedRequest, error) {
+	path, err := Path(slug)
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(reqs, func(i, j int) bool { return reqs[i].ReceivedAt > reqs[j].ReceivedAt })
+	if limit > 0 && len(reqs) > limit {
+		reqs = reqs[:limit]
+	}
+	return reqs, nil
+}
+
+// Entry is a cached request and the endpoint it was cached for.
+type Entry struct {
+	Slug    string
+	Request types.CapturedRequest
+}
+
+// Recent returns the newest cached requests of every endpoint, newest
+// first, up to limit (0 for all of them).
+func Recent(limit int) ([]Entry, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(filepath.Join(configPath, cacheDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range files {
+		slug, ok := strings.CutSuffix(f.Name(), ".jsonl")
+		if !ok || f.IsDir() {
+			continue
+		}
+		reqs, err := List(slug, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			entries = append(entries, Entry{Slug: slug, Request: req})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Request.ReceivedAt > entries[j].Request.ReceivedAt })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// load reads a cache file in the order requests were added. A request
+// cached more than once keeps its latest copy. A missing file is empty, and
+// lines that can't be decoded (say, cut short by a crash) are skipped.
+func load(path string) ([]types.CapturedRequest, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var reqs []types.CapturedRequest
+	index := map[string]int{}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var req types.CapturedRequest
+			if json.Unmarshal(line, &req) == nil {
+				if i, ok := index[req.ID]; ok && req.ID != "" {
+					reqs[i] = req
+				} else {
+					index[req.ID] = len(reqs)
+					reqs = append(reqs, req)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestCache_AddAndList(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	c, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for i := range 3 {
+		req := &types.CapturedRequest{ID: fmt.Sprintf("r%d", i), Method: "POST", Path: "/hook", ReceivedAt: int64(1000 + i)}
+		if err := c.Add(req); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	// A request cached again (e.g. by listen and tunnel both) keeps one copy
+	if err := c.Add(&types.CapturedRequest{ID: "r1", Method: "POST", Path: "/hook", ReceivedAt: 1001, Pinned: true}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	reqs, err := List("my-ep", 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(reqs) != 3 || reqs[0].ID != "r2" || reqs[2].ID != "r0" {
+		t.Fatalf("expected r2, r1, r0, got %+v", reqs)
+	}
+	if !reqs[1].Pinned {
+		t.Error("the latest copy of a request should win")
+	}
+
+	if reqs, _ := List("my-ep", 2); len(reqs) != 2 {
+		t.Errorf("limit 2: got %d requests", len(reqs))
+	}
+	if reqs, err := List("other", 0); err != nil || len(reqs) != 0 {
+		t.Errorf("an uncached endpoint should be empty, got %v, %v", reqs, err)
+	}
+
+	path, _ := Path("my-ep")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected file permissions 0600, got %o", perm)
+	}
+}
+
+func TestRecent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if entries, err := Recent(10); err != nil || len(entries) != 0 {
+		t.Fatalf("expected no entries before anything is cached, got %v, %v", entries, err)
+	}
+	for slug, times := range map[string][]int64{"stripe-dev": {100, 300}, "github-dev": {200, 400}} {
+		c, err := Open(slug)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		for _, at := range times {
+			if err := c.Add(&types.CapturedRequest{ID: fmt.Sprintf("%s-%d", slug, at), ReceivedAt: at}); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+		}
+	}
+
+	entries, err := Recent(3)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	want := []string{"github-dev-400", "stripe-dev-300", "github-dev-200"}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, id := range want {
+		if entries[i].Request.ID != id {
+			t.Errorf("entry %d = %s, want %s", i, entries[i].Request.ID, id)
+		}
+	}
+	if entries[0].Slug != "github-dev" || entries[1].Slug != "stripe-dev" {
+		t.Errorf("entries should carry their endpoint, got %s and %s", entries[0].Slug, entries[1].Slug)
+	}
+}
+
+func TestCache_SkipsDamagedLines(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := c.Add(&types.CapturedRequest{ID: "r1", ReceivedAt: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// Simulate a write cut short by a crash, followed by a later append
+	path, _ := Path("my-ep")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	_, _ = f.WriteString(`{"_id":"r2","meth` + "\n")
+	_ = f.Close()
+	if err := c.Add(&types.CapturedRequest{ID: "r3", ReceivedAt: 3}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	reqs, err := List("my-ep", 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(reqs) != 2 || reqs[0].ID != "r3" || reqs[1].ID != "r1" {
+		t.Errorf("expected r3, r1, got %+v", reqs)
+	}
+}
+
+func TestCache_Compacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	total := 2*MaxPerEndpoint + 1
+	for i := range total {
+		if err := c.Add(&types.CapturedRequest{ID: fmt.Sprintf("r%d", i), ReceivedAt: int64(i)}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	reqs, err := List("my-ep", 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(reqs) != MaxPerEndpoint {
+		t.Fatalf("expected %d requests after compaction, got %d", MaxPerEndpoint, len(reqs))
+	}
+	if want := fmt.Sprintf("r%d", total-1); reqs[0].ID != want {
+		t.Errorf("newest request = %s, want %s", reqs[0].ID, want)
+	}
+	if want := fmt.Sprintf("r%d", total-MaxPerEndpoint); reqs[len(reqs)-1].ID != want {
+		t.Errorf("oldest request = %s, want %s", reqs[len(reqs)-1].ID, want)
+	}
+}
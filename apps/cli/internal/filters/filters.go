@@ -0,0 +1,150 @@
+// Package filters stores named search queries ("saved filters") in the whk
+// config directory so they can be reused by name, e.g.
+// `whk listen <slug> --filter failed-stripe`.
+package filters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/shared/search"
+)
+
+const filtersFile = "filters.json"
+
+// ErrNotFound is returned when no filter has the requested name.
+var ErrNotFound = errors.New("filter not found")
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Filter is a saved search query.
+type Filter struct {
+	Name  string
+	Query string
+}
+
+func filtersPath() (string, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configPath, filtersFile), nil
+}
+
+// load reads every saved filter, keyed by name. A missing file is empty.
+func load() (map[string]string, error) {
+	path, err := filtersPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved := map[string]string{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filtersFile, err)
+	}
+	return saved, nil
+}
+
+func store(saved map[string]string) error {
+	path, err := filtersPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return auth.WriteFileAtomic(path, append(data, '\n'), 0600)
+}
+
+// update runs fn on the saved filters while holding the file lock and
+// stores the result if fn succeeds.
+func update(fn func(saved map[string]string) error) error {
+	path, err := filtersPath()
+	if err != nil {
+		return err
+	}
+	return auth.WithFileLock(path, func() error {
+		saved, err := load()
+		if err != nil {
+			return err
+		}
+		if err := fn(saved); err != nil {
+			return err
+		}
+		return store(saved)
+	})
+}
+
+// Save validates query and stores it in canonical form under name,
+// replacing any filter with the same name.
+func Save(name, query string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid filter name %q: use letters, digits, - and _", name)
+	}
+	q, err := search.Parse(query)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	if q.Empty() {
+		return errors.New("query is empty")
+	}
+
+	return update(func(saved map[string]string) error {
+		saved[name] = q.String()
+		return nil
+	})
+}
+
+// Load returns the parsed query saved under name.
+func Load(name string) (*search.Query, error) {
+	saved, err := load()
+	if err != nil {
+		return nil, err
+	}
+	query, ok := saved[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return search.Parse(query)
+}
+
+// List returns every saved filter, sorted by name.
+func List() ([]Filter, error) {
+	saved, err := load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Filter, 0, len(saved))
+	for name, query := range saved {
+		result = append(result, Filter{Name: name, Query: query})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// Delete removes the filter saved under name.
+func Delete(name string) error {
+	return update(func(saved map[string]string) error {
+		if _, ok := saved[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		delete(saved, name)
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilters_SaveLoadListDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := Save("failed-stripe", `method:post   header.stripe-signature:* status:>=400`); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := Save("invoices", `header.x-event-type:invoice.*`); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, ".config/whk", filtersFile))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected file permissions 0600, got %o", perm)
+	}
+
+	q, err := Load("failed-stripe")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := q.String(); got != `method:post header.stripe-signature:* status:>=400` {
+		t.Errorf("stored query = %q", got)
+	}
+
+	list, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "failed-stripe" || list[1].Name != "invoices" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	if err := Delete("invoices"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Load("invoices"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := Delete("invoices"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting a missing filter, got %v", err)
+	}
+}
+
+func TestFilters_SaveRejectsInvalid(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := Save("bad name", "method:POST"); err == nil {
+		t.Error("expected error for invalid name")
+	}
+	if err := Save("ok", "nope:POST"); err == nil {
+		t.Error("expected error for invalid query")
+	}
+	if err := Save("ok", "  "); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestFilters_ListEmpty(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	list, err := List()
+	if err != nil || len(list) != 0 {
+		t.Errorf("List with no file = %v, %v", list, err)
+	}
+}
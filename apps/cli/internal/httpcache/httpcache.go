@@ -0,0 +1,130 @@
+// Package httpcache keeps API GET responses with their ETags, so repeat
+// requests from the CLI and TUI can be answered with 304 Not Modified and
+// served from disk instead of transferring the response again.
+//
+// Each response is a JSON file in httpcache/ in the whk config directory,
+// named by a hash of the request URL and the token that made it, so a
+// response is never served to another account.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"webhooks.cc/cli/internal/auth"
+)
+
+const (
+	cacheDir = "httpcache"
+	// MaxEntries is how many responses are kept; the least recently
+	// stored ones are removed first.
+	MaxEntries = 256
+)
+
+// Entry is a cached response.
+type Entry struct {
+	ETag string          `json:"etag"`
+	Body json.RawMessage `json:"body"`
+}
+
+// Key returns the cache key for a GET of url made with token.
+func Key(url, token string) string {
+	sum := sha256.Sum256([]byte(url + "\x00" + token))
+	return hex.EncodeToString(sum[:])
+}
+
+func dir() (string, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configPath, cacheDir), nil
+}
+
+// Get returns the cached response for key, or nil if there is none or it
+// can't be read.
+func Get(key string) *Entry {
+	d, err := dir()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(d, key+".json"))
+	if err != nil {
+		return nil
+	}
+	var e Entry
+	if json.Unmarshal(data, &e) != nil || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+// Put stores a JSON response body with its ETag under key.
+func Put(key, etag string, body []byte) error {
+	d, err := dir()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(Entry{ETag: etag, Body: body})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d, 0700); err != nil {
+		return err
+	}
+	if err := auth.WriteFileAtomic(filepath.Join(d, key+".json"), data, 0600); err != nil {
+		return err
+	}
+	return prune(d)
+}
+
+// Delete removes the response stored under key.
+func Delete(key string) error {
+	d, err := dir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(d, key+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Clear removes every cached response.
+func Clear() error {
+	d, err := dir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(d)
+}
+
+// prune removes the oldest responses beyond MaxEntries.
+func prune(d string) error {
+	entries, err := os.ReadDir(d)
+	if err != nil || len(entries) <= MaxEntries {
+		return err
+	}
+	type file struct {
+		name    string
+		modTime int64
+	}
+	files := make([]file, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{e.Name(), info.ModTime().UnixNano()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime < files[j].modTime })
+	for _, f := range files[:max(0, len(files)-MaxEntries)] {
+		_ = os.Remove(filepath.Join(d, f.name))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package httpcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPutGet(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	key := Key("https://webhooks.cc/api/endpoints", "token-a")
+	if Get(key) != nil {
+		t.Fatal("expected no entry before Put")
+	}
+	if err := Put(key, `"v1"`, []byte(`[{"slug":"a"}]`)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	e := Get(key)
+	if e == nil || e.ETag != `"v1"` || string(e.Body) != `[{"slug":"a"}]` {
+		t.Fatalf("Get = %+v", e)
+	}
+
+	// Another account's token gets its own entry
+	if Get(Key("https://webhooks.cc/api/endpoints", "token-b")) != nil {
+		t.Error("expected no entry for another token")
+	}
+
+	if err := Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if Get(key) != nil {
+		t.Error("expected no entry after Delete")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	d, err := dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fill the cache with entries older than the next one stored
+	old := time.Now().Add(-time.Hour)
+	for i := range MaxEntries {
+		key := Key(fmt.Sprintf("https://webhooks.cc/api/endpoints/%d", i), "t")
+		if err := Put(key, `"x"`, []byte(`{}`)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if err := os.Chtimes(filepath.Join(d, key+".json"), old, old.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first := Key("https://webhooks.cc/api/endpoints/0", "t")
+
+	newest := Key("https://webhooks.cc/api/endpoints/new", "t")
+	if err := Put(newest, `"y"`, []byte(`{}`)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	entries, err := os.ReadDir(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("expected %d entries after pruning, got %d", MaxEntries, len(entries))
+	}
+	if Get(first) != nil {
+		t.Error("expected the oldest entry to be pruned")
+	}
+	if Get(newest) == nil {
+		t.Error("expected the newest entry to be kept")
+	}
+}
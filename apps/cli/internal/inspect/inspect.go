@@ -0,0 +1,265 @@
+// Package inspect serves the local web inspector for whk tunnel --inspect:
+// a page listing forwarded requests next to the local server's responses,
+// updated live, with a button to replay a request.
+package inspect
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"webhooks.cc/cli/internal/tunnel"
+	"webhooks.cc/shared/types"
+)
+
+const (
+	// MaxEntries is how many forwarded requests the inspector keeps; older
+	// ones are dropped
+	MaxEntries = 200
+	// maxBodySize limits each response body kept for display
+	maxBodySize = 256 * 1024 // 256KB
+	// subscriberBuffer is how many entries a slow live view may fall behind
+	// before it misses updates
+	subscriberBuffer = 16
+)
+
+// ReplayHeader must be set on replay requests. Browsers can't send custom
+// headers cross-origin without a CORS preflight, which the inspector never
+// allows, so other sites can't trigger replays.
+const ReplayHeader = "X-Whk-Inspect"
+
+//go:embed index.html
+var indexHTML []byte
+
+// Entry is one forwarded request and what the local server answered.
+type Entry struct {
+	ID      int                    `json:"id"`
+	Request *types.CapturedRequest `json:"request"`
+	// Response is nil when the local server could not be reached.
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	// ReplayOf is the ID of the entry this one replayed, or 0.
+	ReplayOf    int   `json:"replayOf,omitempty"`
+	ForwardedAt int64 `json:"forwardedAt"`
+}
+
+// Response is the local server's response to a forwarded request.
+type Response struct {
+	Status     int                 `json:"status"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+	Base64     bool                `json:"base64,omitempty"`
+	Truncated  bool                `json:"truncated,omitempty"`
+	DurationMs int64               `json:"durationMs"`
+}
+
+// ReplayFunc forwards a request to the local server again.
+type ReplayFunc func(req *types.CapturedRequest) (*tunnel.ForwardResult, error)
+
+// Inspector records forwarded requests and serves them over HTTP.
+type Inspector struct {
+	replay ReplayFunc
+	mux    *http.ServeMux
+
+	mu          sync.Mutex
+	entries     []*Entry
+	nextID      int
+	subscribers map[chan *Entry]struct{}
+}
+
+// New creates an Inspector. replay is called when the user replays a
+// request from the page.
+func New(replay ReplayFunc) *Inspector {
+	i := &Inspector{
+		replay:      replay,
+		nextID:      1,
+		subscribers: map[chan *Entry]struct{}{},
+	}
+	i.mux = http.NewServeMux()
+	i.mux.HandleFunc("GET /{$}", i.handleIndex)
+	i.mux.HandleFunc("GET /api/entries", i.handleEntries)
+	i.mux.HandleFunc("GET /api/stream", i.handleStream)
+	i.mux.HandleFunc("POST /api/entries/{id}/replay", i.handleReplay)
+	return i
+}
+
+// Record adds a forwarded request and its result, as returned by
+// tunnel.Forward, and sends it to open pages. The tunnel must keep
+// responses for their headers and body to be shown.
+func (i *Inspector) Record(req *types.CapturedRequest, result *tunnel.ForwardResult, fwdErr error) *Entry {
+	return i.record(req, result, fwdErr, 0)
+}
+
+func (i *Inspector) record(req *types.CapturedRequest, result *tunnel.ForwardResult, fwdErr error, replayOf int) *Entry {
+	e := &Entry{Request: req, ReplayOf: replayOf, ForwardedAt: time.Now().UnixMilli()}
+	switch {
+	case fwdErr != nil:
+		e.Error = fwdErr.Error()
+	case !result.Success:
+		e.Error = result.Error
+	default:
+		e.Response = newResponse(result)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	e.ID = i.nextID
+	i.nextID++
+	i.entries = append(i.entries, e)
+	if len(i.entries) > MaxEntries {
+		i.entries = i.entries[len(i.entries)-MaxEntries:]
+	}
+	for ch := range i.subscribers {
+		select {
+		case ch <- e:
+		default:
+			// The page catches up on reload; never block forwarding
+		}
+	}
+	return e
+}
+
+// Entries returns the recorded entries, oldest first.
+func (i *Inspector) Entries() []*Entry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	entries := make([]*Entry, len(i.entries))
+	copy(entries, i.entries)
+	return entries
+}
+
+func (i *Inspector) entry(id int) *Entry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, e := range i.entries {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func newResponse(r *tunnel.ForwardResult) *Response {
+	resp := &Response{
+		Status:     r.StatusCode,
+		Headers:    r.Headers,
+		DurationMs: r.Duration.Milliseconds(),
+	}
+	body := r.Body
+	if len(body) > maxBodySize {
+		body = body[:maxBodySize]
+		resp.Truncated = true
+	}
+	if utf8.Valid(body) {
+		resp.Body = string(body)
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(body)
+		resp.Base64 = true
+	}
+	return resp
+}
+
+// ServeHTTP serves the inspector page and its API. Requests whose Host
+// isn't a loopback address are refused, so a DNS rebinding site can't read
+// captured payloads through the browser.
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLoopbackHost(r.Host) {
+		http.Error(w, "forbidden host", http.StatusForbidden)
+		return
+	}
+	i.mux.ServeHTTP(w, r)
+}
+
+func (i *Inspector) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	_, _ = w.Write(indexHTML)
+}
+
+func (i *Inspector) handleEntries(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, i.Entries())
+}
+
+func (i *Inspector) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan *Entry, subscriberBuffer)
+	i.mu.Lock()
+	i.subscribers[ch] = struct{}{}
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		delete(i.subscribers, ch)
+		i.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (i *Inspector) handleReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(ReplayHeader) == "" {
+		http.Error(w, "missing "+ReplayHeader+" header", http.StatusForbidden)
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid entry ID", http.StatusBadRequest)
+		return
+	}
+	orig := i.entry(id)
+	if orig == nil {
+		http.Error(w, "entry not found", http.StatusNotFound)
+		return
+	}
+	result, fwdErr := i.replay(orig.Request)
+	writeJSON(w, http.StatusOK, i.record(orig.Request, result, fwdErr, orig.ID))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// isLoopbackHost reports whether a Host header names this machine.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,206 @@
+package inspect
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"webhooks.cc/cli/internal/tunnel"
+	"webhooks.cc/shared/types"
+)
+
+func okResult(body string) *tunnel.ForwardResult {
+	return &tunnel.ForwardResult{
+		Success:    true,
+		StatusCode: 200,
+		Duration:   15 * time.Millisecond,
+		Headers:    http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte(body),
+	}
+}
+
+func TestRecord(t *testing.T) {
+	i := New(nil)
+	req := &types.CapturedRequest{Method: "POST", Path: "/hook"}
+
+	e := i.Record(req, okResult("ok"), nil)
+	if e.ID != 1 || e.Response == nil || e.Response.Status != 200 || e.Response.Body != "ok" || e.Response.DurationMs != 15 {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	e = i.Record(req, &tunnel.ForwardResult{Error: "connection refused"}, nil)
+	if e.ID != 2 || e.Response != nil || e.Error != "connection refused" {
+		t.Errorf("failed forwards should record the error: %+v", e)
+	}
+	e = i.Record(req, nil, errors.New("bad path"))
+	if e.Error != "bad path" {
+		t.Errorf("Error = %q, want bad path", e.Error)
+	}
+
+	if got := len(i.Entries()); got != 3 {
+		t.Errorf("expected 3 entries, got %d", got)
+	}
+}
+
+func TestRecord_KeepsNewest(t *testing.T) {
+	i := New(nil)
+	for range MaxEntries + 5 {
+		i.Record(&types.CapturedRequest{Method: "GET", Path: "/"}, okResult(""), nil)
+	}
+	entries := i.Entries()
+	if len(entries) != MaxEntries {
+		t.Fatalf("expected %d entries, got %d", MaxEntries, len(entries))
+	}
+	if entries[0].ID != 6 || entries[len(entries)-1].ID != MaxEntries+5 {
+		t.Errorf("expected the newest entries, got IDs %d..%d", entries[0].ID, entries[len(entries)-1].ID)
+	}
+}
+
+func TestRecord_BinaryAndLargeBodies(t *testing.T) {
+	i := New(nil)
+	e := i.Record(&types.CapturedRequest{}, okResult("\xff\xfe"), nil)
+	if !e.Response.Base64 || e.Response.Body != "//4=" {
+		t.Errorf("binary bodies should be base64: %+v", e.Response)
+	}
+	e = i.Record(&types.CapturedRequest{}, okResult(strings.Repeat("a", maxBodySize+1)), nil)
+	if !e.Response.Truncated || len(e.Response.Body) != maxBodySize {
+		t.Errorf("large bodies should be truncated, got %d bytes", len(e.Response.Body))
+	}
+}
+
+func TestServeHTTP_Entries(t *testing.T) {
+	i := New(nil)
+	i.Record(&types.CapturedRequest{Method: "POST", Path: "/hook"}, okResult("ok"), nil)
+	srv := httptest.NewServer(i)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/entries")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var entries []Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Request.Path != "/hook" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("GET /: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("expected the inspector page, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestServeHTTP_RejectsForeignHost(t *testing.T) {
+	i := New(nil)
+	req := httptest.NewRequest("GET", "/api/entries", nil)
+	req.Host = "attacker.example:4040"
+	rec := httptest.NewRecorder()
+	i.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a foreign Host, got %d", rec.Code)
+	}
+
+	for _, host := range []string{"localhost:4040", "127.0.0.1:4040", "[::1]:4040"} {
+		req := httptest.NewRequest("GET", "/api/entries", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		i.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Host %s: expected 200, got %d", host, rec.Code)
+		}
+	}
+}
+
+func TestServeHTTP_Replay(t *testing.T) {
+	var replayed *types.CapturedRequest
+	i := New(func(req *types.CapturedRequest) (*tunnel.ForwardResult, error) {
+		replayed = req
+		return okResult("again"), nil
+	})
+	orig := i.Record(&types.CapturedRequest{Method: "POST", Path: "/hook"}, okResult("ok"), nil)
+	srv := httptest.NewServer(i)
+	defer srv.Close()
+
+	// Without the header, as a cross-site form post would send it
+	resp, err := http.Post(srv.URL+"/api/entries/1/replay", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden || replayed != nil {
+		t.Fatalf("replay without %s should be refused, got %d", ReplayHeader, resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("POST", srv.URL+"/api/entries/1/replay", nil)
+	req.Header.Set(ReplayHeader, "1")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var e Entry
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if replayed != orig.Request {
+		t.Error("replay should forward the original request")
+	}
+	if e.ID != 2 || e.ReplayOf != 1 || e.Response.Body != "again" {
+		t.Errorf("unexpected replay entry: %+v", e)
+	}
+
+	req, _ = http.NewRequest("POST", srv.URL+"/api/entries/99/replay", nil)
+	req.Header.Set(ReplayHeader, "1")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown entry, got %d", resp.StatusCode)
+	}
+}
+
+func TestServeHTTP_Stream(t *testing.T) {
+	i := New(nil)
+	srv := httptest.NewServer(i)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/stream")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The handler subscribes before writing the response headers
+	i.Record(&types.CapturedRequest{Method: "POST", Path: "/live"}, okResult("ok"), nil)
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if e.Request.Path != "/live" {
+			t.Errorf("unexpected streamed entry: %+v", e)
+		}
+		return
+	}
+	t.Fatal("stream ended without an entry")
+}
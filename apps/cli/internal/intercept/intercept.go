@@ -0,0 +1,91 @@
+// Package intercept lets the user edit a captured request's body in their
+// editor before `whk tunnel --intercept` (or the TUI tunnel with intercept
+// on) forwards it to the local server.
+package intercept
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"webhooks.cc/shared/types"
+)
+
+// defaultEditor is used when neither VISUAL nor EDITOR is set
+const defaultEditor = "vi"
+
+// Edit is a request body being edited in a temporary file.
+type Edit struct {
+	req  *types.CapturedRequest
+	path string
+}
+
+// Start writes req's body to a temporary file for editing. Binary bodies
+// can't be edited.
+func Start(req *types.CapturedRequest) (*Edit, error) {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(body) {
+		return nil, errors.New("body is binary and can't be edited")
+	}
+
+	// The extension lets editors pick syntax highlighting
+	ext := ".txt"
+	switch ct := strings.ToLower(req.ContentType); {
+	case strings.Contains(ct, "json"):
+		ext = ".json"
+	case strings.Contains(ct, "xml"):
+		ext = ".xml"
+	}
+	f, err := os.CreateTemp("", "whk-body-*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(body); err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return &Edit{req: req, path: f.Name()}, nil
+}
+
+// Cmd returns the command that opens the body in the user's editor: VISUAL
+// or EDITOR, which may include arguments (e.g. "code --wait"), or vi. The
+// caller connects it to the terminal and runs it.
+func (e *Edit) Cmd() *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{defaultEditor}
+	}
+	return exec.Command(args[0], append(args[1:], e.path)...)
+}
+
+// Finish reads the edited body back, removes the temporary file, and
+// returns a copy of the request with the new body. The original request is
+// not modified.
+func (e *Edit) Finish() (*types.CapturedRequest, error) {
+	defer func() { _ = os.Remove(e.path) }()
+	body, err := os.ReadFile(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("read edited body: %w", err)
+	}
+	edited := *e.req
+	edited.Body = string(body)
+	edited.BodyEncoding = ""
+	edited.Size = len(body)
+	return &edited, nil
+}
+
+// Cancel removes the temporary file without reading it.
+func (e *Edit) Cancel() {
+	_ = os.Remove(e.path)
+}
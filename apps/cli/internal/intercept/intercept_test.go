@@ -0,0 +1,64 @@
+package intercept
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestEdit(t *testing.T) {
+	// A stand-in editor that rewrites the file it is given
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\nprintf '{\"edited\":true}' > \"$1\"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	req := &types.CapturedRequest{ID: "r1", Body: `{"edited":false}`, ContentType: "application/json", Size: 16}
+	e, err := Start(req)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !strings.HasSuffix(e.path, ".json") {
+		t.Errorf("expected a .json file for a JSON body, got %s", e.path)
+	}
+	if err := e.Cmd().Run(); err != nil {
+		t.Fatalf("editor: %v", err)
+	}
+	edited, err := e.Finish()
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if edited.Body != `{"edited":true}` || edited.Size != 15 || edited.ID != "r1" {
+		t.Errorf("unexpected edited request: %+v", edited)
+	}
+	if req.Body != `{"edited":false}` {
+		t.Error("the original request must not be modified")
+	}
+	if _, err := os.Stat(e.path); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be removed, got %v", err)
+	}
+}
+
+func TestEditCmd_EditorWithArgs(t *testing.T) {
+	t.Setenv("VISUAL", "code --wait")
+	cmd := (&Edit{path: "/tmp/body.json"}).Cmd()
+	if got := strings.Join(cmd.Args, " "); got != "code --wait /tmp/body.json" {
+		t.Errorf("Args = %q", got)
+	}
+}
+
+func TestStart_Uneditable(t *testing.T) {
+	for name, req := range map[string]*types.CapturedRequest{
+		"binary": {Body: "/w==", BodyEncoding: types.BodyEncodingBase64},
+	} {
+		if _, err := Start(req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+// Package manifest reads the endpoint manifests used by `whk apply`, which
+// keep an account's endpoint configuration in version control, and plans
+// the changes that bring the account in line with one.
+//
+// A manifest is YAML (or JSON) with a list of endpoints:
+//
+//	endpoints:
+//	  - slug: stripe-staging
+//	    name: Stripe (staging)
+//	    ttl: 30d
+//	    mock:
+//	      status: 200
+//	      body: '{"received":true}'
+//	      headers:
+//	        Content-Type: application/json
+//	    routes:
+//	      - prefix: /connect
+//	        forwardUrl: https://staging.example.com/hooks/connect
+//
+// Settings use the same names as the API. An endpoint's name defaults to
+// its slug, and a missing mock or routes list means the endpoint has none,
+// so removing a setting from the manifest removes it from the endpoint.
+// ttl only applies when an endpoint is created.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
+)
+
+// Manifest is a parsed manifest file.
+type Manifest struct {
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Endpoint is the desired configuration of one endpoint.
+type Endpoint struct {
+	Slug   string                `json:"slug"`
+	Name   string                `json:"name,omitempty"`
+	TTL    string                `json:"ttl,omitempty"`
+	Mock   *types.MockResponse   `json:"mock,omitempty"`
+	Routes []types.EndpointRoute `json:"routes,omitempty"`
+}
+
+// DisplayName is the name the endpoint should have: Name, or the slug if
+// it is unset.
+func (e *Endpoint) DisplayName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.Slug
+}
+
+// TTLDuration returns the parsed ttl, or 0 if it is unset.
+func (e *Endpoint) TTLDuration() time.Duration {
+	d, _ := parseTTL(e.TTL)
+	return d
+}
+
+// Parse decodes and validates a manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("invalid manifest: empty file")
+	}
+	// Decode through JSON so settings use the API's field names, and
+	// misspelled ones are reported instead of silently ignored.
+	js, err := json.Marshal(normalize(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.DisallowUnknownFields()
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// normalize converts the map[any]any values YAML produces for non-string
+// keys into map[string]any, so they can be encoded as JSON.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = normalize(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	}
+	return v
+}
+
+func (m *Manifest) validate() error {
+	seen := map[string]bool{}
+	for i := range m.Endpoints {
+		e := &m.Endpoints[i]
+		if e.Slug == "" {
+			return fmt.Errorf("endpoint %d: slug is required", i+1)
+		}
+		slug, _ := validation.NormalizeSlug(e.Slug)
+		if validation.ReservedSlugs[slug] || !validation.IsValidCustomSlug(slug) {
+			return fmt.Errorf("endpoint %d: invalid slug %q", i+1, e.Slug)
+		}
+		e.Slug = slug
+		if seen[slug] {
+			return fmt.Errorf("endpoint %s is listed more than once", slug)
+		}
+		seen[slug] = true
+
+		if _, err := parseTTL(e.TTL); err != nil {
+			return fmt.Errorf("endpoint %s: %w", slug, err)
+		}
+		if err := validateMock(e.Mock); err != nil {
+			return fmt.Errorf("endpoint %s: mock: %w", slug, err)
+		}
+		if len(e.Routes) > validation.MaxRoutes {
+			return fmt.Errorf("endpoint %s: too many routes (max %d)", slug, validation.MaxRoutes)
+		}
+		prefixes := map[string]bool{}
+		for _, r := range e.Routes {
+			if !validation.IsValidRoutePrefix(r.Prefix) {
+				return fmt.Errorf("endpoint %s: invalid route prefix %q", slug, r.Prefix)
+			}
+			if prefixes[r.Prefix] {
+				return fmt.Errorf("endpoint %s: route %s is listed more than once", slug, r.Prefix)
+			}
+			prefixes[r.Prefix] = true
+			if err := validateMock(r.MockResponse); err != nil {
+				return fmt.Errorf("endpoint %s: route %s: mockResponse: %w", slug, r.Prefix, err)
+			}
+			if r.ForwardURL != "" {
+				u, err := url.Parse(r.ForwardURL)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("endpoint %s: route %s: invalid forwardUrl %q (must be http or https)", slug, r.Prefix, r.ForwardURL)
+				}
+			}
+			if len(r.Tags) > validation.MaxTags {
+				return fmt.Errorf("endpoint %s: route %s: too many tags (max %d)", slug, r.Prefix, validation.MaxTags)
+			}
+			for _, tag := range r.Tags {
+				if norm, ok := validation.NormalizeTag(tag); !ok || norm != tag {
+					return fmt.Errorf("endpoint %s: route %s: invalid tag %q (lowercase letters, digits and . _ : / -)", slug, r.Prefix, tag)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateMock(mock *types.MockResponse) error {
+	if mock == nil {
+		return nil
+	}
+	if !validation.IsValidStatus(mock.Status) {
+		return fmt.Errorf("invalid status: %d", mock.Status)
+	}
+	if !validation.IsValidDelay(mock.Delay) {
+		return fmt.Errorf("invalid delay: %d (must be 0-%d ms)", mock.Delay, validation.MaxMockDelay.Milliseconds())
+	}
+	for k, v := range mock.Headers {
+		if !validation.IsSafeResponseHeader(k, v) {
+			return fmt.Errorf("header %s cannot be sent in a mock response", k)
+		}
+	}
+	return nil
+}
+
+// parseTTL parses a ttl such as 2h or 7d. An empty ttl is 0.
+func parseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid ttl: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid ttl: %s", s)
+	}
+	return d, nil
+}
+
+// Actions a Change can take
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is one step of a plan.
+type Change struct {
+	Action string
+	Slug   string
+	// Endpoint is the desired configuration, or nil for ActionDelete.
+	Endpoint *Endpoint
+	// Fields lists the settings an ActionCreate sets (with an empty From)
+	// or an ActionUpdate changes.
+	Fields []FieldChange
+}
+
+// FieldChange describes how one setting changes.
+type FieldChange struct {
+	Field    string
+	From, To string
+}
+
+// Changes reports whether the change updates field.
+func (c *Change) Changes(field string) bool {
+	for _, f := range c.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings compared by Plan
+const (
+	FieldName   = "name"
+	FieldMock   = "mock"
+	FieldRoutes = "routes"
+	FieldTTL    = "ttl"
+)
+
+// Plan returns the changes that make current match the manifest: endpoints
+// to create, in manifest order, then endpoints to update. With prune, owned
+// endpoints missing from the manifest are deleted last; endpoints shared
+// from a team are never deleted. Endpoints that already match are left out.
+func Plan(m *Manifest, current []api.Endpoint, prune bool) []Change {
+	bySlug := make(map[string]*api.Endpoint, len(current))
+	for i := range current {
+		bySlug[current[i].Slug] = &current[i]
+	}
+
+	var creates, updates, deletes []Change
+	wanted := map[string]bool{}
+	for i := range m.Endpoints {
+		e := &m.Endpoints[i]
+		wanted[e.Slug] = true
+		cur, ok := bySlug[e.Slug]
+		if !ok {
+			creates = append(creates, Change{Action: ActionCreate, Slug: e.Slug, Endpoint: e, Fields: created(e)})
+			continue
+		}
+		if fields := diff(e, cur); len(fields) > 0 {
+			updates = append(updates, Change{Action: ActionUpdate, Slug: e.Slug, Endpoint: e, Fields: fields})
+		}
+	}
+	if prune {
+		for _, cur := range current {
+			if !wanted[cur.Slug] && cur.FromTeam == nil {
+				deletes = append(deletes, Change{Action: ActionDelete, Slug: cur.Slug})
+			}
+		}
+		sort.Slice(deletes, func(i, j int) bool { return deletes[i].Slug < deletes[j].Slug })
+	}
+	return append(append(creates, updates...), deletes...)
+}
+
+func created(e *Endpoint) []FieldChange {
+	fields := []FieldChange{{Field: FieldName, To: strconv.Quote(e.DisplayName())}}
+	if e.Mock != nil {
+		fields = append(fields, FieldChange{Field: FieldMock, To: describeMock(e.Mock)})
+	}
+	if len(e.Routes) > 0 {
+		fields = append(fields, FieldChange{Field: FieldRoutes, To: describeRoutes(e.Routes)})
+	}
+	if e.TTL != "" {
+		fields = append(fields, FieldChange{Field: FieldTTL, To: e.TTL})
+	}
+	return fields
+}
+
+func diff(e *Endpoint, cur *api.Endpoint) []FieldChange {
+	var fields []FieldChange
+	if name := e.DisplayName(); name != cur.Name {
+		fields = append(fields, FieldChange{FieldName, strconv.Quote(cur.Name), strconv.Quote(name)})
+	}
+	if !sameSettings(e.Mock, cur.MockResponse) {
+		fields = append(fields, FieldChange{FieldMock, describeMock(cur.MockResponse), describeMock(e.Mock)})
+	}
+	if !sameSettings(sortedRoutes(e.Routes), sortedRoutes(cur.Routes)) {
+		fields = append(fields, FieldChange{FieldRoutes, describeRoutes(cur.Routes), describeRoutes(e.Routes)})
+	}
+	return fields
+}
+
+// sortedRoutes orders routes by prefix: the receiver picks the longest
+// matching prefix, so their order doesn't matter.
+func sortedRoutes(routes []types.EndpointRoute) []types.EndpointRoute {
+	sorted := append([]types.EndpointRoute(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Prefix < sorted[j].Prefix })
+	return sorted
+}
+
+// sameSettings compares two settings by their JSON encoding, treating null,
+// empty and omitted values alike.
+func sameSettings(a, b any) bool {
+	return reflect.DeepEqual(canonical(a), canonical(b))
+}
+
+func canonical(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if json.Unmarshal(data, &out) != nil {
+		return nil
+	}
+	return dropEmpty(out)
+}
+
+// dropEmpty removes nulls, empty strings and empty collections.
+func dropEmpty(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if val = dropEmpty(val); val == nil {
+				delete(v, k)
+			} else {
+				v[k] = val
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = dropEmpty(val)
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return v
+}
+
+func describeMock(mock *types.MockResponse) string {
+	if mock == nil {
+		return "default"
+	}
+	if mock.Body == "" {
+		return fmt.Sprintf("responds %d", mock.Status)
+	}
+	return fmt.Sprintf("responds %d with %d-byte body", mock.Status, len(mock.Body))
+}
+
+func describeRoutes(routes []types.EndpointRoute) string {
+	if len(routes) == 0 {
+		return "none"
+	}
+	prefixes := make([]string, len(routes))
+	for i, r := range sortedRoutes(routes) {
+		prefixes[i] = r.Prefix
+	}
+	return strings.Join(prefixes, ", ")
+}
@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/shared/types"
+)
+
+const testManifest = `
+endpoints:
+  - slug: stripe-staging
+    name: Stripe (staging)
+    ttl: 30d
+    mock:
+      status: 200
+      body: '{"received":true}'
+      headers:
+        Content-Type: application/json
+  - slug: github-dev
+    routes:
+      - prefix: /push
+        forwardUrl: https://staging.example.com/hooks/push
+      - prefix: /ping
+        mockResponse: {status: 204}
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(m.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(m.Endpoints))
+	}
+	stripe, github := m.Endpoints[0], m.Endpoints[1]
+	if stripe.DisplayName() != "Stripe (staging)" || stripe.TTLDuration() != 30*24*time.Hour {
+		t.Errorf("unexpected stripe endpoint: %+v", stripe)
+	}
+	if stripe.Mock == nil || stripe.Mock.Status != 200 || stripe.Mock.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected mock: %+v", stripe.Mock)
+	}
+	if github.DisplayName() != "github-dev" || github.TTLDuration() != 0 {
+		t.Errorf("name should default to the slug and ttl to none: %+v", github)
+	}
+	if len(github.Routes) != 2 || github.Routes[0].ForwardURL == "" || github.Routes[1].MockResponse.Status != 204 {
+		t.Errorf("unexpected routes: %+v", github.Routes)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name, manifest, wantErr string
+	}{
+		{"empty", "", "empty file"},
+		{"unknown setting", "endpoints:\n  - slug: ep-a\n    mok: {status: 200}\n", `unknown field "mok"`},
+		{"missing slug", "endpoints:\n  - name: x\n", "slug is required"},
+		{"invalid slug", "endpoints:\n  - slug: 'no spaces'\n", "invalid slug"},
+		{"duplicate slug", "endpoints:\n  - slug: ep-a\n  - slug: EP-A\n", "listed more than once"},
+		{"ttl", "endpoints:\n  - slug: ep-a\n    ttl: soon\n", "invalid ttl"},
+		{"status", "endpoints:\n  - slug: ep-a\n    mock: {status: 99}\n", "invalid status"},
+		{"delay", "endpoints:\n  - slug: ep-a\n    mock: {status: 200, delay: 60000}\n", "invalid delay"},
+		{"prefix", "endpoints:\n  - slug: ep-a\n    routes: [{prefix: nope}]\n", "invalid route prefix"},
+		{"forward", "endpoints:\n  - slug: ep-a\n    routes: [{prefix: /a, forwardUrl: 'ftp://x'}]\n", "invalid forwardUrl"},
+		{"tag", "endpoints:\n  - slug: ep-a\n    routes: [{prefix: /a, tags: [Stripe]}]\n", "invalid tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	current := []api.Endpoint{
+		{
+			Slug: "github-dev",
+			Name: "github-dev",
+			// Same routes in a different order, with explicit empty values
+			Routes: []types.EndpointRoute{
+				{Prefix: "/ping", MockResponse: &types.MockResponse{Status: 204, Headers: map[string]string{}}},
+				{Prefix: "/push", ForwardURL: "https://staging.example.com/hooks/push"},
+			},
+		},
+		{Slug: "old-one", Name: "old-one"},
+		{Slug: "teammate", Name: "teammate", FromTeam: &api.TeamShare{}},
+	}
+
+	changes := Plan(m, current, false)
+	if len(changes) != 1 || changes[0].Action != ActionCreate || changes[0].Slug != "stripe-staging" {
+		t.Fatalf("expected only stripe-staging to be created, got %+v", changes)
+	}
+
+	changes = Plan(m, current, true)
+	if len(changes) != 2 || changes[1].Action != ActionDelete || changes[1].Slug != "old-one" {
+		t.Fatalf("expected old-one to be pruned (and not the team endpoint), got %+v", changes)
+	}
+
+	current[0].Name = "GitHub"
+	current[0].MockResponse = &types.MockResponse{Status: 200}
+	current[0].Routes = current[0].Routes[:1]
+	changes = Plan(m, current, false)
+	if len(changes) != 2 || changes[1].Action != ActionUpdate {
+		t.Fatalf("expected github-dev to be updated, got %+v", changes)
+	}
+	update := changes[1]
+	for _, field := range []string{FieldName, FieldMock, FieldRoutes} {
+		if !update.Changes(field) {
+			t.Errorf("expected %s to change: %+v", field, update.Fields)
+		}
+	}
+	if f := update.Fields[0]; f.From != `"GitHub"` || f.To != `"github-dev"` {
+		t.Errorf("unexpected name change: %+v", f)
+	}
+	if f := update.Fields[1]; f.From != "responds 200" || f.To != "default" {
+		t.Errorf("removing the mock should restore the default: %+v", f)
+	}
+}
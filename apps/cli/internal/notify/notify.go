@@ -0,0 +1,102 @@
+// Package notify raises desktop notifications with the platform's own
+// tool: osascript on macOS, notify-send on Linux and other Unix systems,
+// and a PowerShell toast on Windows.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"webhooks.cc/shared/types"
+)
+
+// maxPathLen is how much of a request path a notification shows
+const maxPathLen = 80
+
+// windowsAppID is the app ID Windows shows PowerShell toasts under. An
+// unregistered ID would be dropped silently.
+const windowsAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// windowsScript shows a toast with the title and message passed in the
+// environment, so neither is ever parsed as PowerShell.
+const windowsScript = `$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:WHK_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:WHK_NOTIFY_MESSAGE)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:WHK_NOTIFY_APP).Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// Command returns the command that shows a notification on goos (a
+// runtime.GOOS value).
+func Command(goos, title, message string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript)
+		cmd.Env = append(os.Environ(),
+			"WHK_NOTIFY_TITLE="+title,
+			"WHK_NOTIFY_MESSAGE="+message,
+			"WHK_NOTIFY_APP="+windowsAppID,
+		)
+		return cmd
+	default:
+		return exec.Command("notify-send", "--app-name=whk", "--", title, message)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// Available returns an error if this platform's notification tool isn't
+// installed.
+func Available() error {
+	cmd := Command(runtime.GOOS, "", "")
+	if _, err := exec.LookPath(cmd.Args[0]); err != nil {
+		return fmt.Errorf("desktop notifications need %s, which was not found", cmd.Args[0])
+	}
+	return nil
+}
+
+// Send shows a notification.
+func Send(title, message string) error {
+	out, err := Command(runtime.GOOS, title, message).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Message summarizes requests for a notification: the method and path of
+// a single request, or how many arrived and the latest one's.
+func Message(reqs []*types.CapturedRequest) string {
+	if len(reqs) == 0 {
+		return ""
+	}
+	last := reqs[len(reqs)-1]
+	line := last.Method + " " + truncate(last.Path, maxPathLen)
+	if len(reqs) == 1 {
+		return line
+	}
+	return fmt.Sprintf("%d requests, latest %s", len(reqs), line)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
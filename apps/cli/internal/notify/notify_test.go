@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestCommand(t *testing.T) {
+	title, message := `whk: my-ep`, `POST /say "hi" \o/`
+
+	cmd := Command("darwin", title, message)
+	want := []string{"osascript", "-e", `display notification "POST /say \"hi\" \\o/" with title "whk: my-ep"`}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("darwin args = %q, want %q", cmd.Args, want)
+	}
+
+	cmd = Command("linux", title, "-dash first")
+	want = []string{"notify-send", "--app-name=whk", "--", title, "-dash first"}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("linux args = %q, want %q", cmd.Args, want)
+	}
+
+	cmd = Command("windows", title, message)
+	if cmd.Args[0] != "powershell" || strings.Contains(strings.Join(cmd.Args, " "), message) {
+		t.Errorf("windows args should not include the message: %q", cmd.Args)
+	}
+	if !slices.Contains(cmd.Env, "WHK_NOTIFY_MESSAGE="+message) || !slices.Contains(cmd.Env, "WHK_NOTIFY_TITLE="+title) {
+		t.Error("windows command should pass the title and message in the environment")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	a := &types.CapturedRequest{Method: "POST", Path: "/stripe/webhook"}
+	b := &types.CapturedRequest{Method: "GET", Path: "/" + strings.Repeat("x", 100)}
+
+	if got := Message([]*types.CapturedRequest{a}); got != "POST /stripe/webhook" {
+		t.Errorf("single = %q", got)
+	}
+	got := Message([]*types.CapturedRequest{a, b})
+	if !strings.HasPrefix(got, "2 requests, latest GET /xxx") || !strings.HasSuffix(got, "…") {
+		t.Errorf("batch = %q", got)
+	}
+	if n := len([]rune(strings.TrimPrefix(got, "2 requests, latest GET "))); n != maxPathLen {
+		t.Errorf("path shown with %d characters, want %d", n, maxPathLen)
+	}
+	if Message(nil) != "" {
+		t.Error("no requests should give an empty message")
+	}
+}
@@ -0,0 +1,405 @@
+// Package openapi derives endpoint mock responses from OpenAPI documents,
+// so an endpoint can answer like the API it stands in for. It reads
+// OpenAPI 3.x and Swagger 2.0 documents in YAML or JSON and only follows
+// local $refs (#/components/..., #/definitions/...).
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"webhooks.cc/shared/types"
+)
+
+// maxDepth bounds $ref resolution and schema walking, so recursive schemas
+// terminate.
+const maxDepth = 16
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// ErrOperationNotFound is returned when the document has no operation with
+// the requested operationId.
+var ErrOperationNotFound = errors.New("operation not found")
+
+// Operation identifies an operation in a document.
+type Operation struct {
+	ID     string
+	Method string
+	Path   string
+}
+
+// Document is a parsed OpenAPI document.
+type Document struct {
+	root map[string]any
+}
+
+// Parse parses an OpenAPI document in YAML or JSON.
+func Parse(data []byte) (*Document, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	root, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: not an object")
+	}
+	if _, ok := root["paths"].(map[string]any); !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: no paths")
+	}
+	return &Document{root: root}, nil
+}
+
+// Operations lists the document's operations sorted by path and method.
+// Operations without an operationId have an empty ID.
+func (d *Document) Operations() []Operation {
+	paths, _ := d.root["paths"].(map[string]any)
+	var ops []Operation
+	for _, path := range sortedKeys(paths) {
+		item, _ := d.resolve(paths[path], 0).(map[string]any)
+		for _, method := range methods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			ops = append(ops, Operation{ID: id, Method: strings.ToUpper(method), Path: path})
+		}
+	}
+	return ops
+}
+
+// MockResponse builds a mock response from a response of the operation
+// with the given operationId. An empty operationID selects the document's
+// only operation. An empty status selects the operation's first 2xx
+// response, falling back to "default".
+func (d *Document) MockResponse(operationID, status string) (*types.MockResponse, error) {
+	op, err := d.operation(operationID)
+	if err != nil {
+		return nil, err
+	}
+	responses, _ := op["responses"].(map[string]any)
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("the operation has no responses")
+	}
+
+	if status == "" {
+		status = pickStatus(responses)
+	}
+	resp, ok := d.resolve(responses[status], 0).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("operation has no %s response (has %s)", status, strings.Join(sortedKeys(responses), ", "))
+	}
+
+	code, err := statusCode(status)
+	if err != nil {
+		return nil, err
+	}
+
+	mock := &types.MockResponse{Status: code, Headers: make(map[string]string)}
+	if headers, ok := resp["headers"].(map[string]any); ok {
+		for _, name := range sortedKeys(headers) {
+			h, _ := d.resolve(headers[name], 0).(map[string]any)
+			if v, ok := d.example(h, 0); ok {
+				mock.Headers[name] = scalarString(v)
+			}
+		}
+	}
+
+	contentType, body, ok := d.body(resp)
+	if ok {
+		mock.Headers["Content-Type"] = contentType
+		if s, isString := body.(string); isString {
+			mock.Body = s
+		} else {
+			data, err := json.MarshalIndent(body, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			mock.Body = string(data)
+		}
+	}
+	return mock, nil
+}
+
+// operation finds an operation object by operationId.
+func (d *Document) operation(operationID string) (map[string]any, error) {
+	ops := d.Operations()
+	if operationID == "" {
+		if len(ops) != 1 {
+			return nil, fmt.Errorf("the document has %d operations, choose one with --operation", len(ops))
+		}
+	}
+	for _, o := range ops {
+		if operationID == "" || o.ID == operationID {
+			paths := d.root["paths"].(map[string]any)
+			item, _ := d.resolve(paths[o.Path], 0).(map[string]any)
+			return item[strings.ToLower(o.Method)].(map[string]any), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, operationID)
+}
+
+// body picks a media type and an example body for a response. JSON media
+// types are preferred.
+func (d *Document) body(resp map[string]any) (string, any, bool) {
+	// OpenAPI 3: content.<media type>.{example,examples,schema}
+	if content, ok := resp["content"].(map[string]any); ok && len(content) > 0 {
+		mediaTypes := sortedKeys(content)
+		sort.SliceStable(mediaTypes, func(i, j int) bool {
+			return isJSON(mediaTypes[i]) && !isJSON(mediaTypes[j])
+		})
+		for _, mt := range mediaTypes {
+			media, _ := d.resolve(content[mt], 0).(map[string]any)
+			if v, ok := d.example(media, 0); ok {
+				return mt, v, true
+			}
+		}
+		return "", nil, false
+	}
+
+	// Swagger 2: examples.<media type> and schema
+	if examples, ok := resp["examples"].(map[string]any); ok && len(examples) > 0 {
+		mediaTypes := sortedKeys(examples)
+		return mediaTypes[0], examples[mediaTypes[0]], true
+	}
+	if schema, ok := resp["schema"]; ok {
+		if v, ok := d.sample(schema, 0); ok {
+			return "application/json", v, true
+		}
+	}
+	return "", nil, false
+}
+
+// example returns an example value for a media type, parameter or header
+// object: its example, the first of its examples, or a sample built from
+// its schema.
+func (d *Document) example(obj map[string]any, depth int) (any, bool) {
+	if obj == nil {
+		return nil, false
+	}
+	if v, ok := obj["example"]; ok {
+		return v, true
+	}
+	if examples, ok := obj["examples"].(map[string]any); ok {
+		for _, name := range sortedKeys(examples) {
+			ex, _ := d.resolve(examples[name], depth).(map[string]any)
+			if v, ok := ex["value"]; ok {
+				return v, true
+			}
+		}
+	}
+	if schema, ok := obj["schema"]; ok {
+		return d.sample(schema, depth)
+	}
+	return nil, false
+}
+
+// sample builds an example value from a schema, using its example, default
+// or first enum value where given and placeholder values otherwise.
+func (d *Document) sample(s any, depth int) (any, bool) {
+	if depth > maxDepth {
+		return nil, false
+	}
+	schema, ok := d.resolve(s, depth).(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	for _, key := range []string{"example", "default", "const"} {
+		if v, ok := schema[key]; ok {
+			return v, true
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0], true
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0], true
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		subs, ok := schema[key].([]any)
+		if !ok || len(subs) == 0 {
+			continue
+		}
+		if key != "allOf" {
+			return d.sample(subs[0], depth+1)
+		}
+		merged := make(map[string]any)
+		for _, sub := range subs {
+			if v, ok := d.sample(sub, depth+1); ok {
+				if m, ok := v.(map[string]any); ok {
+					for k, val := range m {
+						merged[k] = val
+					}
+				}
+			}
+		}
+		return merged, true
+	}
+
+	typ, _ := schema["type"].(string)
+	if list, ok := schema["type"].([]any); ok && len(list) > 0 {
+		typ, _ = list[0].(string)
+	}
+	if typ == "" {
+		if _, ok := schema["properties"]; ok {
+			typ = "object"
+		} else if _, ok := schema["items"]; ok {
+			typ = "array"
+		}
+	}
+
+	switch typ {
+	case "object":
+		obj := make(map[string]any)
+		props, _ := schema["properties"].(map[string]any)
+		for _, name := range sortedKeys(props) {
+			if v, ok := d.sample(props[name], depth+1); ok {
+				obj[name] = v
+			}
+		}
+		return obj, true
+	case "array":
+		if v, ok := d.sample(schema["items"], depth+1); ok {
+			return []any{v}, true
+		}
+		return []any{}, true
+	case "string":
+		return sampleString(schema), true
+	case "integer":
+		return 0, true
+	case "number":
+		return 0.0, true
+	case "boolean":
+		return false, true
+	}
+	return nil, false
+}
+
+// resolve follows a local $ref until it reaches a non-reference value.
+func (d *Document) resolve(v any, depth int) any {
+	for ; depth <= maxDepth; depth++ {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return v
+		}
+		v = d.lookup(ref)
+	}
+	return nil
+}
+
+// lookup resolves a local JSON pointer such as #/components/schemas/Order.
+func (d *Document) lookup(ref string) any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+	var cur any = d.root
+	for _, part := range strings.Split(pointer, "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// pickStatus chooses the response to mock when none was requested.
+func pickStatus(responses map[string]any) string {
+	for _, status := range sortedKeys(responses) {
+		if strings.HasPrefix(status, "2") {
+			return status
+		}
+	}
+	if _, ok := responses["default"]; ok {
+		return "default"
+	}
+	return sortedKeys(responses)[0]
+}
+
+// statusCode converts a responses key to a status code: "default" is 200
+// and a range such as 4XX is its first code.
+func statusCode(status string) (int, error) {
+	if status == "default" {
+		return 200, nil
+	}
+	if len(status) == 3 && strings.EqualFold(status[1:], "XX") {
+		status = status[:1] + "00"
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid response status: %s", status)
+	}
+	return code, nil
+}
+
+func sampleString(schema map[string]any) string {
+	switch schema["format"] {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	return "string"
+}
+
+func scalarString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// normalize converts the map[any]any values YAML produces for mappings
+// with non-string keys (such as 200: under responses) to map[string]any.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = normalize(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	}
+	return v
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
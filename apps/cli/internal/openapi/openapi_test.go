@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const ordersSpec = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: "1"
+paths:
+  /webhooks/orders:
+    post:
+      operationId: postWebhook
+      responses:
+        200:
+          description: accepted
+          headers:
+            X-Request-Id:
+              schema:
+                type: string
+                example: req_123
+          content:
+            text/plain:
+              example: ok
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Ack'
+        4XX:
+          description: rejected
+          content:
+            application/json:
+              examples:
+                invalid:
+                  value: {error: invalid signature}
+  /orders/{id}:
+    get:
+      operationId: getOrder
+      responses:
+        default:
+          description: order
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id: {type: string, format: uuid}
+                  total: {type: integer}
+                  status: {type: string, enum: [paid, refunded]}
+                  items:
+                    type: array
+                    items: {$ref: '#/components/schemas/Node'}
+components:
+  schemas:
+    Ack:
+      allOf:
+        - type: object
+          properties:
+            received: {type: boolean, default: true}
+        - type: object
+          properties:
+            id: {type: string, example: evt_1}
+    Node:
+      type: object
+      properties:
+        name: {type: string}
+        child: {$ref: '#/components/schemas/Node'}
+`
+
+func TestMockResponse_ExampleFromSchema(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	mock, err := doc.MockResponse("postWebhook", "")
+	if err != nil {
+		t.Fatalf("MockResponse: %v", err)
+	}
+	if mock.Status != 200 {
+		t.Errorf("expected status 200, got %d", mock.Status)
+	}
+	if mock.Headers["Content-Type"] != "application/json" {
+		t.Errorf("expected JSON to be preferred, got %q", mock.Headers["Content-Type"])
+	}
+	if mock.Headers["X-Request-Id"] != "req_123" {
+		t.Errorf("expected header example, got %v", mock.Headers)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(mock.Body), &body); err != nil {
+		t.Fatalf("body is not JSON: %v\n%s", err, mock.Body)
+	}
+	if body["received"] != true || body["id"] != "evt_1" {
+		t.Errorf("unexpected body: %v", body)
+	}
+}
+
+func TestMockResponse_NamedExampleAndRange(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	mock, err := doc.MockResponse("postWebhook", "4XX")
+	if err != nil {
+		t.Fatalf("MockResponse: %v", err)
+	}
+	if mock.Status != 400 {
+		t.Errorf("expected status 400 for 4XX, got %d", mock.Status)
+	}
+	if mock.Body != "{\n  \"error\": \"invalid signature\"\n}" {
+		t.Errorf("unexpected body: %s", mock.Body)
+	}
+
+	if _, err := doc.MockResponse("postWebhook", "500"); err == nil {
+		t.Error("expected an error for a missing response")
+	}
+}
+
+func TestMockResponse_RecursiveSchemaAndDefault(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	mock, err := doc.MockResponse("getOrder", "")
+	if err != nil {
+		t.Fatalf("MockResponse: %v", err)
+	}
+	if mock.Status != 200 {
+		t.Errorf("expected default response to map to 200, got %d", mock.Status)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(mock.Body), &body); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if body["status"] != "paid" || body["total"] != float64(0) {
+		t.Errorf("unexpected body: %v", body)
+	}
+	if items, ok := body["items"].([]any); !ok || len(items) != 1 {
+		t.Errorf("expected one sample item, got %v", body["items"])
+	}
+}
+
+func TestMockResponse_OperationSelection(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if ops := doc.Operations(); len(ops) != 2 || ops[0].ID != "getOrder" || ops[1].Method != "POST" {
+		t.Errorf("unexpected operations: %+v", ops)
+	}
+	if _, err := doc.MockResponse("", ""); err == nil {
+		t.Error("expected an error when the operation is ambiguous")
+	}
+	if _, err := doc.MockResponse("missing", ""); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("expected ErrOperationNotFound, got %v", err)
+	}
+}
+
+func TestMockResponse_Swagger2JSON(t *testing.T) {
+	doc, err := Parse([]byte(`{
+  "swagger": "2.0",
+  "paths": {
+    "/hook": {
+      "post": {
+        "responses": {
+          "202": {"examples": {"application/json": {"queued": true}}}
+        }
+      }
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	mock, err := doc.MockResponse("", "")
+	if err != nil {
+		t.Fatalf("MockResponse: %v", err)
+	}
+	if mock.Status != 202 || mock.Body != "{\n  \"queued\": true\n}" {
+		t.Errorf("unexpected mock: %+v", mock)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, doc := range []string{"", "- a\n- b", "openapi: 3.0.0"} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Parse(%q): expected error", doc)
+		}
+	}
+}
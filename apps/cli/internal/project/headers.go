@@ -0,0 +1,79 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envPrefix marks a header value read from an environment variable, so
+// secrets such as tokens stay out of shell history, process lists and
+// config files.
+const envPrefix = "env:"
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadHeaders reads a headers file: one "Name: value" line per header,
+// with the same comments and quoting as the config file. Values may be
+// env:NAME references (see ResolveEnv).
+func LoadHeaders(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := ParseHeaders(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return headers, nil
+}
+
+// ParseHeaders decodes a headers file.
+func ParseHeaders(data []byte) (map[string]string, error) {
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected \"Name: value\"", n)
+		}
+		value, err := unquote(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		headers[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
+
+// ResolveEnv returns headers with every env:NAME value replaced by the
+// NAME environment variable. Errors name the header and the variable but
+// never a value.
+func ResolveEnv(headers map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if ref, ok := strings.CutPrefix(value, envPrefix); ok {
+			if !envName.MatchString(ref) {
+				return nil, fmt.Errorf("header %s: invalid environment variable name %q", name, ref)
+			}
+			v, set := os.LookupEnv(ref)
+			if !set {
+				return nil, fmt.Errorf("header %s: environment variable %s is not set", name, ref)
+			}
+			value = v
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
+}
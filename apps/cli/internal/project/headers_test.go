@@ -0,0 +1,46 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]byte(`# secrets for the local handler
+Authorization: env:LOCAL_API_TOKEN
+X-Env: local   # comment
+X-Quoted: "a # b"
+`))
+	if err != nil {
+		t.Fatalf("ParseHeaders: %v", err)
+	}
+	if len(headers) != 3 || headers["Authorization"] != "env:LOCAL_API_TOKEN" || headers["X-Env"] != "local" || headers["X-Quoted"] != "a # b" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+
+	for _, data := range []string{"no colon\n", ": value\n", "X: 'open\n"} {
+		if _, err := ParseHeaders([]byte(data)); err == nil || !strings.HasPrefix(err.Error(), "line 1:") {
+			t.Errorf("ParseHeaders(%q): expected a line 1 error, got %v", data, err)
+		}
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("LOCAL_API_TOKEN", "Bearer s3cret")
+
+	got, err := ResolveEnv(map[string]string{"Authorization": "env:LOCAL_API_TOKEN", "X-Env": "local"})
+	if err != nil {
+		t.Fatalf("ResolveEnv: %v", err)
+	}
+	if got["Authorization"] != "Bearer s3cret" || got["X-Env"] != "local" {
+		t.Errorf("unexpected headers: %v", got)
+	}
+
+	_, err = ResolveEnv(map[string]string{"Authorization": "env:WHK_TEST_UNSET_VAR"})
+	if err == nil || !strings.Contains(err.Error(), "WHK_TEST_UNSET_VAR is not set") {
+		t.Errorf("expected an unset variable error, got %v", err)
+	}
+	if _, err := ResolveEnv(map[string]string{"X": "env:not-a-name"}); err == nil {
+		t.Error("expected an invalid name error")
+	}
+}
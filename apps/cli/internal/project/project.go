@@ -0,0 +1,226 @@
+// Package project reads and writes the per-project .whk.yaml file, which
+// holds the tunnel settings for a repository so a plain `whk tunnel` run
+// anywhere inside it picks them up.
+//
+// The file uses a small YAML subset: top-level "key: value" pairs and a
+// "headers:" map of indented "Name: value" lines. Values may be quoted with
+// single or double quotes, and # starts a comment.
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"webhooks.cc/cli/internal/auth"
+)
+
+// FileName is the project config file name.
+const FileName = ".whk.yaml"
+
+// Config is the contents of a .whk.yaml file. Zero values mean "not set".
+type Config struct {
+	// Endpoint is the slug of an existing endpoint to forward from.
+	Endpoint string
+	// Target is the tunnel argument: a port with an optional base path.
+	Target string
+	// Headers are added to every forwarded request.
+	Headers map[string]string
+	// HeadersFile is a headers file (see LoadHeaders) whose headers are
+	// also added, relative to the config file's directory.
+	HeadersFile string
+	// Filter names a saved filter; Query is an inline search query. Only
+	// matching requests are forwarded.
+	Filter string
+	Query  string
+	// Ephemeral deletes a created endpoint on exit.
+	Ephemeral bool
+}
+
+// Find looks for FileName in dir and its parents and returns the first path
+// found. It returns an error satisfying os.IsNotExist if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", os.ErrNotExist
+		}
+		dir = parent
+	}
+}
+
+// Load reads the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes a config file.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	inHeaders := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(stripComment(scanner.Text()), " \t")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indented := line[0] == ' ' || line[0] == '\t'
+		key, raw, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", n)
+		}
+		key = strings.TrimSpace(key)
+		value, err := unquote(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		if indented {
+			if !inHeaders {
+				return nil, fmt.Errorf("line %d: unexpected indentation", n)
+			}
+			cfg.Headers[key] = value
+			continue
+		}
+		inHeaders = false
+
+		switch key {
+		case "endpoint":
+			cfg.Endpoint = value
+		case "target":
+			cfg.Target = value
+		case "filter":
+			cfg.Filter = value
+		case "query":
+			cfg.Query = value
+		case "headersFile":
+			cfg.HeadersFile = value
+		case "ephemeral":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s must be true or false", n, key)
+			}
+			cfg.Ephemeral = b
+		case "headers":
+			if value != "" {
+				return nil, fmt.Errorf("line %d: headers must be a map of indented \"Name: value\" lines", n)
+			}
+			if cfg.Headers == nil {
+				cfg.Headers = map[string]string{}
+			}
+			inHeaders = true
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", n, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Marshal encodes cfg in the format Parse reads, omitting unset fields.
+func (cfg *Config) Marshal() []byte {
+	var b bytes.Buffer
+	b.WriteString("# whk project config: `whk tunnel` in this directory uses these settings.\n")
+	b.WriteString("# Command-line flags override them.\n")
+	writeString := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", key, quote(value))
+		}
+	}
+	writeString("endpoint", cfg.Endpoint)
+	writeString("target", cfg.Target)
+	writeString("filter", cfg.Filter)
+	writeString("query", cfg.Query)
+	writeString("headersFile", cfg.HeadersFile)
+	if cfg.Ephemeral {
+		b.WriteString("ephemeral: true\n")
+	}
+	if len(cfg.Headers) > 0 {
+		b.WriteString("headers:\n")
+		names := make([]string, 0, len(cfg.Headers))
+		for name := range cfg.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, "  %s: %s\n", name, quote(cfg.Headers[name]))
+		}
+	}
+	return b.Bytes()
+}
+
+// Write saves cfg to path.
+func Write(path string, cfg *Config) error {
+	return auth.WriteFileAtomic(path, cfg.Marshal(), 0644)
+}
+
+// stripComment removes a # comment that starts the line or follows
+// whitespace, ignoring # inside quotes.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(v string) (string, error) {
+	if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+		return strings.ReplaceAll(v[1:len(v)-1], "''", "'"), nil
+	}
+	if len(v) >= 1 && v[0] == '"' {
+		s, err := strconv.Unquote(v)
+		if err != nil {
+			return "", errors.New("invalid double-quoted value")
+		}
+		return s, nil
+	}
+	if len(v) >= 1 && v[0] == '\'' {
+		return "", errors.New("unterminated quote")
+	}
+	return v, nil
+}
+
+// quote single-quotes v when it would not read back as the same plain value.
+func quote(v string) string {
+	if v == "" || strings.ContainsAny(v, "#:'\"") || strings.TrimSpace(v) != v {
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return v
+}
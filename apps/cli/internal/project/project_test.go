@@ -0,0 +1,104 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`# settings for the payments service
+endpoint: payments-dev
+target: 3000/api/webhooks   # local handler
+query: 'method:POST header.stripe-signature:*'
+ephemeral: true
+
+headers:
+  X-Env: local
+  Authorization: "Bearer dev # not a comment"
+filter: failed-stripe
+headersFile: .whk.headers
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if cfg.Endpoint != "payments-dev" || cfg.Target != "3000/api/webhooks" || cfg.Filter != "failed-stripe" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Query != "method:POST header.stripe-signature:*" {
+		t.Errorf("Query = %q", cfg.Query)
+	}
+	if !cfg.Ephemeral {
+		t.Error("Ephemeral = false, want true")
+	}
+	if cfg.Headers["X-Env"] != "local" || cfg.Headers["Authorization"] != "Bearer dev # not a comment" {
+		t.Errorf("Headers = %v", cfg.Headers)
+	}
+	if cfg.HeadersFile != ".whk.headers" {
+		t.Errorf("HeadersFile = %q", cfg.HeadersFile)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, data := range []string{
+		"port 3000\n",
+		"unknown: x\n",
+		"ephemeral: maybe\n",
+		"  X-Env: local\n",
+		"headers: x\n",
+		"target: '3000\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q): expected error", data)
+		} else if !strings.HasPrefix(err.Error(), "line 1:") {
+			t.Errorf("Parse(%q): error should name the line, got %v", data, err)
+		}
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	cfg := &Config{
+		Endpoint:    "my-endpoint",
+		Target:      "8080/hooks",
+		Query:       `body:"it's #1"`,
+		Ephemeral:   true,
+		Headers:     map[string]string{"X-B": "two words", "X-A": "a:b"},
+		HeadersFile: "secrets/headers",
+	}
+	got, err := Parse(cfg.Marshal())
+	if err != nil {
+		t.Fatalf("Parse(Marshal()): %v\n%s", err, cfg.Marshal())
+	}
+	if got.Endpoint != cfg.Endpoint || got.Target != cfg.Target || got.Query != cfg.Query || got.Ephemeral != cfg.Ephemeral || got.HeadersFile != cfg.HeadersFile {
+		t.Errorf("round trip = %+v, want %+v", got, cfg)
+	}
+	if got.Headers["X-A"] != "a:b" || got.Headers["X-B"] != "two words" {
+		t.Errorf("Headers = %v", got.Headers)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Find(nested); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error without a config, got %v", err)
+	}
+
+	want := filepath.Join(root, FileName)
+	if err := Write(want, &Config{Target: "3000"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Find(nested)
+	if err != nil || got != want {
+		t.Fatalf("Find = %q, %v; want %q", got, err, want)
+	}
+	cfg, err := Load(got)
+	if err != nil || cfg.Target != "3000" {
+		t.Errorf("Load = %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,254 @@
+// Package proxy runs a local reverse proxy in front of a development server
+// and mirrors a copy of every request to a webhooks.cc endpoint, so local
+// API traffic can be inspected like captured webhooks.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"webhooks.cc/cli/internal/tunnel"
+	"webhooks.cc/shared/validation"
+)
+
+const (
+	// maxRequestBodySize limits request bodies buffered for proxying
+	maxRequestBodySize = 100 * 1024 * 1024 // 100MB
+	// MaxMirrorBodySize is the largest body the receiver accepts; larger
+	// requests are proxied but not mirrored
+	MaxMirrorBodySize = validation.MaxBodySize
+	// mirrorTimeout bounds sending one copy to the endpoint
+	mirrorTimeout = 10 * time.Second
+)
+
+// ErrMirrorTooLarge is reported for requests whose body is too large to mirror.
+var ErrMirrorTooLarge = errors.New("body too large to mirror")
+
+// hopHeaders are connection-specific and never copied to the mirror.
+var hopHeaders = map[string]bool{
+	"connection":          true,
+	"keep-alive":          true,
+	"proxy-connection":    true,
+	"te":                  true,
+	"trailer":             true,
+	"transfer-encoding":   true,
+	"upgrade":             true,
+	"host":                true,
+	"content-length":      true,
+	"proxy-authenticate":  true,
+	"proxy-authorization": true,
+}
+
+// receiverErrors are the error codes the receiver answers with when it
+// doesn't capture a request.
+var receiverErrors = map[string]bool{
+	"not_found":      true,
+	"expired":        true,
+	"quota_exceeded": true,
+}
+
+// Result describes one proxied request.
+type Result struct {
+	Method string
+	Path   string
+	// Status is the local server's response status, or 0 if it failed.
+	Status   int
+	Duration time.Duration
+	// Err is set when the local server could not be reached.
+	Err error
+	// MirrorErr is set when the copy could not be sent to the endpoint.
+	MirrorErr error
+}
+
+// String formats the result as a single log line.
+func (r Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s -> ", r.Method, r.Path)
+	if r.Err != nil {
+		fmt.Fprintf(&b, "ERROR: %v", r.Err)
+	} else {
+		fmt.Fprintf(&b, "%d (%dms)", r.Status, r.Duration.Milliseconds())
+	}
+	if r.MirrorErr != nil {
+		fmt.Fprintf(&b, "  [not mirrored: %v]", r.MirrorErr)
+	}
+	return b.String()
+}
+
+// Proxy forwards requests to a local target and mirrors them to an
+// endpoint. Credentials (Authorization, Cookie, ...) are passed to the
+// target but stripped from the mirrored copy.
+type Proxy struct {
+	mirrorURL  string
+	reverse    *httputil.ReverseProxy
+	httpClient *http.Client
+	onRequest  func(Result)
+	wg         sync.WaitGroup
+}
+
+// New creates a Proxy that forwards to targetURL and mirrors to mirrorURL,
+// the endpoint's capture URL (e.g. https://go.webhooks.cc/w/my-slug).
+func New(targetURL, mirrorURL string) (*Proxy, error) {
+	target, err := url.Parse(targetURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid target URL: %s", targetURL)
+	}
+	if _, err := url.Parse(mirrorURL); err != nil {
+		return nil, fmt.Errorf("invalid mirror URL: %w", err)
+	}
+
+	p := &Proxy{
+		mirrorURL:  strings.TrimSuffix(mirrorURL, "/"),
+		httpClient: &http.Client{Timeout: mirrorTimeout},
+		onRequest:  func(Result) {},
+	}
+	p.reverse = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if rec, ok := w.(*recorder); ok {
+				rec.err = err
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	return p, nil
+}
+
+// OnRequest sets a function called after each request has been proxied and
+// mirrored. It may be called from several goroutines at once.
+func (p *Proxy) OnRequest(fn func(Result)) {
+	p.onRequest = fn
+}
+
+// Wait blocks until every started mirror has finished and been reported.
+func (p *Proxy) Wait() {
+	p.wg.Wait()
+}
+
+// ServeHTTP proxies r to the target and mirrors a copy to the endpoint. The
+// mirror runs alongside the proxied request so it doesn't add latency.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	_ = r.Body.Close()
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxRequestBodySize {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	p.wg.Add(1)
+	mirrorErr := make(chan error, 1)
+	if len(body) > MaxMirrorBodySize {
+		mirrorErr <- ErrMirrorTooLarge
+	} else {
+		go func() { mirrorErr <- p.mirror(r, body) }()
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	start := time.Now()
+	rec := &recorder{ResponseWriter: w}
+	p.reverse.ServeHTTP(rec, r)
+
+	res := Result{
+		Method:   r.Method,
+		Path:     r.URL.RequestURI(),
+		Status:   rec.status,
+		Duration: time.Since(start),
+		Err:      rec.err,
+	}
+	if res.Err != nil {
+		res.Status = 0
+	}
+	go func() {
+		defer p.wg.Done()
+		res.MirrorErr = <-mirrorErr
+		p.onRequest(res)
+	}()
+}
+
+// mirror sends a copy of r with the given body to the endpoint.
+func (p *Proxy) mirror(r *http.Request, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+
+	target := p.mirrorURL + r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range r.Header {
+		lower := strings.ToLower(name)
+		if hopHeaders[lower] || tunnel.IsSensitiveHeader(lower) || validation.IsProxyHeader(lower) {
+			continue
+		}
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Mock responses may use any status, so only the receiver's own errors
+	// (unknown or expired endpoint, quota) count as failures
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests:
+		var result struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(data, &result) == nil && receiverErrors[result.Error] {
+			return fmt.Errorf("endpoint rejected the request: %s", result.Error)
+		}
+	}
+	return nil
+}
+
+// recorder captures the status written by the reverse proxy.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	err    error
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming responses are flushed through the proxy.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
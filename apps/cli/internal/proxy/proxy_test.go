@@ -0,0 +1,202 @@
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// capture records requests received by a test server.
+type capture struct {
+	mu   sync.Mutex
+	reqs []*http.Request
+	body []string
+}
+
+func (c *capture) handler(status int, reply string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.reqs = append(c.reqs, r)
+		c.body = append(c.body, string(body))
+		c.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, reply)
+	}
+}
+
+func setupProxy(t *testing.T, local, receiver http.Handler) (*Proxy, *httptest.Server, *[]Result) {
+	t.Helper()
+	localSrv := httptest.NewServer(local)
+	t.Cleanup(localSrv.Close)
+	receiverSrv := httptest.NewServer(receiver)
+	t.Cleanup(receiverSrv.Close)
+
+	p, err := New(localSrv.URL, receiverSrv.URL+"/w/my-slug")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var (
+		mu      sync.Mutex
+		results []Result
+	)
+	p.OnRequest(func(r Result) {
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+	})
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return p, srv, &results
+}
+
+func TestProxy_ForwardsAndMirrors(t *testing.T) {
+	local, receiver := &capture{}, &capture{}
+	p, srv, results := setupProxy(t, local.handler(201, "created"), receiver.handler(200, "OK"))
+
+	req, _ := http.NewRequest("POST", srv.URL+"/api/orders?debug=1", strings.NewReader(`{"id":1}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Custom", "yes")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	p.Wait()
+
+	if resp.StatusCode != 201 || string(body) != "created" {
+		t.Errorf("expected the local response, got %d %q", resp.StatusCode, body)
+	}
+
+	if len(local.reqs) != 1 || local.body[0] != `{"id":1}` {
+		t.Fatalf("local server got %d requests: %v", len(local.reqs), local.body)
+	}
+	if local.reqs[0].Header.Get("Authorization") != "Bearer secret" {
+		t.Error("local server should receive credentials")
+	}
+
+	if len(receiver.reqs) != 1 {
+		t.Fatalf("expected 1 mirrored request, got %d", len(receiver.reqs))
+	}
+	m := receiver.reqs[0]
+	if m.Method != "POST" || m.URL.Path != "/w/my-slug/api/orders" || m.URL.RawQuery != "debug=1" {
+		t.Errorf("unexpected mirror target: %s %s", m.Method, m.URL)
+	}
+	if receiver.body[0] != `{"id":1}` || m.Header.Get("X-Custom") != "yes" {
+		t.Errorf("mirror lost body or headers: %q %v", receiver.body[0], m.Header)
+	}
+	if m.Header.Get("Authorization") != "" {
+		t.Error("credentials must not be mirrored")
+	}
+
+	if len(*results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(*results))
+	}
+	r := (*results)[0]
+	if r.Status != 201 || r.Path != "/api/orders?debug=1" || r.Err != nil || r.MirrorErr != nil {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
+
+func TestProxy_LocalServerDown(t *testing.T) {
+	receiver := &capture{}
+	receiverSrv := httptest.NewServer(receiver.handler(200, "OK"))
+	defer receiverSrv.Close()
+
+	p, err := New("http://127.0.0.1:1", receiverSrv.URL+"/w/my-slug")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var result Result
+	p.OnRequest(func(r Result) { result = r })
+	srv := httptest.NewServer(p)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/hook", "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	p.Wait()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected 502, got %d", resp.StatusCode)
+	}
+	if result.Err == nil || result.Status != 0 {
+		t.Errorf("expected a local error, got %+v", result)
+	}
+	if len(receiver.reqs) != 1 {
+		t.Error("request should still be mirrored when the local server is down")
+	}
+}
+
+func TestProxy_MirrorRejected(t *testing.T) {
+	local := &capture{}
+	quota := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error":"quota_exceeded"}`)
+	})
+	p, srv, results := setupProxy(t, local.handler(200, "ok"), quota)
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	p.Wait()
+
+	if len(*results) != 1 || (*results)[0].MirrorErr == nil {
+		t.Fatalf("expected a mirror error, got %+v", *results)
+	}
+	if !strings.Contains((*results)[0].String(), "quota_exceeded") {
+		t.Errorf("result line should mention the rejection: %s", (*results)[0])
+	}
+}
+
+func TestProxy_MockStatusIsNotAMirrorError(t *testing.T) {
+	local := &capture{}
+	mock := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "mocked not found")
+	})
+	p, srv, results := setupProxy(t, local.handler(200, "ok"), mock)
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	p.Wait()
+
+	if len(*results) != 1 || (*results)[0].MirrorErr != nil {
+		t.Errorf("a mock 404 should not count as a mirror failure: %+v", *results)
+	}
+}
+
+func TestProxy_BodyTooLargeToMirror(t *testing.T) {
+	local, receiver := &capture{}, &capture{}
+	p, srv, results := setupProxy(t, local.handler(200, "ok"), receiver.handler(200, "OK"))
+
+	body := strings.Repeat("a", MaxMirrorBodySize+1)
+	resp, err := http.Post(srv.URL+"/upload", "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	p.Wait()
+
+	if len(local.reqs) != 1 || len(local.body[0]) != len(body) {
+		t.Error("large bodies should still be proxied")
+	}
+	if len(receiver.reqs) != 0 {
+		t.Error("large bodies should not be mirrored")
+	}
+	if len(*results) != 1 || !errors.Is((*results)[0].MirrorErr, ErrMirrorTooLarge) {
+		t.Errorf("expected ErrMirrorTooLarge, got %+v", *results)
+	}
+}
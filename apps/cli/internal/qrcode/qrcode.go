@@ -0,0 +1,500 @@
+// Package qrcode encodes short text, such as an endpoint URL, as a QR code
+// and renders it for the terminal, so a phone or device under test can scan
+// the URL instead of typing it.
+//
+// Only what URLs need is implemented: byte mode at error correction level M,
+// versions 1 to 10 (up to 213 bytes of text).
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxLen is the longest text Encode accepts, in bytes.
+const MaxLen = 213
+
+// quietZone is the light border, in modules, scanners need around a code
+const quietZone = 4
+
+// Code is an encoded QR code.
+type Code struct {
+	// Size is the width and height in modules.
+	Size    int
+	modules [][]bool
+	// function marks finder, timing, alignment, format and version modules,
+	// which masking and data placement skip
+	function [][]bool
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// version describes the error correction blocks of one version at level M.
+type version struct {
+	eccPerBlock int
+	// blocks lists the number of data codewords in each block
+	blocks    []int
+	alignment []int
+}
+
+func (v version) dataCodewords() int {
+	n := 0
+	for _, b := range v.blocks {
+		n += b
+	}
+	return n
+}
+
+// versions is indexed by version number.
+var versions = [...]version{
+	1:  {10, []int{16}, nil},
+	2:  {16, []int{28}, []int{6, 18}},
+	3:  {26, []int{44}, []int{6, 22}},
+	4:  {18, []int{32, 32}, []int{6, 26}},
+	5:  {24, []int{43, 43}, []int{6, 30}},
+	6:  {16, []int{27, 27, 27, 27}, []int{6, 34}},
+	7:  {18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	8:  {22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	9:  {22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	10: {26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+// Encode returns the QR code for text, using the smallest version it
+// fits in.
+func Encode(text string) (*Code, error) {
+	ver := 0
+	for v := 1; v < len(versions); v++ {
+		if 4+countBits(v)+8*len(text) <= 8*versions[v].dataCodewords() {
+			ver = v
+			break
+		}
+	}
+	if ver == 0 {
+		return nil, fmt.Errorf("text too long for a QR code (%d bytes, max %d)", len(text), MaxLen)
+	}
+
+	c := newCode(ver)
+	c.drawFunctionPatterns(ver)
+	c.drawCodewords(codewords(ver, []byte(text)))
+
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // undo
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+	return c, nil
+}
+
+// countBits is the length of the byte mode character count field.
+func countBits(ver int) int {
+	if ver < 10 {
+		return 8
+	}
+	return 16
+}
+
+func newCode(ver int) *Code {
+	size := 17 + 4*ver
+	c := &Code{Size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for y := range size {
+		c.modules[y] = make([]bool, size)
+		c.function[y] = make([]bool, size)
+	}
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(ver int) {
+	for i := range c.Size {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	for _, p := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x < 0 || x >= c.Size || y < 0 || y >= c.Size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				c.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap a finder
+	pos := versions[ver].alignment
+	last := len(pos) - 1
+	for i, cy := range pos {
+		for j, cx := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; drawFormatBits fills them in
+	c.drawFormatBits(0)
+
+	if ver >= 7 {
+		bits := versionBits(ver)
+		for i := range 18 {
+			dark := bits>>i&1 == 1
+			a, b := c.Size-11+i%3, i/3
+			c.setFunction(a, b, dark)
+			c.setFunction(b, a, dark)
+		}
+	}
+}
+
+// formatBits returns the 15-bit format information for level M and a mask:
+// the 5 data bits followed by their BCH(15,5) code, XORed with 0x5412.
+func formatBits(mask int) int {
+	const levelM = 0b00
+	data := levelM<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionBits returns the 18-bit version information: the version followed
+// by its BCH(18,6) code.
+func versionBits(ver int) int {
+	rem := ver
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return ver<<12 | rem
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	// Around the top-left finder
+	for i := range 6 {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := range 8 {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true) // always dark
+}
+
+// codewords encodes data in byte mode and returns the data and error
+// correction codewords in their interleaved order.
+func codewords(ver int, data []byte) []byte {
+	v := versions[ver]
+	capacity := v.dataCodewords() * 8
+
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits(ver))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0xEC; bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	all := bits.bytes()
+
+	blocks := make([][]byte, len(v.blocks))
+	eccs := make([][]byte, len(v.blocks))
+	divisor := rsDivisor(v.eccPerBlock)
+	for i, n := range v.blocks {
+		blocks[i], all = all[:n], all[n:]
+		eccs[i] = rsRemainder(blocks[i], divisor)
+	}
+
+	var out []byte
+	for i := range v.blocks[len(v.blocks)-1] {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := range v.eccPerBlock {
+		for _, e := range eccs {
+			out = append(out, e[i])
+		}
+	}
+	return out
+}
+
+// drawCodewords places codewords in the zigzag order: pairs of columns from
+// the right, alternately upwards and downwards, skipping function modules.
+// Modules left over are remainder bits and stay light.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := range c.Size {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if c.function[y][x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask XORs a mask pattern onto the data modules. Applying the same
+// mask twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			c.modules[y][x] = c.modules[y][x] != invert
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, following the four rules
+// the standard uses to pick a mask: lower is better.
+func (c *Code) penalty() int {
+	score := 0
+	line := make([]bool, c.Size)
+	for _, vertical := range []bool{false, true} {
+		for a := range c.Size {
+			for b := range c.Size {
+				if vertical {
+					line[b] = c.modules[b][a]
+				} else {
+					line[b] = c.modules[a][b]
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x < c.Size-1 && y < c.Size-1 {
+				v := c.modules[y][x]
+				if c.modules[y][x+1] == v && c.modules[y+1][x] == v && c.modules[y+1][x+1] == v {
+					score += 3
+				}
+			}
+		}
+	}
+	total := c.Size * c.Size
+	// 10 points for every 5% the dark proportion is away from 50%
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+// finderLike is the 1:1:3:1:1 pattern that scanners could mistake for a
+// finder when it has four light modules on one side.
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+func linePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+
+	light := func(i int) bool { return i < 0 || i >= len(line) || !line[i] }
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, dark := range finderLike {
+			if line[i+j] != dark {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		before, after := true, true
+		for k := 1; k <= 4; k++ {
+			before = before && light(i-k)
+			after = after && light(i+len(finderLike)-1+k)
+		}
+		if before || after {
+			score += 40
+		}
+	}
+	return score
+}
+
+// Render draws the code with a quiet zone, two rows of modules per line of
+// text using half blocks. Dark modules are drawn, so the result must be
+// shown dark-on-light (for example with a black foreground on a white
+// background) to scan on any terminal theme.
+func (c *Code) Render() string {
+	n := c.Size + 2*quietZone
+	dark := func(x, y int) bool {
+		x, y = x-quietZone, y-quietZone
+		return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+	}
+	var b strings.Builder
+	for y := 0; y < n; y += 2 {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := range n {
+			top, bottom := dark(x, y), y+1 < n && dark(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return b.String()
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		b.bits = append(b.bits, value>>i&1 == 1)
+	}
+}
+
+func (b *bitBuffer) len() int { return len(b.bits) }
+
+func (b *bitBuffer) bytes() []byte {
+	out := make([]byte, len(b.bits)/8)
+	for i, bit := range b.bits {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the given
+// degree over GF(256), without its leading 1 coefficient.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range degree {
+			result[j] = gfMul(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords for data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies in GF(256) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,211 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// Version 1-M "HELLO WORLD" from the worked example in ISO/IEC 18004
+	// tutorials
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	for mask, want := range []int{
+		0b101010000010010,
+		0b101000100100101,
+		0b101111001111100,
+		0b101101101001011,
+		0b100010111111001,
+		0b100000011001110,
+		0b100111110010111,
+		0b100101010100000,
+	} {
+		if got := formatBits(mask); got != want {
+			t.Errorf("formatBits(%d) = %015b, want %015b", mask, got, want)
+		}
+	}
+	if got := versionBits(7); got != 0b000111110010010100 {
+		t.Errorf("versionBits(7) = %018b", got)
+	}
+	if got := versionBits(10); got != 0b001010010011010011 {
+		t.Errorf("versionBits(10) = %018b", got)
+	}
+}
+
+func TestEncode_Versions(t *testing.T) {
+	tests := []struct {
+		n, size int
+	}{
+		{14, 21},  // version 1
+		{15, 25},  // version 2
+		{100, 41}, // version 6
+		{150, 49}, // version 8
+		{MaxLen, 57},
+	}
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.n))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", tt.n, err)
+		}
+		if c.Size != tt.size {
+			t.Errorf("Encode(%d bytes): size %d, want %d", tt.n, c.Size, tt.size)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", MaxLen+1)); err == nil {
+		t.Error("expected an error for text over MaxLen")
+	}
+}
+
+// TestEncode_RoundTrip reads codes back the way a scanner would once it has
+// located the modules: format information, unmasking, codeword order and
+// error correction.
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"https://go.webhooks.cc/w/my-endpoint",
+		"https://hooks.example.com/w/" + strings.Repeat("x", 90),
+		"https://hooks.example.com/w/" + strings.Repeat("é", 80),
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		ver := (c.Size - 17) / 4
+
+		// Both copies of the format information must agree
+		var first, second int
+		for i := range 6 {
+			first |= b2i(c.Dark(8, i)) << i
+		}
+		first |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+		for i := 9; i < 15; i++ {
+			first |= b2i(c.Dark(14-i, 8)) << i
+		}
+		for i := range 8 {
+			second |= b2i(c.Dark(c.Size-1-i, 8)) << i
+		}
+		for i := 8; i < 15; i++ {
+			second |= b2i(c.Dark(8, c.Size-15+i)) << i
+		}
+		if first != second {
+			t.Fatalf("format copies differ: %015b != %015b", first, second)
+		}
+		mask := -1
+		for m := range 8 {
+			if formatBits(m) == first {
+				mask = m
+			}
+		}
+		if mask < 0 {
+			t.Fatalf("invalid format information %015b", first)
+		}
+
+		// Unmask and read the codewords
+		c.applyMask(mask)
+		var bits []bool
+		for right := c.Size - 1; right >= 1; right -= 2 {
+			if right == 6 {
+				right = 5
+			}
+			for vert := range c.Size {
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				for j := range 2 {
+					if !c.function[y][right-j] {
+						bits = append(bits, c.Dark(right-j, y))
+					}
+				}
+			}
+		}
+		c.applyMask(mask)
+		raw := make([]byte, len(bits)/8)
+		for i := range raw {
+			for j := range 8 {
+				raw[i] = raw[i]<<1 | byte(b2i(bits[i*8+j]))
+			}
+		}
+
+		// De-interleave and check each block's error correction
+		v := versions[ver]
+		blocks := make([][]byte, len(v.blocks))
+		pos := 0
+		for i := range v.blocks[len(v.blocks)-1] {
+			for b, n := range v.blocks {
+				if i < n {
+					blocks[b] = append(blocks[b], raw[pos])
+					pos++
+				}
+			}
+		}
+		var data []byte
+		for b := range blocks {
+			var ecc []byte
+			for i := range v.eccPerBlock {
+				ecc = append(ecc, raw[pos+i*len(blocks)+b])
+			}
+			if want := rsRemainder(blocks[b], rsDivisor(v.eccPerBlock)); !bytes.Equal(ecc, want) {
+				t.Errorf("%d bytes: block %d has wrong error correction", len(text), b)
+			}
+			data = append(data, blocks[b]...)
+		}
+
+		// Byte mode header, then the text
+		if data[0]>>4 != 0b0100 {
+			t.Fatalf("mode = %04b, want byte mode", data[0]>>4)
+		}
+		var n, off int
+		if countBits(ver) == 8 {
+			n = int(data[0]&0x0F)<<4 | int(data[1]>>4)
+			off = 1
+		} else {
+			n = int(data[0]&0x0F)<<12 | int(data[1])<<4 | int(data[2]>>4)
+			off = 2
+		}
+		got := make([]byte, n)
+		for i := range got {
+			got[i] = data[off+i]<<4 | data[off+i+1]>>4
+		}
+		if string(got) != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode("https://go.webhooks.cc/w/abc")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	lines := strings.Split(c.Render(), "\n")
+	width := c.Size + 2*quietZone
+	if want := (width + 1) / 2; len(lines) != want {
+		t.Errorf("got %d lines, want %d", len(lines), want)
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Fatalf("line %d is %d wide, want %d", i, n, width)
+		}
+	}
+	// The quiet zone is blank, then the top of the finder patterns
+	if strings.TrimSpace(lines[0]) != "" || strings.TrimSpace(lines[1]) != "" {
+		t.Error("expected blank quiet zone lines")
+	}
+	if !strings.HasPrefix(lines[2], "    █▀▀▀▀▀█") {
+		t.Errorf("expected a finder pattern, got %q", lines[2])
+	}
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+// Package queue holds the requests `whk tunnel --queue-offline` couldn't
+// deliver because the local target was down, so they can be delivered in
+// order once it is back, even after the tunnel restarts.
+//
+// Each endpoint's queue is a directory, queue/<slug> in the whk config
+// directory, with one JSON file per request named by its position.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/shared/types"
+)
+
+const (
+	queueDir = "queue"
+	// MaxPerEndpoint is how many requests an endpoint's queue holds
+	MaxPerEndpoint = 1000
+)
+
+// ErrFull is returned by Push when the queue holds MaxPerEndpoint requests.
+var ErrFull = fmt.Errorf("queue is full (%d requests)", MaxPerEndpoint)
+
+// Queue is one endpoint's queue of undelivered requests, oldest first. It
+// is safe for concurrent use.
+type Queue struct {
+	dir string
+
+	mu sync.Mutex
+	// seqs are the positions of the queued requests, ascending
+	seqs []int64
+}
+
+// Open opens the queue for an endpoint, loading requests left by an
+// earlier tunnel.
+func Open(slug string) (*Queue, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	q := &Queue{dir: filepath.Join(configPath, queueDir, slug)}
+	entries, err := os.ReadDir(q.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		seq, err := strconv.ParseInt(strings.TrimSuffix(e.Name(), ".json"), 10, 64)
+		if err != nil || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		q.seqs = append(q.seqs, seq)
+	}
+	sort.Slice(q.seqs, func(i, j int) bool { return q.seqs[i] < q.seqs[j] })
+	return q, nil
+}
+
+func (q *Queue) path(seq int64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d.json", seq))
+}
+
+// Len returns the number of queued requests.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.seqs)
+}
+
+// Push adds a request to the end of the queue.
+func (q *Queue) Push(req *types.CapturedRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.seqs) >= MaxPerEndpoint {
+		return ErrFull
+	}
+	var seq int64 = 1
+	if n := len(q.seqs); n > 0 {
+		seq = q.seqs[n-1] + 1
+	}
+	if err := os.MkdirAll(q.dir, 0700); err != nil {
+		return err
+	}
+	if err := auth.WriteFileAtomic(q.path(seq), data, 0600); err != nil {
+		return err
+	}
+	q.seqs = append(q.seqs, seq)
+	return nil
+}
+
+// Peek returns the oldest queued request without removing it, or nil if
+// the queue is empty. A request that can't be read is dropped.
+func (q *Queue) Peek() (*types.CapturedRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.seqs) == 0 {
+		return nil, nil
+	}
+	path := q.path(q.seqs[0])
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var req types.CapturedRequest
+		if err = json.Unmarshal(data, &req); err == nil {
+			return &req, nil
+		}
+	}
+	_ = os.Remove(path)
+	q.seqs = q.seqs[1:]
+	return nil, fmt.Errorf("dropped unreadable queued request %s: %w", filepath.Base(path), err)
+}
+
+// Pop removes the oldest queued request, once it has been delivered.
+func (q *Queue) Pop() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.seqs) == 0 {
+		return nil
+	}
+	if err := os.Remove(q.path(q.seqs[0])); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	q.seqs = q.seqs[1:]
+	if len(q.seqs) == 0 {
+		// Leave no empty directory behind
+		_ = os.Remove(q.dir)
+	}
+	return nil
+}
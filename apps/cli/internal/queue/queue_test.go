@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestQueue_PushPeekPop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	q, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if req, err := q.Peek(); req != nil || err != nil {
+		t.Fatalf("expected an empty queue, got %v, %v", req, err)
+	}
+	for _, id := range []string{"r1", "r2", "r3"} {
+		if err := q.Push(&types.CapturedRequest{ID: id, Method: "POST", Body: "{}"}); err != nil {
+			t.Fatalf("Push: %v", err)
+		}
+	}
+
+	// A new tunnel picks up where this one left off, in order
+	q, err = Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if q.Len() != 3 {
+		t.Fatalf("expected 3 queued requests, got %d", q.Len())
+	}
+	for _, want := range []string{"r1", "r2", "r3"} {
+		req, err := q.Peek()
+		if err != nil || req == nil || req.ID != want {
+			t.Fatalf("Peek = %v, %v; want %s", req, err, want)
+		}
+		if err := q.Pop(); err != nil {
+			t.Fatalf("Pop: %v", err)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("expected an empty queue, got %d", q.Len())
+	}
+	if _, err := os.Stat(q.dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the empty queue's directory to be removed, got %v", err)
+	}
+}
+
+func TestQueue_Full(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	q, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	// Pretend the queue is nearly full rather than writing every file
+	for i := range MaxPerEndpoint - 1 {
+		q.seqs = append(q.seqs, int64(i+1))
+	}
+	if err := q.Push(&types.CapturedRequest{ID: "last"}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if err := q.Push(&types.CapturedRequest{ID: "over"}); !errors.Is(err, ErrFull) {
+		t.Errorf("expected ErrFull, got %v", err)
+	}
+}
+
+func TestQueue_UnreadableRequestDropped(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	q, err := Open("my-ep")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := q.Push(&types.CapturedRequest{ID: "r1"}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if err := q.Push(&types.CapturedRequest{ID: "r2"}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if err := os.WriteFile(q.path(1), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Peek(); err == nil {
+		t.Fatal("expected an error for the unreadable request")
+	}
+	req, err := q.Peek()
+	if err != nil || req == nil || req.ID != "r2" {
+		t.Errorf("expected r2 after the unreadable request, got %v, %v", req, err)
+	}
+	if _, err := os.Stat(filepath.Join(q.dir, "00000000000000000001.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the unreadable request to be removed, got %v", err)
+	}
+}
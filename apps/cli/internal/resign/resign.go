@@ -0,0 +1,207 @@
+// Package resign recomputes webhook provider signatures over a captured
+// request's body, so a replayed request still passes the signature check
+// in a local handler that verifies with the provider's secret. It follows
+// the same schemes as the SDK's signed templates.
+package resign
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"mime"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"webhooks.cc/shared/types"
+)
+
+// signer sets the signature headers for one provider. body is the raw body
+// and target the URL the request is delivered to.
+type signer func(req *types.CapturedRequest, body []byte, secret, target string, now time.Time)
+
+var signers = map[string]signer{
+	"stripe": func(req *types.CapturedRequest, body []byte, secret, _ string, now time.Time) {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		sig := hexMAC(sha256.New, []byte(secret), ts+"."+string(body))
+		req.SetHeader("Stripe-Signature", "t="+ts+",v1="+sig)
+	},
+	"github": func(req *types.CapturedRequest, body []byte, secret, _ string, _ time.Time) {
+		req.SetHeader("X-Hub-Signature-256", "sha256="+hexMAC(sha256.New, []byte(secret), string(body)))
+		// Older handlers still check the SHA-1 header; only keep it if
+		// GitHub sent it
+		if len(req.HeaderValues("X-Hub-Signature")) > 0 {
+			req.SetHeader("X-Hub-Signature", "sha1="+hexMAC(sha1.New, []byte(secret), string(body)))
+		}
+	},
+	"shopify": func(req *types.CapturedRequest, body []byte, secret, _ string, _ time.Time) {
+		req.SetHeader("X-Shopify-Hmac-Sha256", base64MAC(sha256.New, []byte(secret), string(body)))
+	},
+	"twilio": func(req *types.CapturedRequest, body []byte, secret, target string, _ time.Time) {
+		req.SetHeader("X-Twilio-Signature", base64MAC(sha1.New, []byte(secret), twilioPayload(req, body, target)))
+	},
+	"slack": func(req *types.CapturedRequest, body []byte, secret, _ string, now time.Time) {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		req.SetHeader("X-Slack-Request-Timestamp", ts)
+		req.SetHeader("X-Slack-Signature", "v0="+hexMAC(sha256.New, []byte(secret), "v0:"+ts+":"+string(body)))
+	},
+	"paddle": func(req *types.CapturedRequest, body []byte, secret, _ string, now time.Time) {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		req.SetHeader("Paddle-Signature", "ts="+ts+";h1="+hexMAC(sha256.New, []byte(secret), ts+":"+string(body)))
+	},
+	"linear": func(req *types.CapturedRequest, body []byte, secret, _ string, _ time.Time) {
+		req.SetHeader("Linear-Signature", hexMAC(sha256.New, []byte(secret), string(body)))
+	},
+	"vercel": func(req *types.CapturedRequest, body []byte, secret, _ string, _ time.Time) {
+		req.SetHeader("X-Vercel-Signature", hexMAC(sha1.New, []byte(secret), string(body)))
+	},
+	"gitlab": func(req *types.CapturedRequest, _ []byte, secret, _ string, _ time.Time) {
+		req.SetHeader("X-Gitlab-Token", secret)
+	},
+	"standard-webhooks": func(req *types.CapturedRequest, body []byte, secret, _ string, now time.Time) {
+		id, ts, sig := standardSignature(req, body, secret, now)
+		req.SetHeader("webhook-id", id)
+		req.SetHeader("webhook-timestamp", ts)
+		req.SetHeader("webhook-signature", sig)
+	},
+	// Clerk delivers through Svix, which sends the Standard Webhooks
+	// headers under both prefixes
+	"clerk": func(req *types.CapturedRequest, body []byte, secret, _ string, now time.Time) {
+		id, ts, sig := standardSignature(req, body, secret, now)
+		for _, prefix := range []string{"webhook-", "svix-"} {
+			req.SetHeader(prefix+"id", id)
+			req.SetHeader(prefix+"timestamp", ts)
+			req.SetHeader(prefix+"signature", sig)
+		}
+	},
+}
+
+// Providers returns the provider names Sign accepts, sorted.
+func Providers() []string {
+	names := make([]string, 0, len(signers))
+	for name := range signers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check reports an error if provider isn't one Sign accepts.
+func Check(provider string) error {
+	if _, ok := signers[provider]; !ok {
+		return fmt.Errorf("unknown provider: %s (must be one of %s)", provider, strings.Join(Providers(), ", "))
+	}
+	return nil
+}
+
+// Sign replaces req's signature headers for provider with ones computed
+// over its current body with secret. target is the URL the request will be
+// sent to, which Twilio includes in the signature. Timestamped schemes are
+// signed at now, so handlers that reject old deliveries accept the replay.
+func Sign(req *types.CapturedRequest, provider, secret, target string, now time.Time) error {
+	if err := Check(provider); err != nil {
+		return err
+	}
+	if secret == "" {
+		return fmt.Errorf("a signing secret is required")
+	}
+	body, err := req.BodyBytes()
+	if err != nil {
+		return err
+	}
+	signers[provider](req, body, secret, target, now)
+	return nil
+}
+
+func mac(h func() hash.Hash, key []byte, payload string) []byte {
+	m := hmac.New(h, key)
+	m.Write([]byte(payload))
+	return m.Sum(nil)
+}
+
+func hexMAC(h func() hash.Hash, key []byte, payload string) string {
+	return hex.EncodeToString(mac(h, key, payload))
+}
+
+func base64MAC(h func() hash.Hash, key []byte, payload string) string {
+	return base64.StdEncoding.EncodeToString(mac(h, key, payload))
+}
+
+// twilioPayload is what Twilio signs: the full URL, followed by each form
+// parameter's name and value sorted by name. Other bodies are appended as
+// is.
+func twilioPayload(req *types.CapturedRequest, body []byte, target string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType(req))
+	if mediaType != "application/x-www-form-urlencoded" {
+		return target + string(body)
+	}
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		return target + string(body)
+	}
+	var b strings.Builder
+	b.WriteString(target)
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values := append([]string(nil), params[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+	return b.String()
+}
+
+func contentType(req *types.CapturedRequest) string {
+	if req.ContentType != "" {
+		return req.ContentType
+	}
+	if values := req.HeaderValues("Content-Type"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// standardSignature signs body the Standard Webhooks way, keeping the
+// captured message ID so handlers that dedupe by it see the same delivery.
+func standardSignature(req *types.CapturedRequest, body []byte, secret string, now time.Time) (id, ts, sig string) {
+	for _, name := range []string{"webhook-id", "svix-id"} {
+		if values := req.HeaderValues(name); len(values) > 0 && values[0] != "" {
+			id = values[0]
+			break
+		}
+	}
+	if id == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		id = "msg_" + hex.EncodeToString(b)
+	}
+	ts = strconv.FormatInt(now.Unix(), 10)
+	sig = "v1," + base64MAC(sha256.New, standardSecret(secret), id+"."+ts+"."+string(body))
+	return id, ts, sig
+}
+
+// standardSecret decodes a whsec_ secret. Secrets that aren't base64 are
+// used as raw bytes, as the SDK does.
+func standardSecret(secret string) []byte {
+	raw, hadPrefix := strings.CutPrefix(secret, "whsec_")
+	if key, err := base64.StdEncoding.DecodeString(raw); err == nil {
+		return key
+	}
+	if hadPrefix {
+		return []byte(secret)
+	}
+	return []byte(raw)
+}
@@ -0,0 +1,129 @@
+package resign
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"webhooks.cc/shared/types"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func TestSign_Stripe(t *testing.T) {
+	req := &types.CapturedRequest{
+		Body:    `{"id":"evt_1","edited":true}`,
+		Headers: map[string]string{"stripe-signature": "t=1,v1=stale"},
+	}
+	if err := Sign(req, "stripe", "whsec_test", "", now); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	m := hmac.New(sha256.New, []byte("whsec_test"))
+	m.Write([]byte("1700000000." + req.Body))
+	want := "t=1700000000,v1=" + hex.EncodeToString(m.Sum(nil))
+	if got := req.HeaderValues("Stripe-Signature"); len(got) != 1 || got[0] != want {
+		t.Errorf("Stripe-Signature = %v, want [%s]", got, want)
+	}
+}
+
+func TestSign_GitHub(t *testing.T) {
+	req := &types.CapturedRequest{
+		Body: `{"zen":"hi"}`,
+		HeaderFields: []types.HeaderField{
+			{Name: "X-Hub-Signature-256", Value: "sha256=stale"},
+			{Name: "X-Hub-Signature", Value: "sha1=stale"},
+		},
+	}
+	if err := Sign(req, "github", "s3cret", "", now); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	m := hmac.New(sha256.New, []byte("s3cret"))
+	m.Write([]byte(req.Body))
+	if got := req.HeaderValues("X-Hub-Signature-256"); len(got) != 1 || got[0] != "sha256="+hex.EncodeToString(m.Sum(nil)) {
+		t.Errorf("X-Hub-Signature-256 = %v", got)
+	}
+	m = hmac.New(sha1.New, []byte("s3cret"))
+	m.Write([]byte(req.Body))
+	if got := req.HeaderValues("X-Hub-Signature"); len(got) != 1 || got[0] != "sha1="+hex.EncodeToString(m.Sum(nil)) {
+		t.Errorf("X-Hub-Signature = %v", got)
+	}
+}
+
+func TestSign_Twilio(t *testing.T) {
+	req := &types.CapturedRequest{
+		Body:        "To=%2B18005551212&From=%2B14158675310&Digits=1234&CallSid=CA1234567890ABCDE",
+		ContentType: "application/x-www-form-urlencoded; charset=utf-8",
+	}
+	target := "http://localhost:3000/voice?foo=1"
+	if err := Sign(req, "twilio", "12345", target, now); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	m := hmac.New(sha1.New, []byte("12345"))
+	m.Write([]byte(target + "CallSidCA1234567890ABCDEDigits1234From+14158675310To+18005551212"))
+	want := base64.StdEncoding.EncodeToString(m.Sum(nil))
+	if got := req.HeaderValues("X-Twilio-Signature"); len(got) != 1 || got[0] != want {
+		t.Errorf("X-Twilio-Signature = %v, want [%s]", got, want)
+	}
+}
+
+func TestSign_StandardWebhooks(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	secret := "whsec_" + base64.StdEncoding.EncodeToString(key)
+	req := &types.CapturedRequest{
+		Body: `{"type":"user.created"}`,
+		Headers: map[string]string{
+			"svix-id":        "msg_captured",
+			"svix-timestamp": "1",
+			"svix-signature": "v1,stale",
+		},
+	}
+	if err := Sign(req, "clerk", secret, "", now); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte("msg_captured.1700000000." + req.Body))
+	want := "v1," + base64.StdEncoding.EncodeToString(m.Sum(nil))
+	for _, prefix := range []string{"webhook-", "svix-"} {
+		if got := req.Headers[prefix+"id"]; got != "msg_captured" {
+			t.Errorf("%sid = %q, want the captured message ID", prefix, got)
+		}
+		if got := req.Headers[prefix+"timestamp"]; got != "1700000000" {
+			t.Errorf("%stimestamp = %q", prefix, got)
+		}
+		if got := req.Headers[prefix+"signature"]; got != want {
+			t.Errorf("%ssignature = %q, want %q", prefix, got, want)
+		}
+	}
+
+	// Without a captured ID one is generated
+	req = &types.CapturedRequest{Body: "{}"}
+	if err := Sign(req, "standard-webhooks", secret, "", now); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if !strings.HasPrefix(req.Headers["webhook-id"], "msg_") {
+		t.Errorf("expected a generated message ID, got %q", req.Headers["webhook-id"])
+	}
+}
+
+func TestSign_Errors(t *testing.T) {
+	tests := []struct {
+		name, provider, secret, wantErr string
+		req                             *types.CapturedRequest
+	}{
+		{"unknown provider", "acme", "s", "unknown provider", &types.CapturedRequest{}},
+		{"missing secret", "stripe", "", "secret is required", &types.CapturedRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Sign(tt.req, tt.provider, tt.secret, "", now)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package sessions keeps track of the ephemeral endpoints created by running
+// tunnels, so endpoints left behind by a tunnel that crashed or was killed
+// can be found and reused or cleaned up by the next `whk tunnel`.
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"syscall"
+	"time"
+
+	"webhooks.cc/cli/internal/auth"
+)
+
+const sessionsFile = "tunnels.json"
+
+// Session is an ephemeral endpoint owned by a tunnel process.
+type Session struct {
+	Slug      string `json:"slug"`
+	Target    string `json:"target"`
+	PID       int    `json:"pid"`
+	CreatedAt int64  `json:"createdAt"`
+	// ExpiresAt is set when the endpoint was created with a TTL; the
+	// server deletes it on its own after that.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// processAlive reports whether a process with the given PID is running.
+// It is a variable so tests can stub it.
+var processAlive = func(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		// On Windows FindProcess fails for processes that don't exist
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	// On Unix FindProcess always succeeds; signal 0 checks existence
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// ProcessAlive reports whether a process with the given PID is running.
+func ProcessAlive(pid int) bool {
+	return processAlive(pid)
+}
+
+func sessionsPath() (string, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configPath, sessionsFile), nil
+}
+
+// load reads every recorded session. A missing file is empty.
+func load() ([]Session, error) {
+	path, err := sessionsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved []Session
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", sessionsFile, err)
+	}
+	return saved, nil
+}
+
+func store(saved []Session) error {
+	path, err := sessionsPath()
+	if err != nil {
+		return err
+	}
+	if len(saved) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return auth.WriteFileAtomic(path, append(data, '\n'), 0600)
+}
+
+// update replaces the recorded sessions with fn's result while holding the
+// file lock, so concurrently running tunnels don't drop each other's
+// entries. The file is only rewritten if fn changed the number of sessions
+// or always is set.
+func update(always bool, fn func(saved []Session) []Session) error {
+	path, err := sessionsPath()
+	if err != nil {
+		return err
+	}
+	return auth.WithFileLock(path, func() error {
+		saved, err := load()
+		if err != nil {
+			return err
+		}
+		n := len(saved)
+		saved = fn(saved)
+		if !always && len(saved) == n {
+			return nil
+		}
+		return store(saved)
+	})
+}
+
+// without returns saved minus the session for slug, reusing its storage.
+func without(saved []Session, slug string) []Session {
+	kept := saved[:0]
+	for _, s := range saved {
+		if s.Slug != slug {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// Record stores s, replacing any session for the same slug.
+func Record(s Session) error {
+	return update(true, func(saved []Session) []Session {
+		return append(without(saved, s.Slug), s)
+	})
+}
+
+// Remove forgets the session for slug. Removing an unknown slug is not an
+// error.
+func Remove(slug string) error {
+	return update(false, func(saved []Session) []Session {
+		return without(saved, slug)
+	})
+}
+
+// Orphans returns the sessions whose tunnel process is no longer running,
+// newest first. Sessions whose endpoint has already expired server-side
+// are dropped from the file.
+func Orphans() ([]Session, error) {
+	now := time.Now().UnixMilli()
+	var orphans []Session
+	err := update(false, func(saved []Session) []Session {
+		live := saved[:0]
+		for _, s := range saved {
+			if s.ExpiresAt > 0 && s.ExpiresAt <= now {
+				continue
+			}
+			live = append(live, s)
+			if !processAlive(s.PID) {
+				orphans = append(orphans, s)
+			}
+		}
+		return live
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].CreatedAt > orphans[j].CreatedAt })
+	return orphans, nil
+}
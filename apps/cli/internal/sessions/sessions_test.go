@@ -0,0 +1,120 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func stubProcessAlive(t *testing.T, alive map[int]bool) {
+	t.Helper()
+	orig := processAlive
+	processAlive = func(pid int) bool { return alive[pid] }
+	t.Cleanup(func() { processAlive = orig })
+}
+
+func TestSessions_RecordAndOrphans(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	stubProcessAlive(t, map[int]bool{200: true})
+
+	now := time.Now().UnixMilli()
+	records := []Session{
+		{Slug: "old", Target: "http://localhost:3000", PID: 100, CreatedAt: now - 2000},
+		{Slug: "running", Target: "http://localhost:3000", PID: 200, CreatedAt: now - 1000},
+		{Slug: "new", Target: "http://localhost:8080", PID: 300, CreatedAt: now},
+	}
+	for _, s := range records {
+		if err := Record(s); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, ".config/whk", sessionsFile))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected file permissions 0600, got %o", perm)
+	}
+
+	orphans, err := Orphans()
+	if err != nil {
+		t.Fatalf("Orphans: %v", err)
+	}
+	if len(orphans) != 2 || orphans[0].Slug != "new" || orphans[1].Slug != "old" {
+		t.Fatalf("expected orphans [new old], got %+v", orphans)
+	}
+
+	// Re-recording a slug (a resumed tunnel) replaces the old entry
+	if err := Record(Session{Slug: "old", Target: "http://localhost:3000", PID: 200, CreatedAt: now}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := Remove("new"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	orphans, err = Orphans()
+	if err != nil {
+		t.Fatalf("Orphans: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("expected no orphans, got %+v", orphans)
+	}
+}
+
+func TestSessions_RemoveUnknownAndLast(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := Remove("missing"); err != nil {
+		t.Fatalf("Remove on empty state: %v", err)
+	}
+	if err := Record(Session{Slug: "abc", PID: 1}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := Remove("abc"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".config/whk", sessionsFile)); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed with the last session, got %v", err)
+	}
+}
+
+func TestSessions_OrphansDropsExpired(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	stubProcessAlive(t, nil)
+
+	now := time.Now().UnixMilli()
+	if err := Record(Session{Slug: "expired", PID: 1, CreatedAt: now - 5000, ExpiresAt: now - 1000}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := Record(Session{Slug: "ttl", PID: 2, CreatedAt: now, ExpiresAt: now + 60000}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	orphans, err := Orphans()
+	if err != nil {
+		t.Fatalf("Orphans: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Slug != "ttl" {
+		t.Fatalf("expected only the unexpired orphan, got %+v", orphans)
+	}
+	saved, err := load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(saved) != 1 {
+		t.Errorf("expected the expired session to be pruned, got %+v", saved)
+	}
+}
+
+func TestProcessAlive_Self(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("expected the current process to be alive")
+	}
+	if processAlive(0) {
+		t.Error("expected pid 0 to be reported as not running")
+	}
+}
@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"webhooks.cc/shared/types"
+)
+
+const (
+	// RateWindow is the span requests/sec is measured over.
+	RateWindow = 10 * time.Second
+	// statsTopPaths is how many paths the stats view lists.
+	statsTopPaths = 5
+)
+
+// Stats aggregates requests from the stream for whk listen --stats. It is
+// safe for concurrent use.
+type Stats struct {
+	mu       sync.Mutex
+	start    time.Time
+	total    int
+	bytes    int
+	methods  map[string]int
+	paths    map[string]int
+	statuses map[int]int
+	// arrivals holds the times of requests within RateWindow, oldest first
+	arrivals []time.Time
+}
+
+// NewStats creates an empty Stats. Rates are measured from start until
+// RateWindow has passed.
+func NewStats(start time.Time) *Stats {
+	return &Stats{
+		start:    start,
+		methods:  map[string]int{},
+		paths:    map[string]int{},
+		statuses: map[int]int{},
+	}
+}
+
+// Add counts a request that arrived at the given time.
+func (s *Stats) Add(req *types.CapturedRequest, at time.Time) {
+	path, _, _ := strings.Cut(req.Path, "?")
+	if path == "" {
+		path = "/"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	s.bytes += req.Size
+	s.methods[req.Method]++
+	s.paths[path]++
+	if req.ResponseStatus >= 400 {
+		s.statuses[req.ResponseStatus]++
+	}
+	s.arrivals = append(s.arrivals, at)
+}
+
+// Rate returns the requests per second over the last RateWindow, or since
+// start when that is shorter.
+func (s *Stats) Rate(now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rate(now)
+}
+
+func (s *Stats) rate(now time.Time) float64 {
+	cutoff := now.Add(-RateWindow)
+	i := sort.Search(len(s.arrivals), func(i int) bool { return s.arrivals[i].After(cutoff) })
+	s.arrivals = s.arrivals[i:]
+
+	span := min(now.Sub(s.start), RateWindow)
+	if span < time.Second {
+		span = time.Second
+	}
+	return float64(len(s.arrivals)) / span.Seconds()
+}
+
+// Render returns the stats as a multi-line table for the terminal.
+func (s *Stats) Render(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Requests:  %d total · %.1f/s (last %s) · %s\n",
+		s.total, s.rate(now), RateWindow, FormatBytes(s.bytes))
+	if s.total == 0 {
+		b.WriteString("\nWaiting for requests...\n")
+		return b.String()
+	}
+
+	var methods []string
+	for _, m := range sortedByCount(s.methods) {
+		methods = append(methods, fmt.Sprintf("%s %d (%d%%)", colorMethod(m.key), m.count, m.count*100/s.total))
+	}
+	fmt.Fprintf(&b, "Methods:   %s\n", strings.Join(methods, "  "))
+
+	b.WriteString("\nTop paths:\n")
+	paths := sortedByCount(s.paths)
+	for _, p := range paths[:min(len(paths), statsTopPaths)] {
+		fmt.Fprintf(&b, "  %6d  %s\n", p.count, p.key)
+	}
+	if len(paths) > statsTopPaths {
+		other := 0
+		for _, p := range paths[statsTopPaths:] {
+			other += p.count
+		}
+		fmt.Fprintf(&b, "  %6d  (%d other paths)\n", other, len(paths)-statsTopPaths)
+	}
+
+	b.WriteString("\nError statuses:\n")
+	if len(s.statuses) == 0 {
+		b.WriteString("  none\n")
+	}
+	codes := make([]int, 0, len(s.statuses))
+	for code := range s.statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Fprintf(&b, "  %6d  %d\n", s.statuses[code], code)
+	}
+	return b.String()
+}
+
+type keyCount struct {
+	key   string
+	count int
+}
+
+// sortedByCount returns the map's entries, most frequent first and then
+// by key.
+func sortedByCount(m map[string]int) []keyCount {
+	entries := make([]keyCount, 0, len(m))
+	for k, n := range m {
+		entries = append(entries, keyCount{k, n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].key < entries[j].key
+	})
+	return entries
+}
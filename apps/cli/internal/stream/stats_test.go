@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"webhooks.cc/shared/types"
+)
+
+func TestStats_Rate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	s := NewStats(start)
+	for i := range 10 {
+		s.Add(&types.CapturedRequest{Method: "POST"}, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	// Under a second in, the rate is per second rather than extrapolated
+	if got := s.Rate(start.Add(time.Second)); got != 10 {
+		t.Errorf("Rate after 1s = %v, want 10", got)
+	}
+	if got := s.Rate(start.Add(5 * time.Second)); got != 2 {
+		t.Errorf("Rate after 5s = %v, want 2", got)
+	}
+	// Once the requests fall out of the window the rate drops to zero
+	if got := s.Rate(start.Add(RateWindow + time.Second)); got != 0 {
+		t.Errorf("Rate after the window = %v, want 0", got)
+	}
+}
+
+func TestStats_Render(t *testing.T) {
+	start := time.Unix(1000, 0)
+	s := NewStats(start)
+	if got := s.Render(start); !strings.Contains(got, "Waiting for requests") {
+		t.Errorf("empty stats should say they're waiting, got:\n%s", got)
+	}
+
+	add := func(method, path string, status, n int) {
+		for range n {
+			s.Add(&types.CapturedRequest{Method: method, Path: path, ResponseStatus: status, Size: 100}, start)
+		}
+	}
+	add("POST", "/hook?attempt=1", 200, 3)
+	add("POST", "/hook", 500, 2)
+	add("GET", "/health", 404, 1)
+	for i := range 6 {
+		add("PUT", fmt.Sprintf("/items/%d", i), 0, 1)
+	}
+
+	out := s.Render(start.Add(2 * time.Second))
+	for _, want := range []string{
+		"12 total",
+		"6.0/s",
+		"1.2kb",
+		"PUT\033[0m 6 (50%)",
+		"POST\033[0m 5 (41%)",
+		"     5  /hook\n",
+		"     1  /health\n",
+		"     3  (3 other paths)\n",
+		"1  404\n       2  500\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "?attempt") {
+		t.Error("paths should be grouped without their query string")
+	}
+}
@@ -14,9 +14,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
+	"webhooks.cc/cli/internal/auth"
 	"webhooks.cc/shared/types"
 )
 
@@ -25,13 +28,45 @@ type StatusError struct {
 	Code int
 }
 
+// Error describes the failure along with what the user can do about it,
+// since listen, tunnel and the TUI show it as is.
 func (e *StatusError) Error() string {
+	switch e.Code {
+	case http.StatusUnauthorized:
+		return "token expired or revoked (401): run `whk auth login`"
+	case http.StatusForbidden:
+		return "access denied (403): your account doesn't have access to this endpoint"
+	case http.StatusNotFound:
+		return "endpoint not found (404): it may have been deleted or expired, run `whk list` to see your endpoints"
+	}
 	return fmt.Sprintf("unexpected status: %d", e.Code)
 }
 
+// Unwrap reports a 401 as auth.ErrSessionExpired, since the stream is
+// authenticated with the stored token.
+func (e *StatusError) Unwrap() error {
+	if e.Code == http.StatusUnauthorized {
+		return auth.ErrSessionExpired
+	}
+	return nil
+}
+
 // ErrEndpointDeleted is returned when the server signals the endpoint was deleted.
 var ErrEndpointDeleted = errors.New("endpoint was deleted")
 
+// HandlerPanic is what Listen and ListenEvents panic with when a handler
+// panics. Handlers run on the stream's reader goroutine, so the panic is
+// re-raised on the caller's goroutine where it can be recovered; Stack is
+// where the handler panicked.
+type HandlerPanic struct {
+	Value any
+	Stack []byte
+}
+
+func (p *HandlerPanic) String() string {
+	return fmt.Sprint(p.Value)
+}
+
 const (
 	scannerInitBufSize = 64 * 1024    // 64KB initial scanner buffer
 	scannerMaxBufSize  = 1024 * 1024  // 1MB max line size for large webhook bodies
@@ -65,6 +100,13 @@ type Stream struct {
 // be stored or called from other goroutines.
 type RequestHandler func(req *types.CapturedRequest)
 
+// EventHandler processes platform events as they arrive, with the same
+// threading rules as RequestHandler.
+type EventHandler func(event *types.PlatformEvent)
+
+// messageHandler receives each complete SSE message (event name and data).
+type messageHandler func(event, data string)
+
 // New creates a Stream that listens for webhooks on the given endpoint.
 // The token authenticates with the webhooks.cc API.
 // The transport is reused across reconnections for connection pooling.
@@ -93,10 +135,22 @@ func New(endpointSlug, baseURL, token string) *Stream {
 // It automatically reconnects with exponential backoff on connection loss.
 // It respects the provided context for cancellation and graceful shutdown.
 func (s *Stream) Listen(ctx context.Context, handler RequestHandler) error {
+	return s.listen(ctx, "/api/stream/", requestMessages(handler))
+}
+
+// ListenEvents connects to the endpoint's platform event stream (quota
+// denials, mock responses, forward attempts, size warnings) and calls
+// handler for each event. Reconnection and cancellation work as in Listen.
+func (s *Stream) ListenEvents(ctx context.Context, handler EventHandler) error {
+	return s.listen(ctx, "/api/events/", eventMessages(handler))
+}
+
+// listen runs the reconnect loop for the SSE stream at pathPrefix+slug.
+func (s *Stream) listen(ctx context.Context, pathPrefix string, dispatch messageHandler) error {
 	backoff := initialBackoff
 	for {
 		connectStart := time.Now()
-		err := s.connect(ctx, handler)
+		err := s.connectTo(ctx, pathPrefix, dispatch)
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
@@ -131,11 +185,46 @@ func (s *Stream) Listen(ctx context.Context, handler RequestHandler) error {
 	}
 }
 
-// connect performs a single SSE connection attempt and processes messages
-// until the connection is lost or context is cancelled.
+// requestMessages decodes "request" messages and passes them to handler.
+func requestMessages(handler RequestHandler) messageHandler {
+	return func(event, data string) {
+		if event != "request" {
+			return
+		}
+		capturedReq, err := types.DecodeCapturedRequest([]byte(data))
+		if err != nil {
+			debugLog("SSE parse error: %v (data: %s)", err, truncateData(data))
+			return
+		}
+		handler(capturedReq)
+	}
+}
+
+// eventMessages decodes "event" messages and passes them to handler.
+func eventMessages(handler EventHandler) messageHandler {
+	return func(event, data string) {
+		if event != "event" {
+			return
+		}
+		var e types.PlatformEvent
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			debugLog("SSE parse error: %v (data: %s)", err, truncateData(data))
+			return
+		}
+		handler(&e)
+	}
+}
+
+// connect performs a single connection attempt to the request stream.
 func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
+	return s.connectTo(ctx, "/api/stream/", requestMessages(handler))
+}
+
+// connectTo performs a single SSE connection attempt and processes messages
+// until the connection is lost or context is cancelled.
+func (s *Stream) connectTo(ctx context.Context, pathPrefix string, dispatch messageHandler) error {
 	escapedSlug := url.PathEscape(s.endpointSlug)
-	streamURL := fmt.Sprintf("%s/api/stream/%s", s.baseURL, escapedSlug)
+	streamURL := fmt.Sprintf("%s%s%s", s.baseURL, pathPrefix, escapedSlug)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", streamURL, nil)
 	if err != nil {
@@ -145,6 +234,7 @@ func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
 	req.Header.Set("Authorization", "Bearer "+s.token)
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set(types.APIVersionHeader, types.APIVersion)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -159,9 +249,15 @@ func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
 	// Channel to signal scanner goroutine completion
 	done := make(chan struct{})
 	errChan := make(chan error, 1)
+	var panicked *HandlerPanic
 
 	go func() {
 		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = &HandlerPanic{Value: r, Stack: debug.Stack()}
+			}
+		}()
 		scanner := bufio.NewScanner(resp.Body)
 		buf := make([]byte, scannerInitBufSize)
 		scanner.Buffer(buf, scannerMaxBufSize)
@@ -184,18 +280,8 @@ func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
 					errChan <- ErrEndpointDeleted
 					return
 				}
-				if currentEvent == "request" && len(dataLines) > 0 {
-					data := strings.Join(dataLines, "\n")
-					var capturedReq types.CapturedRequest
-					if err := json.Unmarshal([]byte(data), &capturedReq); err != nil {
-						truncated := data
-						if len(truncated) > maxDebugDataLen {
-							truncated = truncated[:maxDebugDataLen] + "..."
-						}
-						debugLog("SSE parse error: %v (data: %s)", err, truncated)
-					} else {
-						handler(&capturedReq)
-					}
+				if currentEvent != "" && len(dataLines) > 0 {
+					dispatch(currentEvent, strings.Join(dataLines, "\n"))
 				}
 				currentEvent = ""
 				dataLines = nil
@@ -232,8 +318,14 @@ func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
 	case <-ctx.Done():
 		_ = resp.Body.Close() // Unblock scanner.Scan() in case context cancellation doesn't interrupt the read
 		<-done
+		if panicked != nil {
+			panic(panicked)
+		}
 		return ctx.Err()
 	case <-done:
+		if panicked != nil {
+			panic(panicked)
+		}
 		select {
 		case err := <-errChan:
 			return err
@@ -243,6 +335,14 @@ func (s *Stream) connect(ctx context.Context, handler RequestHandler) error {
 	}
 }
 
+// truncateData shortens SSE data for debug logs to avoid leaking payloads.
+func truncateData(data string) string {
+	if len(data) > maxDebugDataLen {
+		return data[:maxDebugDataLen] + "..."
+	}
+	return data
+}
+
 // FormatRequest returns a formatted string for terminal output
 func FormatRequest(req *types.CapturedRequest) string {
 	t := time.UnixMilli(req.ReceivedAt).Format("15:04:05")
@@ -263,6 +363,41 @@ var methodColors = map[string]string{
 	"PATCH":  "\033[35m", // Magenta
 }
 
+// FormatEvent returns a formatted platform event for terminal output
+func FormatEvent(e *types.PlatformEvent) string {
+	t := time.UnixMilli(e.Timestamp).Format("15:04:05")
+	line := fmt.Sprintf("%s  %s  %s", t, colorEventType(e.Type), e.Message)
+	if e.RequestID != "" {
+		line += "  (request " + e.RequestID + ")"
+	}
+	keys := make([]string, 0, len(e.Details))
+	for k := range e.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		line += fmt.Sprintf("  %s=%s", k, e.Details[k])
+	}
+	return line
+}
+
+var eventTypeColors = map[string]string{
+	types.EventQuotaDenied:    "\033[31m", // Red
+	types.EventMockServed:     "\033[32m", // Green
+	types.EventForwardAttempt: "\033[34m", // Blue
+	types.EventSizeWarning:    "\033[33m", // Yellow
+}
+
+// colorEventType returns the padded event type with ANSI color codes.
+func colorEventType(eventType string) string {
+	padded := fmt.Sprintf("%-16s", eventType)
+	color, ok := eventTypeColors[eventType]
+	if !ok {
+		return padded
+	}
+	return color + padded + "\033[0m"
+}
+
 // colorMethod returns the method string with ANSI color codes for terminal display.
 func colorMethod(method string) string {
 	color, ok := methodColors[method]
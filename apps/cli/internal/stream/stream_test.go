@@ -2,6 +2,7 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"webhooks.cc/cli/internal/auth"
 	"webhooks.cc/shared/types"
 )
 
@@ -86,6 +88,22 @@ data: "size":0,"receivedAt":1700000000000}
 	}
 }
 
+func TestStream_V2Payload(t *testing.T) {
+	received := runSSETest(t, `event: request
+data: {"version":2,"_id":"req-v2","method":"POST","path":"/v2","headers":[{"name":"Set-Cookie","value":"a=1"},{"name":"Set-Cookie","value":"b=2"}],"body":"AP8=","bodyEncoding":"base64","queryParams":{},"ip":"1.2.3.4","size":2,"receivedAt":1700000000000}
+
+`)
+	if len(received) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(received))
+	}
+	if got := received[0].HeaderValues("set-cookie"); len(got) != 2 {
+		t.Errorf("expected 2 Set-Cookie values, got %v", got)
+	}
+	if received[0].BodyEncoding != "base64" {
+		t.Errorf("expected base64 body encoding, got %q", received[0].BodyEncoding)
+	}
+}
+
 func TestStream_KeepaliveSkipped(t *testing.T) {
 	received := runSSETest(t, `:ping
 :keepalive
@@ -138,6 +156,31 @@ data: {"_id":"valid","method":"PATCH","path":"/ok","headers":{},"queryParams":{}
 // Non-retryable status codes
 // ---------------------------------------------------------------------------
 
+func TestStream_HandlerPanicReraised(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("event: request\ndata: {\"_id\":\"req-1\"}\n\n"))
+	}))
+	defer server.Close()
+
+	s := New("test-slug", server.URL, "token")
+	s.client = server.Client()
+
+	defer func() {
+		p, ok := recover().(*HandlerPanic)
+		if !ok {
+			t.Fatal("expected connect to re-panic with a *HandlerPanic")
+		}
+		if p.Value != "boom" || len(p.Stack) == 0 {
+			t.Errorf("unexpected panic: %v", p)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_ = s.connect(ctx, func(req *types.CapturedRequest) { panic("boom") })
+}
+
 func TestStream_NonRetryableStatusCodes(t *testing.T) {
 	codes := []int{401, 403, 404}
 	for _, code := range codes {
@@ -171,9 +214,29 @@ func TestStream_NonRetryableStatusCodes(t *testing.T) {
 }
 
 func TestStatusError_Error(t *testing.T) {
-	e := &StatusError{Code: 401}
-	if !strings.Contains(e.Error(), "401") {
-		t.Errorf("expected 401 in error message, got %q", e.Error())
+	tests := []struct {
+		code int
+		want string
+	}{
+		{401, "whk auth login"},
+		{403, "access"},
+		{404, "may have been deleted"},
+		{500, "unexpected status"},
+	}
+	for _, tt := range tests {
+		msg := (&StatusError{Code: tt.code}).Error()
+		if !strings.Contains(msg, fmt.Sprint(tt.code)) || !strings.Contains(msg, tt.want) {
+			t.Errorf("StatusError{%d}: got %q, want the code and %q", tt.code, msg, tt.want)
+		}
+	}
+}
+
+func TestStatusError_UnauthorizedIsSessionExpired(t *testing.T) {
+	if !errors.Is(&StatusError{Code: 401}, auth.ErrSessionExpired) {
+		t.Error("expected 401 to match auth.ErrSessionExpired")
+	}
+	if errors.Is(&StatusError{Code: 403}, auth.ErrSessionExpired) {
+		t.Error("expected 403 not to match auth.ErrSessionExpired")
 	}
 }
 
@@ -201,6 +264,23 @@ func TestFormatRequest(t *testing.T) {
 	}
 }
 
+func TestFormatEvent(t *testing.T) {
+	e := &types.PlatformEvent{
+		Type:      types.EventMockServed,
+		RequestID: "req-1",
+		Message:   "Mock response served",
+		Details:   map[string]string{"status": "202", "path": "/stripe"},
+		Timestamp: 1700000000000,
+	}
+
+	s := FormatEvent(e)
+	for _, want := range []string{"mock_served", "Mock response served", "req-1", "path=/stripe  status=202"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected %q in output, got %q", want, s)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FormatBytes
 // ---------------------------------------------------------------------------
@@ -294,3 +374,48 @@ func TestListen_NonRetryable_Returns(t *testing.T) {
 		t.Errorf("expected 401, got %d", statusErr.Code)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Platform event stream
+// ---------------------------------------------------------------------------
+
+func TestListenEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/events/test-slug" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`event: request
+data: {"_id":"req-1","method":"POST","path":"/","headers":{},"queryParams":{},"ip":"","size":0,"receivedAt":1}
+
+event: event
+data: {"id":"evt-1","type":"mock_served","requestId":"req-1","message":"Mock response served","details":{"status":"202"},"timestamp":1700000000000}
+
+event: event
+data: {not json
+
+`))
+	}))
+	t.Cleanup(server.Close)
+
+	s := New("test-slug", server.URL, "token")
+	s.client = server.Client()
+	s.baseURL = server.URL
+
+	var events []*types.PlatformEvent
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_ = s.connectTo(ctx, "/api/events/", eventMessages(func(e *types.PlatformEvent) {
+		events = append(events, e)
+	}))
+
+	// The request message and the malformed event are skipped
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.Type != types.EventMockServed || e.RequestID != "req-1" || e.Details["status"] != "202" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
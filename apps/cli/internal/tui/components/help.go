@@ -0,0 +1,50 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	helpBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#FF6B35")).
+			Padding(1, 3)
+
+	helpKeyStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF6B35"))
+
+	helpDescStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#D1D5DB"))
+
+	helpHintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280"))
+)
+
+// HelpOverlay renders the key bindings of a screen in a box centered in a
+// width x height area. Disabled bindings and ones without help are left out.
+func HelpOverlay(title string, bindings []key.Binding, width, height int) string {
+	keyWidth := 0
+	for _, b := range bindings {
+		if b.Enabled() && b.Help().Key != "" {
+			keyWidth = max(keyWidth, lipgloss.Width(b.Help().Key))
+		}
+	}
+
+	lines := []string{titleStyle.Render(title + " keys"), ""}
+	for _, b := range bindings {
+		h := b.Help()
+		if !b.Enabled() || h.Key == "" {
+			continue
+		}
+		pad := strings.Repeat(" ", keyWidth-lipgloss.Width(h.Key))
+		lines = append(lines, helpKeyStyle.Render(h.Key)+pad+"   "+helpDescStyle.Render(h.Desc))
+	}
+	lines = append(lines, "", helpHintStyle.Render("press any key to close"))
+
+	box := helpBoxStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
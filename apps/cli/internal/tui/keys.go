@@ -3,16 +3,27 @@ package tui
 import "github.com/charmbracelet/bubbles/key"
 
 type KeyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Enter  key.Binding
-	Back   key.Binding
-	Quit   key.Binding
-	Tab    key.Binding
-	Copy   key.Binding
-	Delete key.Binding
-	New    key.Binding
-	Help   key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Enter     key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	Tab       key.Binding
+	Copy      key.Binding
+	Delete    key.Binding
+	New       key.Binding
+	Help      key.Binding
+	Refresh   key.Binding
+	Pin       key.Binding
+	Filter    key.Binding
+	Search    key.Binding
+	QR        key.Binding
+	Login     key.Binding
+	Logout    key.Binding
+	Update    key.Binding
+	Intercept key.Binding
+	Forward   key.Binding
+	Edit      key.Binding
 }
 
 var Keys = KeyMap{
@@ -56,4 +67,63 @@ var Keys = KeyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Pin: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pin"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	QR: key.NewBinding(
+		key.WithKeys("q"),
+		key.WithHelp("q", "qr code"),
+	),
+	Login: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "log in"),
+	),
+	Logout: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "log out"),
+	),
+	Update: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "update"),
+	),
+	Intercept: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "intercept"),
+	),
+	Forward: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "forward"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit"),
+	),
+}
+
+// HelpProvider is implemented by screens that list their key bindings in
+// the ? help overlay. HelpKeys returns the bindings for the screen's
+// current state, or nil while a text input has focus, so ? is typed
+// instead of opening the overlay.
+type HelpProvider interface {
+	HelpKeys() []key.Binding
+}
+
+// WithHelp returns b with its help text replaced by desc, for screens where
+// a shared binding does something specific.
+func WithHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
 }
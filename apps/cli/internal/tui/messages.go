@@ -21,8 +21,32 @@ const (
 	ScreenEndpoints
 	ScreenDetail
 	ScreenUpdate
+	ScreenRequests
 )
 
+// String returns the screen's title.
+func (s Screen) String() string {
+	switch s {
+	case ScreenMenu:
+		return "Menu"
+	case ScreenAuth:
+		return "Auth"
+	case ScreenTunnel:
+		return "Tunnel"
+	case ScreenListen:
+		return "Listen"
+	case ScreenEndpoints:
+		return "Endpoints"
+	case ScreenDetail:
+		return "Request Detail"
+	case ScreenUpdate:
+		return "Update"
+	case ScreenRequests:
+		return "Requests"
+	}
+	return ""
+}
+
 // Navigation messages
 type NavigateMsg struct {
 	Screen Screen
@@ -31,6 +55,10 @@ type NavigateMsg struct {
 
 type BackMsg struct{}
 
+// SessionExpiredMsg is sent when the API rejects the stored token. The app
+// responds by switching to the Auth screen.
+type SessionExpiredMsg struct{}
+
 // Window size (forwarded to active screen)
 type WindowSizeMsg = tea.WindowSizeMsg
 
@@ -59,7 +87,24 @@ type EndpointsLoadedMsg struct {
 
 type EndpointCreatedMsg struct {
 	Endpoint *Endpoint
-	Err      error
+	// Copied is set when the endpoint's URL was copied to the clipboard
+	Copied bool
+	Err    error
+}
+
+type RequestsLoadedMsg struct {
+	Requests []*types.CapturedRequest
+	// Cached counts requests that came from the local request cache;
+	// Offline is set when the server couldn't be reached at all.
+	Cached  int
+	Offline bool
+	Err     error
+}
+
+type RequestPinnedMsg struct {
+	RequestID string
+	Pinned    bool
+	Err       error
 }
 
 type EndpointDeletedMsg struct {
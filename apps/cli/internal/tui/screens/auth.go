@@ -7,6 +7,7 @@ import (
 
 	"webhooks.cc/cli/internal/api"
 	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/httpcache"
 	"webhooks.cc/cli/internal/tui"
 	"webhooks.cc/cli/internal/tui/components"
 
@@ -52,13 +53,17 @@ func NewAuth(client *api.Client) AuthModel {
 		}
 	}
 
-	return AuthModel{
+	m := AuthModel{
 		client:   client,
 		loggedIn: loggedIn,
 		email:    email,
 		state:    authIdle,
 		spinner:  s,
 	}
+	if !loggedIn && auth.SessionExpired() {
+		m.err = auth.ErrSessionExpired
+	}
+	return m
 }
 
 func (m AuthModel) Init() tea.Cmd {
@@ -77,11 +82,11 @@ func (m AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case key.Matches(msg, tui.Keys.Back):
 			return m, func() tea.Msg { return tui.BackMsg{} }
-		case msg.String() == "l" && !m.loggedIn && m.state == authIdle:
+		case key.Matches(msg, tui.Keys.Login) && !m.loggedIn && m.state == authIdle:
 			m.state = authPolling
 			m.err = nil
 			return m, tea.Batch(m.spinner.Tick, m.startLogin())
-		case msg.String() == "o" && m.loggedIn:
+		case key.Matches(msg, tui.Keys.Logout) && m.loggedIn:
 			return m, m.doLogout()
 		}
 
@@ -199,11 +204,23 @@ func (m AuthModel) claimAuth() tea.Cmd {
 
 func (m AuthModel) doLogout() tea.Cmd {
 	return func() tea.Msg {
+		// Cached responses belong to the account logging out; failing to
+		// remove them only costs disk space
+		_ = httpcache.Clear()
 		err := auth.ClearToken()
 		return tui.AuthLogoutMsg{Err: err}
 	}
 }
 
+// HelpKeys lists the auth screen's key bindings for the help overlay.
+func (m AuthModel) HelpKeys() []key.Binding {
+	login := tui.Keys.Login
+	login.SetEnabled(!m.loggedIn && m.state == authIdle)
+	logout := tui.Keys.Logout
+	logout.SetEnabled(m.loggedIn)
+	return []key.Binding{login, logout, tui.Keys.Back}
+}
+
 func (m AuthModel) View() string {
 	header := components.Header("Auth", m.width)
 
@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -9,6 +10,7 @@ import (
 
 	"webhooks.cc/cli/internal/tui"
 	"webhooks.cc/cli/internal/tui/components"
+	"webhooks.cc/shared/bodyfmt"
 	"webhooks.cc/shared/types"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -103,6 +105,18 @@ func (m DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// HelpKeys lists the detail screen's key bindings for the help overlay.
+func (m DetailModel) HelpKeys() []key.Binding {
+	return []key.Binding{
+		tui.WithHelp(tui.Keys.Tab, "next tab"),
+		key.NewBinding(key.WithKeys("1", "2", "3"), key.WithHelp("1/2/3", "overview, headers, body")),
+		tui.WithHelp(tui.Keys.Up, "scroll up"),
+		tui.WithHelp(tui.Keys.Down, "scroll down"),
+		key.NewBinding(key.WithKeys("pgup", "pgdown"), key.WithHelp("pgup/pgdn", "page up/down")),
+		tui.Keys.Back,
+	}
+}
+
 func (m DetailModel) tabContent() string {
 	switch m.tab {
 	case tabOverview:
@@ -129,6 +143,23 @@ func (m DetailModel) overviewContent() string {
 		fmt.Sprintf("  Received:     %s", ts),
 		fmt.Sprintf("  Content-Type: %s", req.ContentType),
 	}
+	if req.Pinned {
+		lines = append(lines, fmt.Sprintf("  Pinned:       %s", tui.Accent.Render("★ yes")))
+	}
+	if req.Note != "" {
+		lines = append(lines, fmt.Sprintf("  Note:         %s", tui.Bold.Render(req.Note)))
+	}
+	if len(req.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("  Tags:         %s", tui.Accent.Render(strings.Join(req.Tags, ", "))))
+	}
+
+	if len(req.PathParams) > 0 {
+		lines = append(lines, "", "  Path Parameters:")
+		for _, k := range sortedKeys(req.PathParams) {
+			lines = append(lines, fmt.Sprintf("    %s = %s",
+				tui.Bold.Render(k), req.PathParams[k]))
+		}
+	}
 
 	if len(req.QueryParams) > 0 {
 		lines = append(lines, "", "  Query Parameters:")
@@ -143,37 +174,114 @@ func (m DetailModel) overviewContent() string {
 }
 
 func (m DetailModel) headersContent() string {
-	if len(m.request.Headers) == 0 {
+	fields := m.request.Fields()
+	if len(fields) == 0 {
 		return "  No headers"
 	}
 
-	keys := sortedKeys(m.request.Headers)
 	var lines []string
-	for _, k := range keys {
+	for _, f := range fields {
 		lines = append(lines, fmt.Sprintf("  %s: %s",
-			tui.Bold.Render(k), m.request.Headers[k]))
+			tui.Bold.Render(f.Name), f.Value))
 	}
 	return strings.Join(lines, "\n")
 }
 
 func (m DetailModel) bodyContent() string {
-	body := m.request.Body
-	if body == "" {
+	req := m.request
+	if req.Body == "" {
 		return "  (empty body)"
 	}
 
+	raw, err := req.BodyBytes()
+	if err != nil {
+		return "  (invalid base64 body)"
+	}
+
+	contentType := req.ContentType
+	if contentType == "" {
+		if values := req.HeaderValues("Content-Type"); len(values) > 0 {
+			contentType = values[0]
+		}
+	}
+	if bodyfmt.IsGRPCWeb(contentType) {
+		if msg, err := bodyfmt.ParseGRPCWeb(contentType, req.Path, raw); err == nil {
+			return grpcWebContent(msg)
+		}
+	}
+	if op, ok := bodyfmt.ParseGraphQL(contentType, raw); ok {
+		return graphQLContent(op)
+	}
+
+	if req.BodyEncoding == types.BodyEncodingBase64 {
+		return fmt.Sprintf("  (binary body, %d bytes, base64)\n\n  %s", len(raw), req.Body)
+	}
+
+	if fields, ok := bodyfmt.ParseForm(contentType, raw); ok && len(fields) > 0 {
+		lines := []string{"  Form fields:"}
+		for _, f := range fields {
+			lines = append(lines, fmt.Sprintf("    %s = %s", tui.Bold.Render(f.Name), f.Value))
+		}
+		return strings.Join(lines, "\n")
+	}
+	if bodyfmt.IsXML(contentType, raw) {
+		if pretty, err := bodyfmt.FormatXML(raw); err == nil {
+			return indentLines(pretty)
+		}
+	}
+
 	// Try to pretty-print JSON
 	var parsed any
-	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+	if err := json.Unmarshal(raw, &parsed); err == nil {
 		pretty, err := json.MarshalIndent(parsed, "  ", "  ")
 		if err == nil {
 			return "  " + string(pretty)
 		}
 	}
 
-	// Show raw body with indentation
+	return indentLines(req.Body)
+}
+
+func graphQLContent(op *bodyfmt.GraphQLOperation) string {
+	name := op.OperationName
+	if name == "" {
+		name = tui.Muted.Render("(anonymous)")
+	}
+	lines := []string{
+		fmt.Sprintf("  GraphQL %s %s", op.OperationType, tui.Bold.Render(name)),
+		"",
+		indentLines(strings.TrimSpace(op.Query)),
+	}
+	if len(op.Variables) > 0 {
+		lines = append(lines, "", "  Variables:")
+		if pretty, err := json.MarshalIndent(op.Variables, "  ", "  "); err == nil {
+			lines = append(lines, "  "+string(pretty))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func grpcWebContent(msg *bodyfmt.GRPCWebMessage) string {
+	lines := []string{fmt.Sprintf("  gRPC-web %s/%s", msg.Service, tui.Bold.Render(msg.Method))}
+	for i, f := range msg.Frames {
+		lines = append(lines, "")
+		if f.Trailer {
+			lines = append(lines, fmt.Sprintf("  Trailers (%d bytes):", len(f.Data)), indentLines(strings.TrimSpace(string(f.Data))))
+			continue
+		}
+		label := fmt.Sprintf("  Message %d (%d bytes", i+1, len(f.Data))
+		if f.Compressed {
+			label += ", compressed"
+		}
+		lines = append(lines, label+"):", indentLines(strings.TrimRight(hex.Dump(f.Data), "\n")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines indents every line of s by two spaces.
+func indentLines(s string) string {
 	var lines []string
-	for _, line := range strings.Split(body, "\n") {
+	for _, line := range strings.Split(s, "\n") {
 		lines = append(lines, "  "+line)
 	}
 	return strings.Join(lines, "\n")
@@ -2,12 +2,18 @@ package screens
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/qrcode"
 	"webhooks.cc/cli/internal/tui"
 	"webhooks.cc/cli/internal/tui/components"
+	"webhooks.cc/shared/types"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -20,8 +26,44 @@ type endpointsState int
 const (
 	epList endpointsState = iota
 	epCreating
+	epQR
 )
 
+// createField is the focused row of the create form.
+type createField int
+
+const (
+	fieldName createField = iota
+	fieldTemplate
+	fieldBody // only shown for the custom JSON template
+	fieldExpiry
+	fieldCopy
+)
+
+// Mock response choices in the create form besides the provider templates
+const (
+	templateBlank  = ""
+	templateCustom = "custom"
+)
+
+// createTemplates are the create form's mock response choices: the
+// server's default response, the `whk create --template` presets, and a
+// custom JSON body.
+var createTemplates = append(append([]string{templateBlank}, api.TemplateNames()...), templateCustom)
+
+// createExpiries are the create form's expiry choices. An endpoint with an
+// expiry is deleted by the server once it passes.
+var createExpiries = []struct {
+	label string
+	ttl   time.Duration
+}{
+	{"never", 0},
+	{"1 hour", time.Hour},
+	{"1 day", 24 * time.Hour},
+	{"7 days", 7 * 24 * time.Hour},
+	{"30 days", 30 * 24 * time.Hour},
+}
+
 type EndpointsModel struct {
 	client    *api.Client
 	width     int
@@ -34,6 +76,18 @@ type EndpointsModel struct {
 	message   string
 	state     endpointsState
 	nameInput textinput.Model
+	// The create form: field is the focused row, template and expiry index
+	// createTemplates and createExpiries, and bodyInput holds the custom
+	// JSON mock response
+	field     createField
+	template  int
+	expiry    int
+	bodyInput textinput.Model
+	// copyURL copies a created endpoint's URL to the clipboard
+	copyURL bool
+	// qr is the rendered QR code for qrURL, shown in the epQR state
+	qr    string
+	qrURL string
 }
 
 func NewEndpoints(client *api.Client, mode string) EndpointsModel {
@@ -45,6 +99,10 @@ func NewEndpoints(client *api.Client, mode string) EndpointsModel {
 	ti.Placeholder = "endpoint name (optional)"
 	ti.CharLimit = 64
 
+	bi := textinput.New()
+	bi.Placeholder = `{"ok":true}`
+	bi.CharLimit = 4096
+
 	state := epList
 	if mode == "create" {
 		state = epCreating
@@ -57,6 +115,8 @@ func NewEndpoints(client *api.Client, mode string) EndpointsModel {
 		spinner:   s,
 		state:     state,
 		nameInput: ti,
+		bodyInput: bi,
+		copyURL:   true,
 	}
 }
 
@@ -77,6 +137,15 @@ func (m EndpointsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == epCreating {
 			return m.updateCreating(msg)
 		}
+		if m.state == epQR {
+			switch {
+			case key.Matches(msg, tui.Keys.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, tui.Keys.Back), key.Matches(msg, tui.Keys.Enter), key.Matches(msg, tui.Keys.QR):
+				m.state = epList
+			}
+			return m, nil
+		}
 		switch {
 		case key.Matches(msg, tui.Keys.Quit):
 			return m, tea.Quit
@@ -92,9 +161,37 @@ func (m EndpointsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case key.Matches(msg, tui.Keys.New):
 			m.state = epCreating
+			m.field = fieldName
+			m.template = 0
+			m.expiry = 0
+			m.err = nil
 			m.nameInput.Reset()
+			m.bodyInput.Reset()
+			m.bodyInput.Blur()
 			m.nameInput.Focus()
 			return m, m.nameInput.Cursor.BlinkCmd()
+		case key.Matches(msg, tui.Keys.Copy):
+			if len(m.endpoints) > 0 {
+				url := m.endpoints[m.cursor].URL
+				if err := clipboard.WriteAll(url); err != nil {
+					m.err = fmt.Errorf("copy to clipboard: %w", err)
+					return m, nil
+				}
+				m.err = nil
+				m.message = "Copied " + url
+			}
+		case key.Matches(msg, tui.Keys.QR):
+			if len(m.endpoints) > 0 {
+				ep := m.endpoints[m.cursor]
+				code, err := qrcode.Encode(ep.URL)
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.qr = code.Render()
+				m.qrURL = ep.URL
+				m.state = epQR
+			}
 		case key.Matches(msg, tui.Keys.Delete):
 			if len(m.endpoints) > 0 {
 				m.loading = true
@@ -128,6 +225,9 @@ func (m EndpointsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.message = fmt.Sprintf("Created endpoint: %s", msg.Endpoint.Slug)
+		if msg.Copied {
+			m.message += " · URL copied to clipboard"
+		}
 		return m, m.loadEndpoints()
 
 	case tui.EndpointDeletedMsg:
@@ -148,39 +248,157 @@ func (m EndpointsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// HelpKeys lists the endpoints screen's key bindings for the help
+// overlay. The create form has none, since ? is typed into the name.
+func (m EndpointsModel) HelpKeys() []key.Binding {
+	switch m.state {
+	case epCreating:
+		return nil
+	case epQR:
+		return []key.Binding{tui.WithHelp(tui.Keys.QR, "close the qr code"), tui.WithHelp(tui.Keys.Back, "close the qr code")}
+	}
+	return []key.Binding{
+		tui.Keys.Up,
+		tui.Keys.Down,
+		tui.WithHelp(tui.Keys.New, "new endpoint"),
+		tui.WithHelp(tui.Keys.Delete, "delete endpoint"),
+		tui.WithHelp(tui.Keys.Copy, "copy url"),
+		tui.WithHelp(tui.Keys.QR, "show url as a qr code"),
+		tui.WithHelp(tui.Keys.Enter, "listen"),
+		tui.Keys.Back,
+	}
+}
+
 func (m EndpointsModel) updateCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch {
-	case key.Matches(msg, tui.Keys.Back):
+	switch msg.String() {
+	case "esc":
 		m.state = epList
 		return m, nil
-	case key.Matches(msg, tui.Keys.Enter):
-		name := m.nameInput.Value()
+	case "enter":
+		params, err := m.createParams()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
 		m.loading = true
 		m.state = epList
-		return m, tea.Batch(m.spinner.Tick, m.createEndpoint(name))
-	default:
+		return m, tea.Batch(m.spinner.Tick, m.createEndpoint(params, m.copyURL))
+	case "tab", "down":
+		return m.focusField(m.nextField(1))
+	case "shift+tab", "up":
+		return m.focusField(m.nextField(-1))
+	}
+
+	switch m.field {
+	case fieldName, fieldBody:
 		var cmd tea.Cmd
-		m.nameInput, cmd = m.nameInput.Update(msg)
+		if m.field == fieldName {
+			m.nameInput, cmd = m.nameInput.Update(msg)
+		} else {
+			m.bodyInput, cmd = m.bodyInput.Update(msg)
+		}
 		return m, cmd
+	case fieldTemplate:
+		m.template = cycle(m.template, len(createTemplates), msg.String())
+	case fieldExpiry:
+		m.expiry = cycle(m.expiry, len(createExpiries), msg.String())
+	case fieldCopy:
+		if s := msg.String(); s == " " || s == "left" || s == "right" {
+			m.copyURL = !m.copyURL
+		}
+	}
+	return m, nil
+}
+
+// cycle steps a choice of n options left or right, wrapping around.
+func cycle(i, n int, key string) int {
+	switch key {
+	case "left", "h":
+		return (i + n - 1) % n
+	case "right", "l", " ":
+		return (i + 1) % n
+	}
+	return i
+}
+
+// nextField returns the create form row dir steps from the focused one,
+// skipping the body row unless the custom template is chosen.
+func (m EndpointsModel) nextField(dir int) createField {
+	n := int(fieldCopy) + 1
+	f := (int(m.field) + dir + n) % n
+	if createField(f) == fieldBody && createTemplates[m.template] != templateCustom {
+		f = (f + dir + n) % n
+	}
+	return createField(f)
+}
+
+func (m EndpointsModel) focusField(f createField) (tea.Model, tea.Cmd) {
+	m.field = f
+	m.err = nil
+	m.nameInput.Blur()
+	m.bodyInput.Blur()
+	switch f {
+	case fieldName:
+		m.nameInput.Focus()
+		return m, m.nameInput.Cursor.BlinkCmd()
+	case fieldBody:
+		m.bodyInput.Focus()
+		return m, m.bodyInput.Cursor.BlinkCmd()
+	}
+	return m, nil
+}
+
+// createParams builds the create request from the form.
+func (m EndpointsModel) createParams() (api.CreateEndpointParams, error) {
+	params := api.CreateEndpointParams{Name: strings.TrimSpace(m.nameInput.Value())}
+	switch name := createTemplates[m.template]; name {
+	case templateBlank:
+	case templateCustom:
+		body := strings.TrimSpace(m.bodyInput.Value())
+		if !json.Valid([]byte(body)) {
+			return params, fmt.Errorf("custom response body is not valid JSON")
+		}
+		params.MockResponse = &types.MockResponse{
+			Status:  200,
+			Body:    body,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		}
+	default:
+		// No secret: signatures are recorded but not verified
+		if err := api.ApplyTemplate(&params, name, ""); err != nil {
+			return params, err
+		}
 	}
+	if ttl := createExpiries[m.expiry].ttl; ttl > 0 {
+		params.ExpiresAt = time.Now().Add(ttl).UnixMilli()
+	}
+	return params, nil
 }
 
 func (m EndpointsModel) loadEndpoints() tea.Cmd {
 	return loadEndpointsCmd(m.client)
 }
 
-func (m EndpointsModel) createEndpoint(name string) tea.Cmd {
+func (m EndpointsModel) createEndpoint(params api.CreateEndpointParams, copyURL bool) tea.Cmd {
 	return func() tea.Msg {
-		ep, err := m.client.CreateEndpointWithContext(context.Background(), name, false)
+		ep, err := m.client.CreateEndpointWithParams(context.Background(), params)
 		if err != nil {
 			return tui.EndpointCreatedMsg{Err: err}
 		}
+		url := ep.URL
+		if url == "" {
+			url = m.client.WebhookURL() + "/w/" + ep.Slug
+		}
+		// The next step is pasting the URL into a provider's dashboard.
+		// A missing clipboard isn't worth an error: the URL is listed.
+		copied := copyURL && clipboard.WriteAll(url) == nil
 		return tui.EndpointCreatedMsg{Endpoint: &tui.Endpoint{
 			ID:   ep.ID,
 			Slug: ep.Slug,
 			Name: ep.Name,
-			URL:  ep.URL,
-		}}
+			URL:  url,
+		}, Copied: copied}
 	}
 }
 
@@ -198,10 +416,9 @@ func (m EndpointsModel) View() string {
 	var body string
 
 	if m.state == epCreating {
-		body = fmt.Sprintf("  Create new endpoint:\n\n  %s\n\n  %s",
-			m.nameInput.View(),
-			tui.Muted.Render("enter to create · esc to cancel"),
-		)
+		body = m.createView()
+	} else if m.state == epQR {
+		body = m.qrView()
 	} else if m.loading {
 		body = fmt.Sprintf("  %s Loading...", m.spinner.View())
 	} else if len(m.endpoints) == 0 {
@@ -242,7 +459,7 @@ func (m EndpointsModel) View() string {
 
 	content := lipgloss.JoinVertical(lipgloss.Left, header, "", body)
 
-	help := "n new · d delete · enter listen · esc back · ctrl+c quit"
+	help := "n new · d delete · c copy url · q qr code · enter listen · esc back · ctrl+c quit"
 	statusBar := components.StatusBar(help, m.width)
 
 	contentHeight := lipgloss.Height(content)
@@ -254,3 +471,69 @@ func (m EndpointsModel) View() string {
 
 	return content + fmt.Sprintf("%*s", gap, "\n") + statusBar
 }
+
+// createView renders the create form, marking the focused row.
+func (m EndpointsModel) createView() string {
+	row := func(f createField, label, value string) string {
+		cursor := "  "
+		label = fmt.Sprintf("%-10s", label)
+		if m.field == f {
+			cursor = tui.Primary.Render("▸ ")
+			label = tui.Bold.Render(label)
+		}
+		return fmt.Sprintf("%s%s %s\n", cursor, label, value)
+	}
+	choice := func(f createField, s string) string {
+		if m.field == f {
+			return "‹ " + s + " ›"
+		}
+		return s
+	}
+
+	template := "blank (default response)"
+	switch name := createTemplates[m.template]; name {
+	case templateBlank:
+	case templateCustom:
+		template = "custom JSON (200)"
+	default:
+		template = name + " · " + api.EndpointTemplates[name].Description
+	}
+	check := "[ ]"
+	if m.copyURL {
+		check = "[x]"
+	}
+
+	var b strings.Builder
+	b.WriteString("  Create new endpoint:\n\n")
+	b.WriteString(row(fieldName, "Name", m.nameInput.View()))
+	b.WriteString(row(fieldTemplate, "Response", choice(fieldTemplate, template)))
+	if createTemplates[m.template] == templateCustom {
+		b.WriteString(row(fieldBody, "Body", m.bodyInput.View()))
+	}
+	b.WriteString(row(fieldExpiry, "Expires", choice(fieldExpiry, createExpiries[m.expiry].label)))
+	b.WriteString(row(fieldCopy, "Copy URL", check))
+	b.WriteString("\n  " + tui.Muted.Render("enter to create · tab/↑↓ move · ←→ change · esc to cancel"))
+	return b.String()
+}
+
+// qrView shows the selected endpoint's URL as a QR code, or asks for a
+// bigger terminal if it doesn't fit: a cropped code won't scan.
+func (m EndpointsModel) qrView() string {
+	lines := strings.Split(m.qr, "\n")
+	width := lipgloss.Width(lines[0])
+	// Header, URL and hint lines around the code
+	if m.width > 0 && (width+2 > m.width || len(lines)+8 > m.height) {
+		return fmt.Sprintf("  %s\n\n  %s\n\n  %s",
+			m.qrURL,
+			tui.Danger.Render(fmt.Sprintf("Enlarge the terminal to %dx%d to show the QR code.", width+2, len(lines)+8)),
+			tui.Muted.Render("esc to close"),
+		)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "  Scan to open %s\n\n", tui.Bold.Render(m.qrURL))
+	for _, line := range lines {
+		b.WriteString("  " + tui.QRCode.Render(line) + "\n")
+	}
+	b.WriteString("\n  " + tui.Muted.Render("esc to close"))
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -17,6 +18,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// listenRecentCount is how many already-captured requests are shown when
+// the stream starts, so recent traffic is visible before anything new arrives.
+const listenRecentCount = 20
+
 type listenState int
 
 const (
@@ -62,7 +67,7 @@ func NewListen(client *api.Client, slug string) ListenModel {
 func (m ListenModel) Init() tea.Cmd {
 	cmds := []tea.Cmd{m.spinner.Tick}
 	if m.slug != "" {
-		cmds = append(cmds, m.startStream())
+		cmds = append(cmds, m.startStream(), m.loadRecent())
 	} else {
 		cmds = append(cmds, m.loadEndpoints())
 	}
@@ -107,7 +112,7 @@ func (m ListenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.slug = m.endpoints[m.cursor].Slug
 				m.state = listenStreaming
 				m.loading = true
-				return m, tea.Batch(m.spinner.Tick, m.startStream())
+				return m, tea.Batch(m.spinner.Tick, m.startStream(), m.loadRecent())
 			}
 			if m.state == listenStreaming && len(m.requests) > 0 && m.scrollPos < len(m.requests) {
 				req := m.requests[m.scrollPos]
@@ -134,6 +139,17 @@ func (m ListenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tui.WaitForSSE(m.sseSession)
 		}
 
+	case tui.RequestsLoadedMsg:
+		// Recent history is best-effort; the live stream still works without it
+		if msg.Err != nil || m.state != listenStreaming {
+			return m, nil
+		}
+		m.requests = mergeRecent(msg.Requests, m.requests)
+		if len(m.requests) > 0 {
+			m.loading = false
+			m.scrollPos = len(m.requests) - 1
+		}
+
 	case tui.SSEErrorMsg:
 		m.err = msg.Err
 
@@ -160,11 +176,44 @@ func (m ListenModel) loadEndpoints() tea.Cmd {
 	return loadEndpointsCmd(m.client)
 }
 
+// loadRecent fetches the endpoint's most recent captured requests.
+func (m ListenModel) loadRecent() tea.Cmd {
+	client, slug := m.client, m.slug
+	params := api.ListRequestsParams{Limit: listenRecentCount}
+	return func() tea.Msg {
+		reqs, err := client.ListRequests(context.Background(), slug, params)
+		if err != nil {
+			return tui.RequestsLoadedMsg{Err: err}
+		}
+		result := make([]*types.CapturedRequest, len(reqs))
+		for i := range reqs {
+			result[i] = &reqs[i]
+		}
+		return tui.RequestsLoadedMsg{Requests: result}
+	}
+}
+
+// mergeRecent puts recent history (newest first) ahead of requests already
+// received from the stream, oldest first, skipping any the stream delivered.
+func mergeRecent(recent, live []*types.CapturedRequest) []*types.CapturedRequest {
+	seen := make(map[string]bool, len(live))
+	for _, req := range live {
+		seen[req.ID] = true
+	}
+	merged := make([]*types.CapturedRequest, 0, len(recent)+len(live))
+	for i := len(recent) - 1; i >= 0; i-- {
+		if !seen[recent[i].ID] {
+			merged = append(merged, recent[i])
+		}
+	}
+	return append(merged, live...)
+}
+
 func (m *ListenModel) startStream() tea.Cmd {
 	tok, err := auth.LoadToken()
 	if err != nil {
 		return func() tea.Msg {
-			return tui.SSEErrorMsg{Err: fmt.Errorf("not logged in: %w", err)}
+			return tui.SSEErrorMsg{Err: fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)}
 		}
 	}
 
@@ -174,6 +223,19 @@ func (m *ListenModel) startStream() tea.Cmd {
 	return cmd
 }
 
+// HelpKeys lists the listen screen's key bindings for the help overlay.
+func (m ListenModel) HelpKeys() []key.Binding {
+	if m.state == listenPicker {
+		return []key.Binding{tui.Keys.Up, tui.Keys.Down, tui.WithHelp(tui.Keys.Enter, "listen"), tui.Keys.Back}
+	}
+	return []key.Binding{
+		tui.WithHelp(tui.Keys.Up, "scroll up"),
+		tui.WithHelp(tui.Keys.Down, "scroll down"),
+		tui.WithHelp(tui.Keys.Enter, "inspect request"),
+		tui.WithHelp(tui.Keys.Back, "back to endpoints"),
+	}
+}
+
 func (m ListenModel) View() string {
 	header := components.Header("Listen", m.width)
 
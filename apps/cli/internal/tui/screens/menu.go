@@ -42,6 +42,7 @@ func NewMenu(version string) MenuModel {
 		items: []menuItem{
 			{title: "Tunnel", desc: "Forward webhooks to localhost", screen: tui.ScreenTunnel},
 			{title: "Listen", desc: "Stream incoming requests", screen: tui.ScreenListen},
+			{title: "History", desc: "Browse captured requests", screen: tui.ScreenRequests},
 			{title: "Create", desc: "Create a new endpoint", screen: tui.ScreenEndpoints, data: "create"},
 			{title: "Endpoints", desc: "Manage your endpoints", screen: tui.ScreenEndpoints},
 			{title: "Auth", desc: "Login / logout", screen: tui.ScreenAuth},
@@ -88,6 +89,12 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// HelpKeys lists the menu's key bindings for the help overlay.
+func (m MenuModel) HelpKeys() []key.Binding {
+	quit := key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit"))
+	return []key.Binding{tui.Keys.Up, tui.Keys.Down, tui.Keys.Enter, quit}
+}
+
 func (m MenuModel) View() string {
 	header := components.Header("", m.width)
 
@@ -127,7 +134,7 @@ func (m MenuModel) View() string {
 		version,
 	)
 
-	help := "↑↓ navigate · enter select · q quit"
+	help := "↑↓ navigate · enter select · ? help · q quit"
 	statusBar := components.StatusBar(help, m.width)
 
 	// Fill remaining space
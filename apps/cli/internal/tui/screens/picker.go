@@ -0,0 +1,198 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"webhooks.cc/cli/internal/tui"
+	"webhooks.cc/cli/internal/tui/components"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerMaxRows caps how many matches are shown at once.
+const pickerMaxRows = 15
+
+// PickerItem is one choice in a picker.
+type PickerItem struct {
+	// Value is returned when the item is chosen.
+	Value string
+	// Label is shown and matched against the query.
+	Label string
+	// Detail is shown muted after the label and also matched.
+	Detail string
+}
+
+// PickerModel is a standalone fuzzy-searchable list used by commands that
+// need a choice from the user when an argument was omitted.
+type PickerModel struct {
+	title    string
+	items    []PickerItem
+	matches  []PickerItem
+	cursor   int
+	input    textinput.Model
+	width    int
+	height   int
+	selected *PickerItem
+	// done clears the view on exit so the picker doesn't stay on screen
+	done bool
+}
+
+func NewPicker(title string, items []PickerItem) PickerModel {
+	ti := textinput.New()
+	ti.Placeholder = "type to filter"
+	ti.Prompt = "/ "
+	ti.CharLimit = 64
+	ti.Focus()
+
+	m := PickerModel{title: title, items: items, input: ti}
+	m.matches = filterPickerItems(items, "")
+	return m
+}
+
+// Selected returns the chosen item, or false if the picker was cancelled.
+func (m PickerModel) Selected() (PickerItem, bool) {
+	if m.selected == nil {
+		return PickerItem{}, false
+	}
+	return *m.selected, true
+}
+
+func (m PickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		// j/k would be typed into the filter, so only arrows move here
+		switch {
+		case key.Matches(msg, tui.Keys.Quit), key.Matches(msg, tui.Keys.Back):
+			m.done = true
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Enter):
+			if len(m.matches) > 0 {
+				item := m.matches[m.cursor]
+				m.selected = &item
+				m.done = true
+				return m, tea.Quit
+			}
+			return m, nil
+		case msg.Type == tea.KeyUp || msg.Type == tea.KeyCtrlP:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case msg.Type == tea.KeyDown || msg.Type == tea.KeyCtrlN:
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	prev := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != prev {
+		m.matches = filterPickerItems(m.items, m.input.Value())
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+func (m PickerModel) View() string {
+	if m.done {
+		return ""
+	}
+	header := components.Header(m.title, m.width)
+
+	body := "  " + m.input.View() + "\n\n"
+	if len(m.matches) == 0 {
+		body += "  " + tui.Muted.Render("No matches") + "\n"
+	}
+
+	// Keep the cursor visible when there are more matches than rows
+	start := 0
+	if m.cursor >= pickerMaxRows {
+		start = m.cursor - pickerMaxRows + 1
+	}
+	end := min(start+pickerMaxRows, len(m.matches))
+	for i := start; i < end; i++ {
+		item := m.matches[i]
+		cursor := "  "
+		style := tui.MenuItemNormal
+		if i == m.cursor {
+			cursor = tui.Primary.Render("▸ ")
+			style = tui.MenuItemSelected
+		}
+		line := cursor + style.Render(item.Label)
+		if item.Detail != "" {
+			line += "  " + tui.Muted.Render(item.Detail)
+		}
+		body += line + "\n"
+	}
+	if len(m.matches) > end-start {
+		body += "  " + tui.Muted.Render(fmt.Sprintf("%d of %d shown", end-start, len(m.matches))) + "\n"
+	}
+
+	help := "type to filter · ↑/↓ move · enter select · esc cancel"
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", body, components.StatusBar(help, m.width))
+}
+
+// filterPickerItems returns the items whose label or detail contains the
+// query's characters in order, best matches first. An empty query keeps
+// every item in its original order.
+func filterPickerItems(items []PickerItem, query string) []PickerItem {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return items
+	}
+
+	type scored struct {
+		item  PickerItem
+		score int
+	}
+	var found []scored
+	for _, item := range items {
+		score, ok := fuzzyScore(strings.ToLower(item.Label+" "+item.Detail), query)
+		if ok {
+			found = append(found, scored{item, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score < found[j].score })
+
+	result := make([]PickerItem, len(found))
+	for i, f := range found {
+		result[i] = f.item
+	}
+	return result
+}
+
+// fuzzyScore reports whether query is a subsequence of text. Lower scores
+// are better: substring matches beat scattered ones, and earlier beats later.
+func fuzzyScore(text, query string) (int, bool) {
+	if i := strings.Index(text, query); i >= 0 {
+		return i, true
+	}
+	score, pos := len(text), 0
+	for _, r := range query {
+		i := strings.IndexRune(text[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		// Each skipped character makes the match looser
+		score += i
+		pos += i + len(string(r))
+	}
+	return score, true
+}
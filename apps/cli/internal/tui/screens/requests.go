@@ -0,0 +1,486 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/cache"
+	"webhooks.cc/cli/internal/filters"
+	"webhooks.cc/cli/internal/stream"
+	"webhooks.cc/cli/internal/tui"
+	"webhooks.cc/cli/internal/tui/components"
+	"webhooks.cc/shared/search"
+	"webhooks.cc/shared/types"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// requestsPageSize is how many recent requests the history browser loads.
+const requestsPageSize = 100
+
+type requestsState int
+
+const (
+	requestsPicker requestsState = iota
+	requestsBrowsing
+)
+
+// RequestsModel browses previously captured requests for an endpoint.
+type RequestsModel struct {
+	client     *api.Client
+	width      int
+	height     int
+	state      requestsState
+	endpoints  []tui.Endpoint
+	cursor     int
+	requests   []*types.CapturedRequest
+	scrollPos  int
+	loading    bool
+	spinner    spinner.Model
+	err        error
+	slug       string
+	pinnedOnly bool
+	// cached and offline describe where the last load came from
+	cached    int
+	offline   bool
+	query     *search.Query
+	searching bool
+	searchBar textinput.Model
+	// saved filters offered in the search bar; savedIdx is the next one tab selects
+	saved    []filters.Filter
+	savedIdx int
+}
+
+func NewRequests(client *api.Client, slug string) RequestsModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(tui.ColorPrimary)
+
+	ti := textinput.New()
+	ti.Placeholder = `method:POST path:/stripe/* body:"customer_123"`
+	ti.CharLimit = 256
+	ti.Prompt = "/ "
+
+	m := RequestsModel{
+		client:    client,
+		loading:   true,
+		spinner:   s,
+		slug:      slug,
+		searchBar: ti,
+	}
+
+	if slug != "" {
+		m.state = requestsBrowsing
+	}
+
+	return m
+}
+
+func (m RequestsModel) Init() tea.Cmd {
+	if m.slug != "" {
+		return tea.Batch(m.spinner.Tick, m.loadRequests())
+	}
+	return tea.Batch(m.spinner.Tick, loadEndpointsCmd(m.client))
+}
+
+func (m RequestsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearching(msg)
+		}
+		switch {
+		case key.Matches(msg, tui.Keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Back):
+			if m.state == requestsBrowsing {
+				m.state = requestsPicker
+				m.slug = ""
+				m.requests = nil
+				m.scrollPos = 0
+				m.pinnedOnly = false
+				m.query = nil
+				m.err = nil
+				m.loading = true
+				return m, loadEndpointsCmd(m.client)
+			}
+			return m, func() tea.Msg { return tui.BackMsg{} }
+		case key.Matches(msg, tui.Keys.Up):
+			if m.state == requestsPicker && m.cursor > 0 {
+				m.cursor--
+			} else if m.state == requestsBrowsing && m.scrollPos > 0 {
+				m.scrollPos--
+			}
+		case key.Matches(msg, tui.Keys.Down):
+			if m.state == requestsPicker && m.cursor < len(m.endpoints)-1 {
+				m.cursor++
+			} else if m.state == requestsBrowsing && m.scrollPos < len(m.requests)-1 {
+				m.scrollPos++
+			}
+		case key.Matches(msg, tui.Keys.Refresh):
+			if m.state == requestsBrowsing && !m.loading {
+				m.loading = true
+				m.err = nil
+				return m, tea.Batch(m.spinner.Tick, m.loadRequests())
+			}
+		case key.Matches(msg, tui.Keys.Filter):
+			if m.state == requestsBrowsing && !m.loading {
+				m.pinnedOnly = !m.pinnedOnly
+				m.scrollPos = 0
+				m.loading = true
+				m.err = nil
+				return m, tea.Batch(m.spinner.Tick, m.loadRequests())
+			}
+		case key.Matches(msg, tui.Keys.Search):
+			if m.state == requestsBrowsing {
+				m.searching = true
+				m.saved, _ = filters.List()
+				m.savedIdx = 0
+				m.searchBar.SetValue(m.query.String())
+				m.searchBar.CursorEnd()
+				m.searchBar.Focus()
+				return m, m.searchBar.Cursor.BlinkCmd()
+			}
+		case key.Matches(msg, tui.Keys.Pin):
+			if m.state == requestsBrowsing && m.scrollPos < len(m.requests) {
+				req := m.requests[m.scrollPos]
+				return m, m.togglePin(req.ID, !req.Pinned)
+			}
+		case key.Matches(msg, tui.Keys.Enter):
+			if m.state == requestsPicker && len(m.endpoints) > 0 {
+				m.slug = m.endpoints[m.cursor].Slug
+				m.state = requestsBrowsing
+				m.loading = true
+				return m, tea.Batch(m.spinner.Tick, m.loadRequests())
+			}
+			if m.state == requestsBrowsing && m.scrollPos < len(m.requests) {
+				req := m.requests[m.scrollPos]
+				return m, func() tea.Msg {
+					return tui.NavigateMsg{Screen: tui.ScreenDetail, Data: req}
+				}
+			}
+		}
+
+	case tui.EndpointsLoadedMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.endpoints = msg.Endpoints
+
+	case tui.RequestsLoadedMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.requests = msg.Requests
+		m.cached = msg.Cached
+		m.offline = msg.Offline
+		if m.scrollPos >= len(m.requests) {
+			m.scrollPos = 0
+		}
+
+	case tui.RequestPinnedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		for i, req := range m.requests {
+			if req.ID != msg.RequestID {
+				continue
+			}
+			req.Pinned = msg.Pinned
+			// Unpinned requests leave the pinned-only view
+			if m.pinnedOnly && !msg.Pinned {
+				m.requests = append(m.requests[:i], m.requests[i+1:]...)
+				if m.scrollPos >= len(m.requests) && m.scrollPos > 0 {
+					m.scrollPos--
+				}
+			}
+			break
+		}
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m RequestsModel) updateSearching(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, tui.Keys.Back):
+		m.searching = false
+		m.searchBar.Blur()
+		return m, nil
+	case key.Matches(msg, tui.Keys.Tab):
+		if len(m.saved) > 0 {
+			m.searchBar.SetValue(m.saved[m.savedIdx].Query)
+			m.searchBar.CursorEnd()
+			m.savedIdx = (m.savedIdx + 1) % len(m.saved)
+		}
+		return m, nil
+	case key.Matches(msg, tui.Keys.Enter):
+		q, err := search.Parse(m.searchBar.Value())
+		if err != nil {
+			m.err = fmt.Errorf("invalid search: %w", err)
+			return m, nil
+		}
+		m.searching = false
+		m.searchBar.Blur()
+		m.query = q
+		m.scrollPos = 0
+		m.loading = true
+		m.err = nil
+		return m, tea.Batch(m.spinner.Tick, m.loadRequests())
+	default:
+		var cmd tea.Cmd
+		m.searchBar, cmd = m.searchBar.Update(msg)
+		return m, cmd
+	}
+}
+
+func (m RequestsModel) loadRequests() tea.Cmd {
+	client, slug, q := m.client, m.slug, m.query
+	params := api.ListRequestsParams{Limit: requestsPageSize, Pinned: m.pinnedOnly, Query: q.String()}
+	return func() tea.Msg {
+		reqs, err := client.ListRequests(context.Background(), slug, params)
+		// Requests saved with --cache fill in what the server's retention
+		// has deleted, or stand in for the server when it's unreachable
+		cached, cacheErr := cache.List(slug, 0)
+		offline := false
+		if err != nil {
+			if cacheErr != nil || len(cached) == 0 {
+				return tui.RequestsLoadedMsg{Err: err}
+			}
+			reqs, offline = nil, true
+		}
+		cached = slices.DeleteFunc(cached, func(r types.CapturedRequest) bool {
+			return !q.Match(&r) || (params.Pinned && !r.Pinned)
+		})
+		reqs, added := mergeCached(reqs, cached, params.Limit)
+
+		// Filter locally as well, in case the server ignores the query or
+		// the pinned filter
+		result := make([]*types.CapturedRequest, 0, len(reqs))
+		for i := range reqs {
+			if q.Match(&reqs[i]) && (!params.Pinned || reqs[i].Pinned) {
+				result = append(result, &reqs[i])
+			}
+		}
+		return tui.RequestsLoadedMsg{Requests: result, Cached: added, Offline: offline}
+	}
+}
+
+// mergeCached appends the cached requests older than the server's oldest
+// (all of them if the server returned none) and returns the merged list,
+// newest first and at most limit long, with how many came from the cache.
+func mergeCached(server, cached []types.CapturedRequest, limit int) ([]types.CapturedRequest, int) {
+	oldest := int64(-1)
+	seen := make(map[string]bool, len(server))
+	for _, r := range server {
+		seen[r.ID] = true
+		if oldest < 0 || r.ReceivedAt < oldest {
+			oldest = r.ReceivedAt
+		}
+	}
+	added := 0
+	for _, r := range cached {
+		if len(server)+added >= limit {
+			break
+		}
+		if seen[r.ID] || (oldest >= 0 && r.ReceivedAt >= oldest) {
+			continue
+		}
+		server = append(server, r)
+		added++
+	}
+	return server, added
+}
+
+func (m RequestsModel) togglePin(requestID string, pinned bool) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		var err error
+		if pinned {
+			err = client.PinRequest(context.Background(), requestID)
+		} else {
+			err = client.UnpinRequest(context.Background(), requestID)
+		}
+		return tui.RequestPinnedMsg{RequestID: requestID, Pinned: pinned, Err: err}
+	}
+}
+
+// HelpKeys lists the requests screen's key bindings for the help overlay.
+// The search box has none, since ? is typed into the query.
+func (m RequestsModel) HelpKeys() []key.Binding {
+	switch {
+	case m.searching:
+		return nil
+	case m.state == requestsPicker:
+		return []key.Binding{tui.Keys.Up, tui.Keys.Down, tui.WithHelp(tui.Keys.Enter, "browse requests"), tui.Keys.Back}
+	}
+	return []key.Binding{
+		tui.WithHelp(tui.Keys.Up, "scroll up"),
+		tui.WithHelp(tui.Keys.Down, "scroll down"),
+		tui.WithHelp(tui.Keys.Enter, "inspect request"),
+		tui.Keys.Search,
+		tui.WithHelp(tui.Keys.Pin, "pin or unpin request"),
+		tui.WithHelp(tui.Keys.Filter, "show pinned only"),
+		tui.Keys.Refresh,
+		tui.WithHelp(tui.Keys.Back, "back to endpoints"),
+	}
+}
+
+func (m RequestsModel) View() string {
+	header := components.Header("Requests", m.width)
+
+	var body string
+
+	if m.state == requestsPicker {
+		if m.loading {
+			body = fmt.Sprintf("  %s Loading endpoints...", m.spinner.View())
+		} else if len(m.endpoints) == 0 {
+			body = "  No endpoints found. Create one first."
+		} else {
+			body = "  Select an endpoint to browse:\n\n"
+			for i, ep := range m.endpoints {
+				cursor := "  "
+				style := tui.MenuItemNormal
+				if i == m.cursor {
+					cursor = tui.Primary.Render("▸ ")
+					style = tui.MenuItemSelected
+				}
+				name := ep.Slug
+				if ep.Name != "" {
+					name = ep.Name + " (" + ep.Slug + ")"
+				}
+				body += fmt.Sprintf("%s%s\n", cursor, style.Render(name))
+			}
+		}
+	} else {
+		title := fmt.Sprintf("  History for %s", tui.Secondary.Render(m.slug))
+		if m.pinnedOnly {
+			title += tui.Muted.Render("  [pinned only]")
+		}
+		if !m.query.Empty() {
+			title += tui.Muted.Render("  [" + m.query.String() + "]")
+		}
+		if m.offline {
+			title += tui.Muted.Render("  [offline: local cache]")
+		} else if m.cached > 0 {
+			title += tui.Muted.Render(fmt.Sprintf("  [%d from local cache]", m.cached))
+		}
+		if m.searching {
+			title += "\n\n  " + m.searchBar.View()
+			if len(m.saved) > 0 {
+				names := make([]string, len(m.saved))
+				for i, f := range m.saved {
+					names[i] = f.Name
+				}
+				title += "\n  " + tui.Muted.Render("saved: "+strings.Join(names, " · "))
+			}
+		}
+		if m.loading && len(m.requests) == 0 {
+			body = fmt.Sprintf("%s\n\n  %s Loading requests...", title, m.spinner.View())
+		} else if len(m.requests) == 0 && !m.query.Empty() {
+			body = fmt.Sprintf("%s\n\n  No requests match the search. Press / to change it.", title)
+		} else if len(m.requests) == 0 && m.pinnedOnly {
+			body = fmt.Sprintf("%s\n\n  No pinned requests. Press p on a request to pin it.", title)
+		} else if len(m.requests) == 0 {
+			body = fmt.Sprintf("%s\n\n  No requests captured yet.", title)
+		} else {
+			body = fmt.Sprintf("%s  (%d most recent)\n\n", title, len(m.requests))
+
+			maxVisible := m.height - 8
+			if maxVisible < 3 {
+				maxVisible = 3
+			}
+
+			start := 0
+			if len(m.requests) > maxVisible {
+				start = m.scrollPos - maxVisible/2
+				if start < 0 {
+					start = 0
+				}
+				if start+maxVisible > len(m.requests) {
+					start = len(m.requests) - maxVisible
+				}
+			}
+			end := min(start+maxVisible, len(m.requests))
+
+			for i := start; i < end; i++ {
+				req := m.requests[i]
+				cursor := "  "
+				if i == m.scrollPos {
+					cursor = tui.Primary.Render("▸ ")
+				}
+				pin := " "
+				if req.Pinned {
+					pin = tui.Accent.Render("★")
+				}
+				ts := time.UnixMilli(req.ReceivedAt).Format("Jan 02 15:04:05")
+				method := tui.MethodStyle(req.Method).Render(fmt.Sprintf("%-7s", req.Method))
+				var tags string
+				if len(req.Tags) > 0 {
+					tags = "  " + tui.Accent.Render("#"+strings.Join(req.Tags, " #"))
+				}
+				body += fmt.Sprintf("%s%s %s  %s  %s  %s%s\n",
+					cursor,
+					pin,
+					tui.Muted.Render(ts),
+					method,
+					req.Path,
+					tui.Muted.Render(stream.FormatBytes(req.Size)),
+					tags,
+				)
+			}
+		}
+	}
+
+	if m.err != nil {
+		body += fmt.Sprintf("\n  %s %s", tui.Danger.Render("Error:"), m.err)
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", body)
+
+	var help string
+	if m.state == requestsPicker {
+		help = "↑↓ navigate · enter select · esc back · ctrl+c quit"
+	} else if m.searching {
+		help = "enter search · tab saved filters · esc cancel · ctrl+c quit"
+	} else {
+		help = "↑↓ scroll · enter inspect · / search · p pin · f pinned only · r refresh · esc back · ctrl+c quit"
+	}
+	statusBar := components.StatusBar(help, m.width)
+
+	contentHeight := lipgloss.Height(content)
+	statusHeight := lipgloss.Height(statusBar)
+	gap := m.height - contentHeight - statusHeight
+	if gap < 0 {
+		gap = 0
+	}
+
+	return content + fmt.Sprintf("%*s", gap, "\n") + statusBar
+}
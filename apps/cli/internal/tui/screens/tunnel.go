@@ -10,6 +10,7 @@ import (
 
 	"webhooks.cc/cli/internal/api"
 	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/intercept"
 	"webhooks.cc/cli/internal/stream"
 	"webhooks.cc/cli/internal/tui"
 	"webhooks.cc/cli/internal/tui/components"
@@ -34,6 +35,17 @@ const (
 type tunnelRequest struct {
 	req    *types.CapturedRequest
 	result *tunnel.ForwardResult
+	// held is set while intercept waits for the request to be forwarded,
+	// edited or dropped
+	held    bool
+	dropped bool
+}
+
+// bodyEditedMsg is sent when the editor opened for a held request exits.
+type bodyEditedMsg struct {
+	id  string
+	req *types.CapturedRequest
+	err error
 }
 
 type TunnelModel struct {
@@ -52,6 +64,7 @@ type TunnelModel struct {
 	sseSession *tui.SSESession
 	tun        *tunnel.Tunnel
 	epCreated  bool // whether we created an ephemeral endpoint
+	intercept  bool // hold requests until they are forwarded or dropped
 }
 
 func NewTunnel(client *api.Client) TunnelModel {
@@ -162,6 +175,56 @@ func (m TunnelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.state == tunnelActive && m.scrollPos < len(m.requests)-1 {
 				m.scrollPos++
 			}
+		case key.Matches(msg, tui.Keys.Intercept):
+			if m.state == tunnelActive {
+				m.intercept = !m.intercept
+				if !m.intercept {
+					// Nothing stays held once intercept is off
+					var cmds []tea.Cmd
+					for i := range m.requests {
+						if m.requests[i].held {
+							cmds = append(cmds, m.release(i))
+						}
+					}
+					return m, tea.Batch(cmds...)
+				}
+			}
+		case key.Matches(msg, tui.Keys.Forward):
+			if i, ok := m.heldAtCursor(); ok {
+				return m, m.release(i)
+			}
+		case key.Matches(msg, tui.Keys.Delete):
+			if i, ok := m.heldAtCursor(); ok {
+				m.requests[i].held = false
+				m.requests[i].dropped = true
+			}
+		case key.Matches(msg, tui.Keys.Edit):
+			if i, ok := m.heldAtCursor(); ok {
+				e, err := intercept.Start(m.requests[i].req)
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.err = nil
+				id := m.requests[i].req.ID
+				return m, tea.ExecProcess(e.Cmd(), func(err error) tea.Msg {
+					if err != nil {
+						e.Cancel()
+						return bodyEditedMsg{id: id, err: fmt.Errorf("editor: %w", err)}
+					}
+					req, err := e.Finish()
+					return bodyEditedMsg{id: id, req: req, err: err}
+				})
+			}
+		}
+
+	case bodyEditedMsg:
+		m.err = msg.err
+		for i := range m.requests {
+			if m.requests[i].req.ID == msg.id && m.requests[i].held && msg.req != nil {
+				m.requests[i].req = msg.req
+				break
+			}
 		}
 
 	case tui.EndpointCreatedMsg:
@@ -189,7 +252,11 @@ func (m TunnelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.sseSession != nil {
 			cmds = append(cmds, tui.WaitForSSE(m.sseSession))
 		}
-		cmds = append(cmds, m.forwardRequest(msg.Request, idx))
+		if m.intercept {
+			m.requests[idx].held = true
+		} else {
+			cmds = append(cmds, m.forwardRequest(msg.Request, idx))
+		}
 		return m, tea.Batch(cmds...)
 
 	case tui.ForwardResultMsg:
@@ -253,7 +320,7 @@ func (m *TunnelModel) connectStream() tea.Cmd {
 	tok, err := auth.LoadToken()
 	if err != nil {
 		return func() tea.Msg {
-			return tui.SSEErrorMsg{Err: fmt.Errorf("not logged in: %w", err)}
+			return tui.SSEErrorMsg{Err: fmt.Errorf("%w: %w", auth.ErrNotLoggedIn, err)}
 		}
 	}
 
@@ -263,6 +330,21 @@ func (m *TunnelModel) connectStream() tea.Cmd {
 	return cmd
 }
 
+// heldAtCursor returns the index of the request under the cursor if it is
+// held by intercept.
+func (m TunnelModel) heldAtCursor() (int, bool) {
+	if m.state != tunnelActive || m.scrollPos >= len(m.requests) || !m.requests[m.scrollPos].held {
+		return 0, false
+	}
+	return m.scrollPos, true
+}
+
+// release forwards the held request at index i.
+func (m *TunnelModel) release(i int) tea.Cmd {
+	m.requests[i].held = false
+	return m.forwardRequest(m.requests[i].req, i)
+}
+
 func (m TunnelModel) forwardRequest(req *types.CapturedRequest, _ int) tea.Cmd {
 	t := m.tun
 	reqID := req.ID
@@ -272,6 +354,35 @@ func (m TunnelModel) forwardRequest(req *types.CapturedRequest, _ int) tea.Cmd {
 	}
 }
 
+// HelpKeys lists the tunnel screen's key bindings for the help overlay.
+// The port input has none, since ? would be typed.
+func (m TunnelModel) HelpKeys() []key.Binding {
+	switch m.state {
+	case tunnelInput:
+		return nil
+	case tunnelConnecting:
+		return []key.Binding{tui.WithHelp(tui.Keys.Back, "cancel")}
+	}
+	intercept := "hold requests before forwarding"
+	if m.intercept {
+		intercept = "stop holding requests, forwarding held ones"
+	}
+	bindings := []key.Binding{
+		tui.WithHelp(tui.Keys.Up, "scroll up"),
+		tui.WithHelp(tui.Keys.Down, "scroll down"),
+		tui.WithHelp(tui.Keys.Enter, "inspect request"),
+		tui.WithHelp(tui.Keys.Intercept, intercept),
+	}
+	if m.intercept {
+		bindings = append(bindings,
+			tui.WithHelp(tui.Keys.Forward, "forward held request"),
+			tui.WithHelp(tui.Keys.Edit, "edit held request body"),
+			tui.WithHelp(tui.Keys.Delete, "drop held request"),
+		)
+	}
+	return append(bindings, tui.WithHelp(tui.Keys.Back, "stop the tunnel"))
+}
+
 func (m TunnelModel) View() string {
 	header := components.Header("Tunnel", m.width)
 
@@ -301,6 +412,9 @@ func (m TunnelModel) View() string {
 			tui.Success.Render("●"),
 			len(m.requests),
 		)
+		if m.intercept {
+			countLine += "  " + tui.Accent.Render("intercepting")
+		}
 		body = fmt.Sprintf("%s\n%s\n%s\n\n", webhookLine, targetLine, countLine)
 
 		if len(m.requests) == 0 {
@@ -336,7 +450,11 @@ func (m TunnelModel) View() string {
 				method := tui.MethodStyle(tr.req.Method).Render(fmt.Sprintf("%-7s", tr.req.Method))
 
 				var status string
-				if tr.result == nil {
+				if tr.held {
+					status = tui.Accent.Render("held")
+				} else if tr.dropped {
+					status = tui.Muted.Render("dropped")
+				} else if tr.result == nil {
 					status = m.spinner.View()
 				} else if tr.result.Success {
 					status = tui.Success.Render(fmt.Sprintf("→ %d (%dms)",
@@ -370,7 +488,11 @@ func (m TunnelModel) View() string {
 	case tunnelConnecting:
 		help = "esc cancel · ctrl+c quit"
 	case tunnelActive:
-		help = "↑↓ scroll · enter inspect · esc stop · ctrl+c quit"
+		if m.intercept {
+			help = "f forward · e edit · d drop · i intercept off · esc stop"
+		} else {
+			help = "↑↓ scroll · enter inspect · i intercept · esc stop · ctrl+c quit"
+		}
 	}
 	statusBar := components.StatusBar(help, m.width)
 
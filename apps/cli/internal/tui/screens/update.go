@@ -63,7 +63,7 @@ func (m UpdateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case key.Matches(msg, tui.Keys.Back):
 			return m, func() tea.Msg { return tui.BackMsg{} }
-		case msg.String() == "u" && m.state == updAvailable:
+		case key.Matches(msg, tui.Keys.Update) && m.state == updAvailable:
 			m.state = updApplying
 			return m, tea.Batch(m.spinner.Tick, m.applyUpdate())
 		}
@@ -124,6 +124,13 @@ func (m UpdateModel) applyUpdate() tea.Cmd {
 	}
 }
 
+// HelpKeys lists the update screen's key bindings for the help overlay.
+func (m UpdateModel) HelpKeys() []key.Binding {
+	update := tui.WithHelp(tui.Keys.Update, "install the update")
+	update.SetEnabled(m.state == updAvailable)
+	return []key.Binding{update, tui.Keys.Back}
+}
+
 func (m UpdateModel) View() string {
 	header := components.Header("Update", m.width)
 
@@ -34,6 +34,12 @@ var (
 			BorderForeground(ColorBorder).
 			Padding(0, 1)
 
+	// QRCode draws QR codes dark-on-light whatever the terminal's theme,
+	// so they scan
+	QRCode = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color("#FFFFFF"))
+
 	// Status indicators
 	StatusOnline  = Success.Render("●")
 	StatusOffline = Danger.Render("●")
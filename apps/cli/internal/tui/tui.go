@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 
 	"webhooks.cc/cli/internal/api"
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/tui/components"
 	"webhooks.cc/shared/types"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -16,6 +20,8 @@ type App struct {
 	active  tea.Model
 	width   int
 	height  int
+	// help is set while the ? help overlay is shown
+	help bool
 
 	// Factory functions set by the Run caller
 	menuFactory      func(version string) tea.Model
@@ -25,6 +31,7 @@ type App struct {
 	listenFactory    func(client *api.Client, slug string) tea.Model
 	tunnelFactory    func(client *api.Client) tea.Model
 	detailFactory    func(req *types.CapturedRequest) tea.Model
+	requestsFactory  func(client *api.Client, slug string) tea.Model
 }
 
 func (a App) Init() tea.Cmd {
@@ -41,11 +48,35 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.active, cmd = a.active.Update(msg)
 		return a, cmd
 
+	case tea.KeyMsg:
+		if a.help {
+			a.help = false
+			if key.Matches(msg, Keys.Quit) {
+				return a, tea.Quit
+			}
+			return a, nil
+		}
+		if key.Matches(msg, Keys.Help) && a.helpKeys() != nil {
+			a.help = true
+			return a, nil
+		}
+
 	case NavigateMsg:
 		return a.navigate(msg)
 
 	case BackMsg:
 		return a.navigateToMenu()
+
+	case SessionExpiredMsg:
+		return a.navigateToAuth()
+
+	case SSEErrorMsg:
+		// Streams report a rejected token as an error rather than through
+		// the API client, so mark it here
+		if errors.Is(msg.Err, auth.ErrSessionExpired) {
+			_ = auth.MarkExpired()
+			return a.navigateToAuth()
+		}
 	}
 
 	var cmd tea.Cmd
@@ -54,58 +85,104 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (a App) View() string {
+	if a.help {
+		return components.HelpOverlay(a.screen.String(), a.helpKeys(), a.width, a.height)
+	}
 	return a.active.View()
 }
 
+// helpKeys returns the bindings the help overlay lists for the active
+// screen, or nil if it has none to show right now.
+func (a App) helpKeys() []key.Binding {
+	p, ok := a.active.(HelpProvider)
+	if !ok {
+		return nil
+	}
+	bindings := p.HelpKeys()
+	if bindings == nil {
+		return nil
+	}
+	return append(bindings, WithHelp(Keys.Help, "toggle this help"), Keys.Quit)
+}
+
 func (a App) navigate(msg NavigateMsg) (tea.Model, tea.Cmd) {
+	a.help = false
 	a.screen = msg.Screen
+	a.active = a.screenModel(msg)
+
+	// Send initial window size + init
+	var cmds []tea.Cmd
+	cmds = append(cmds, a.active.Init())
+	if a.width > 0 && a.height > 0 {
+		cmds = append(cmds, func() tea.Msg {
+			return tea.WindowSizeMsg{Width: a.width, Height: a.height}
+		})
+	}
+
+	return a, tea.Batch(cmds...)
+}
 
+// screenModel builds the model for a navigation target. If the target
+// can't be built (e.g. detail without a request) the active screen is kept.
+func (a App) screenModel(msg NavigateMsg) tea.Model {
+	active := a.active
 	switch msg.Screen {
 	case ScreenMenu:
-		a.active = a.menuFactory(a.version)
+		active = a.menuFactory(a.version)
 	case ScreenAuth:
-		a.active = a.authFactory(a.client)
+		active = a.authFactory(a.client)
 	case ScreenEndpoints:
 		mode := ""
 		if s, ok := msg.Data.(string); ok {
 			mode = s
 		}
-		a.active = a.endpointsFactory(a.client, mode)
+		active = a.endpointsFactory(a.client, mode)
 	case ScreenUpdate:
-		a.active = a.updateFactory(a.version)
+		active = a.updateFactory(a.version)
 	case ScreenListen:
 		slug := ""
 		if s, ok := msg.Data.(string); ok {
 			slug = s
 		}
-		a.active = a.listenFactory(a.client, slug)
+		active = a.listenFactory(a.client, slug)
 	case ScreenTunnel:
-		a.active = a.tunnelFactory(a.client)
+		active = a.tunnelFactory(a.client)
+	case ScreenRequests:
+		slug := ""
+		if s, ok := msg.Data.(string); ok {
+			slug = s
+		}
+		active = a.requestsFactory(a.client, slug)
 	case ScreenDetail:
 		if req, ok := msg.Data.(*types.CapturedRequest); ok {
-			a.active = a.detailFactory(req)
+			active = a.detailFactory(req)
 		}
 	}
-
-	// Send initial window size + init
-	var cmds []tea.Cmd
-	cmds = append(cmds, a.active.Init())
-	if a.width > 0 && a.height > 0 {
-		cmds = append(cmds, func() tea.Msg {
-			return tea.WindowSizeMsg{Width: a.width, Height: a.height}
-		})
-	}
-
-	return a, tea.Batch(cmds...)
+	return active
 }
 
 func (a App) navigateToMenu() (tea.Model, tea.Cmd) {
 	return a.navigate(NavigateMsg{Screen: ScreenMenu})
 }
 
+// navigateToAuth switches to the Auth screen after the session expired,
+// unless the user is already there (e.g. several requests failed at once).
+func (a App) navigateToAuth() (tea.Model, tea.Cmd) {
+	if a.screen == ScreenAuth {
+		return a, nil
+	}
+	return a.navigate(NavigateMsg{Screen: ScreenAuth})
+}
+
 // Run starts the TUI. screenFactories are injected by the caller so
 // the tui package doesn't import screens (avoiding circular imports).
 func Run(client *api.Client, version string, factories ScreenFactories) error {
+	return RunScreen(client, version, factories, NavigateMsg{Screen: ScreenMenu})
+}
+
+// RunScreen starts the TUI on a specific screen (a deep link). Going back
+// from that screen returns to the menu as usual.
+func RunScreen(client *api.Client, version string, factories ScreenFactories, start NavigateMsg) error {
 	menu := factories.Menu(version)
 	app := App{
 		client:           client,
@@ -119,9 +196,15 @@ func Run(client *api.Client, version string, factories ScreenFactories) error {
 		listenFactory:    factories.Listen,
 		tunnelFactory:    factories.Tunnel,
 		detailFactory:    factories.Detail,
+		requestsFactory:  factories.Requests,
+	}
+	if start.Screen != ScreenMenu {
+		app.screen = start.Screen
+		app.active = app.screenModel(start)
 	}
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
+	client.OnSessionExpired(func() { p.Send(SessionExpiredMsg{}) })
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
@@ -138,4 +221,5 @@ type ScreenFactories struct {
 	Listen    func(client *api.Client, slug string) tea.Model
 	Tunnel    func(client *api.Client) tea.Model
 	Detail    func(req *types.CapturedRequest) tea.Model
+	Requests  func(client *api.Client, slug string) tea.Model
 }
@@ -13,11 +13,15 @@ import (
 	"time"
 
 	"webhooks.cc/shared/types"
+	"webhooks.cc/shared/validation"
 )
 
 // maxResponseBodySize limits the response body to prevent memory exhaustion
 const maxResponseBodySize = 100 * 1024 * 1024 // 100MB
 
+// maxKeptResponseSize limits response bodies held in memory by KeepResponses
+const maxKeptResponseSize = 10 * 1024 * 1024 // 10MB
+
 // sensitiveHeaders that should not be forwarded to local services (lowercase for case-insensitive matching)
 // These headers from captured webhooks could expose credentials or cause security issues
 var sensitiveHeaders = map[string]bool{
@@ -30,28 +34,22 @@ var sensitiveHeaders = map[string]bool{
 	"x-access-token":      true,
 }
 
-// proxyHeaders added by infrastructure (Cloudflare, Caddy) — not part of the original request
-var proxyHeaders = map[string]bool{
-	"cdn-loop":          true,
-	"cf-connecting-ip":  true,
-	"cf-ipcountry":      true,
-	"cf-ray":            true,
-	"cf-visitor":        true,
-	"via":               true,
-	"x-forwarded-for":   true,
-	"x-forwarded-host":  true,
-	"x-forwarded-proto": true,
-	"x-real-ip":         true,
-	"true-client-ip":    true,
+// IsSensitiveHeader reports whether name (case-insensitive) carries
+// credentials and must not be sent on to another service.
+func IsSensitiveHeader(name string) bool {
+	return sensitiveHeaders[strings.ToLower(name)]
 }
 
 // Tunnel forwards captured webhook requests to a local target URL.
 // Filters security-sensitive headers (Authorization, Cookie, etc.)
 // before forwarding to prevent credential leakage.
 type Tunnel struct {
-	endpointSlug string
-	targetURL    string
-	httpClient   *http.Client
+	endpointSlug  string
+	targetURL     string
+	httpClient    *http.Client
+	keepResponses bool
+	// headers are added to every forwarded request (see SetHeaders)
+	headers map[string]string
 }
 
 // New creates a Tunnel that forwards requests to the given target URL.
@@ -65,21 +63,34 @@ func New(endpointSlug, targetURL string) *Tunnel {
 	}
 }
 
-// Forward sends a captured request to the target URL
-func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
-	start := time.Now()
+// KeepResponses makes Forward return the target's response headers and
+// body (up to 10MB) in the ForwardResult instead of discarding them.
+func (t *Tunnel) KeepResponses() {
+	t.keepResponses = true
+}
+
+// SetHeaders adds headers the user configured (whk tunnel --header and
+// --headers-file) to every forwarded request, replacing captured headers of
+// the same name. Unlike captured headers they may carry credentials, such
+// as the Authorization the local server expects.
+func (t *Tunnel) SetHeaders(headers map[string]string) {
+	t.headers = headers
+}
 
+// TargetURL returns the URL Forward sends req to: the target URL joined
+// with the request's path and query parameters.
+func (t *Tunnel) TargetURL(req *types.CapturedRequest) (string, error) {
 	// Parse the base target URL
 	base, err := url.Parse(t.targetURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid target URL: %w", err)
+		return "", fmt.Errorf("invalid target URL: %w", err)
 	}
 
 	// Safely join the path to prevent path traversal attacks
 	// url.JoinPath properly handles ".." and other malicious path segments
 	targetURL, err := url.JoinPath(base.String(), req.Path)
 	if err != nil {
-		return nil, fmt.Errorf("invalid request path: %w", err)
+		return "", fmt.Errorf("invalid request path: %w", err)
 	}
 
 	// Append query parameters from the original request
@@ -94,9 +105,26 @@ func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
 			targetURL = parsedTarget.String()
 		}
 	}
+	return targetURL, nil
+}
+
+// Forward sends a captured request to the target URL
+func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
+	start := time.Now()
+
+	targetURL, err := t.TargetURL(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the body (v2 captures may carry binary bodies as base64)
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode request body: %w", err)
+	}
 
 	// Create the forwarded request
-	httpReq, err := http.NewRequest(req.Method, targetURL, bytes.NewBufferString(req.Body))
+	httpReq, err := http.NewRequest(req.Method, targetURL, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -105,29 +133,56 @@ func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
 	// We filter sensitive headers to prevent forwarding credentials from
 	// captured webhooks to the local target service
 	// Use case-insensitive matching since HTTP headers are case-insensitive per RFC 7230
-	for key, value := range req.Headers {
-		keyLower := strings.ToLower(key)
-		if keyLower != "host" && !sensitiveHeaders[keyLower] && !proxyHeaders[keyLower] {
-			httpReq.Header.Set(key, value)
+	// Repeated headers from v2 captures are forwarded as separate lines.
+	for _, field := range req.Fields() {
+		keyLower := strings.ToLower(field.Name)
+		if keyLower != "host" && !IsSensitiveHeader(keyLower) && !validation.IsProxyHeader(keyLower) {
+			httpReq.Header.Add(field.Name, field.Value)
 		}
 	}
+	for name, value := range t.headers {
+		httpReq.Header.Set(name, value)
+	}
 
 	// Send the request
 	resp, err := t.httpClient.Do(httpReq)
 	if err != nil {
 		return &ForwardResult{
 			Success:  false,
+			Target:   targetURL,
 			Error:    err.Error(),
 			Duration: time.Since(start),
 		}, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if t.keepResponses {
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxKeptResponseSize))
+		if err != nil {
+			return &ForwardResult{
+				Success:  false,
+				Target:   targetURL,
+				Error:    fmt.Sprintf("failed to read response: %v", err),
+				Duration: time.Since(start),
+			}, nil
+		}
+		return &ForwardResult{
+			Success:    true,
+			Target:     targetURL,
+			StatusCode: resp.StatusCode,
+			Duration:   time.Since(start),
+			BodySize:   len(respBody),
+			Headers:    resp.Header.Clone(),
+			Body:       respBody,
+		}, nil
+	}
+
 	// Count bytes without allocating memory for the body
 	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
 	if err != nil {
 		return &ForwardResult{
 			Success:  false,
+			Target:   targetURL,
 			Error:    fmt.Sprintf("failed to read response: %v", err),
 			Duration: time.Since(start),
 		}, nil
@@ -135,6 +190,7 @@ func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
 
 	return &ForwardResult{
 		Success:    true,
+		Target:     targetURL,
 		StatusCode: resp.StatusCode,
 		Duration:   time.Since(start),
 		BodySize:   int(n),
@@ -143,14 +199,33 @@ func (t *Tunnel) Forward(req *types.CapturedRequest) (*ForwardResult, error) {
 
 // ForwardResult contains the outcome of forwarding a request.
 // On failure, Success is false and Error describes what went wrong.
+// Headers and Body are only set when the Tunnel keeps responses.
 type ForwardResult struct {
 	Success    bool
+	Target     string
 	StatusCode int
 	Duration   time.Duration
 	BodySize   int
+	Headers    http.Header
+	Body       []byte
 	Error      string
 }
 
+// Attempt converts the result into the shared ForwardAttempt shape used for
+// delivery history. attempt is the 1-based attempt number.
+func (r *ForwardResult) Attempt(requestID string, attempt int) types.ForwardAttempt {
+	return types.ForwardAttempt{
+		RequestID:   requestID,
+		Source:      types.ForwardSourceTunnel,
+		Target:      r.Target,
+		Attempt:     attempt,
+		StatusCode:  r.StatusCode,
+		LatencyMs:   r.Duration.Milliseconds(),
+		Error:       r.Error,
+		AttemptedAt: time.Now().Add(-r.Duration).UnixMilli(),
+	}
+}
+
 // String returns a formatted status for terminal display.
 func (r *ForwardResult) String() string {
 	if !r.Success {
@@ -99,6 +99,39 @@ func TestForward_HostHeaderNotForwarded(t *testing.T) {
 	}
 }
 
+func TestForward_SetHeaders(t *testing.T) {
+	var receivedHeaders http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		w.WriteHeader(200)
+	}))
+	t.Cleanup(target.Close)
+
+	tun := New("test-slug", target.URL)
+	tun.SetHeaders(map[string]string{"Authorization": "Bearer local-token", "X-Env": "local"})
+
+	req := &types.CapturedRequest{
+		Method: "POST",
+		Path:   "/",
+		Headers: map[string]string{
+			"Authorization": "Bearer captured",
+			"x-env":         "captured",
+		},
+	}
+	if _, err := tun.Forward(req); err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	if got := receivedHeaders.Get("Authorization"); got != "Bearer local-token" {
+		t.Errorf("Authorization = %q, want the configured header", got)
+	}
+	if got := receivedHeaders.Values("X-Env"); len(got) != 1 || got[0] != "local" {
+		t.Errorf("X-Env = %v, want the configured header to replace the captured one", got)
+	}
+	if req.Headers["Authorization"] != "Bearer captured" {
+		t.Error("the captured request must not be modified")
+	}
+}
+
 func TestForward_CaseInsensitiveHeaderMatching(t *testing.T) {
 	var receivedHeaders http.Header
 	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -237,6 +270,51 @@ func TestForward_BodyForwarded(t *testing.T) {
 	}
 }
 
+func TestForward_BinaryBodyAndRepeatedHeaders(t *testing.T) {
+	var receivedBody []byte
+	var receivedHeaders http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		receivedHeaders = r.Header.Clone()
+		w.WriteHeader(200)
+	}))
+	t.Cleanup(target.Close)
+
+	tun := New("test-slug", target.URL)
+
+	req := (&types.CapturedRequestV2{
+		CapturedRequest: types.CapturedRequest{
+			Method:       "POST",
+			Path:         "/",
+			Body:         "AP8B",
+			BodyEncoding: types.BodyEncodingBase64,
+		},
+		Headers: []types.HeaderField{
+			{Name: "X-Trace", Value: "a"},
+			{Name: "X-Trace", Value: "b"},
+			{Name: "Cookie", Value: "secret=1"},
+		},
+	}).ToCapturedRequest()
+
+	result, err := tun.Forward(req)
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Forward failed: %s", result.Error)
+	}
+
+	if string(receivedBody) != "\x00\xff\x01" {
+		t.Errorf("body mismatch: got %v", receivedBody)
+	}
+	if got := receivedHeaders.Values("X-Trace"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected repeated X-Trace headers [a b], got %v", got)
+	}
+	if receivedHeaders.Get("Cookie") != "" {
+		t.Error("sensitive header should not be forwarded")
+	}
+}
+
 func TestForwardResult_String_Success(t *testing.T) {
 	r := &ForwardResult{
 		Success:    true,
@@ -314,3 +392,50 @@ func TestForward_ResponseStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardResult_Attempt(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(202)
+	}))
+	t.Cleanup(target.Close)
+
+	tun := New("test-slug", target.URL)
+	result, err := tun.Forward(&types.CapturedRequest{Method: "POST", Path: "/hook"})
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+
+	a := result.Attempt("req-1", 1)
+	if a.RequestID != "req-1" || a.Attempt != 1 || a.Source != types.ForwardSourceTunnel {
+		t.Errorf("unexpected attempt metadata: %+v", a)
+	}
+	if a.Target != target.URL+"/hook" {
+		t.Errorf("Target = %q, want %q", a.Target, target.URL+"/hook")
+	}
+	if a.StatusCode != 202 || !a.Delivered() {
+		t.Errorf("expected delivered 202 attempt, got %+v", a)
+	}
+}
+
+func TestForward_KeepResponses(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Local", "yes")
+		w.WriteHeader(201)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(target.Close)
+
+	tun := New("test-slug", target.URL)
+	tun.KeepResponses()
+
+	result, err := tun.Forward(&types.CapturedRequest{Method: "POST", Path: "/"})
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	if result.StatusCode != 201 || string(result.Body) != `{"ok":true}` {
+		t.Errorf("unexpected result: %d %q", result.StatusCode, result.Body)
+	}
+	if result.Headers.Get("X-Local") != "yes" {
+		t.Errorf("expected X-Local header, got %v", result.Headers)
+	}
+}
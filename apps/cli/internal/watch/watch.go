@@ -0,0 +1,164 @@
+// Package watch keeps track of the background watchers started by
+// `whk watch`, at most one per endpoint, so they can be listed and
+// stopped. Each watcher has a record, watch/<slug>.json in the whk config
+// directory, and writes its output to watch/<slug>.log next to it.
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"webhooks.cc/cli/internal/auth"
+	"webhooks.cc/cli/internal/sessions"
+)
+
+const watchDir = "watch"
+
+// Watcher is a running `whk watch` process.
+type Watcher struct {
+	Slug      string `json:"slug"`
+	PID       int    `json:"pid"`
+	StartedAt int64  `json:"startedAt"`
+	Notify    bool   `json:"notify,omitempty"`
+	// Query is the search query requests must match, if any
+	Query string `json:"query,omitempty"`
+}
+
+// processAlive is a variable so tests can stub it.
+var processAlive = sessions.ProcessAlive
+
+// Dir returns the directory holding watcher records and logs.
+func Dir() (string, error) {
+	configPath, err := auth.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configPath, watchDir), nil
+}
+
+// LogPath returns the log file of the watcher for slug.
+func LogPath(slug string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, slug+".log"), nil
+}
+
+func recordPath(slug string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, slug+".json"), nil
+}
+
+// Record stores w, replacing the record of an earlier watcher of the same
+// endpoint.
+func Record(w Watcher) error {
+	path, err := recordPath(w.Slug)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return auth.WriteFileAtomic(path, data, 0600)
+}
+
+// Remove deletes the record for slug if it belongs to pid, so an exiting
+// watcher doesn't remove the record of one that replaced it.
+func Remove(slug string, pid int) error {
+	path, err := recordPath(slug)
+	if err != nil {
+		return err
+	}
+	if w, err := read(path); err != nil || w.PID != pid {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func read(path string) (*Watcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var w Watcher
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Get returns the running watcher for slug, or nil if there is none. The
+// record of a watcher that is no longer running is removed.
+func Get(slug string) (*Watcher, error) {
+	path, err := recordPath(slug)
+	if err != nil {
+		return nil, err
+	}
+	w, err := read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil || !processAlive(w.PID) {
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	return w, nil
+}
+
+// List returns the running watchers, sorted by slug.
+func List() ([]Watcher, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var watchers []Watcher
+	for _, e := range entries {
+		slug, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		w, err := Get(slug)
+		if err != nil {
+			return nil, err
+		}
+		if w != nil {
+			watchers = append(watchers, *w)
+		}
+	}
+	sort.Slice(watchers, func(i, j int) bool { return watchers[i].Slug < watchers[j].Slug })
+	return watchers, nil
+}
+
+// Stop asks the watcher to exit. On Windows, where processes can't be sent
+// a termination signal, it is killed.
+func (w Watcher) Stop() error {
+	p, err := os.FindProcess(w.PID)
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return p.Kill()
+	}
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func stubProcessAlive(t *testing.T, alive map[int]bool) {
+	t.Helper()
+	orig := processAlive
+	processAlive = func(pid int) bool { return alive[pid] }
+	t.Cleanup(func() { processAlive = orig })
+}
+
+func TestWatch_RecordGetList(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	stubProcessAlive(t, map[int]bool{100: true, 200: true})
+
+	for _, w := range []Watcher{
+		{Slug: "stripe-dev", PID: 200, Notify: true},
+		{Slug: "github-dev", PID: 100, Query: "method:POST"},
+		{Slug: "gone", PID: 300},
+	} {
+		if err := Record(w); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, ".config/whk", watchDir, "stripe-dev.json"))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected file permissions 0600, got %o", perm)
+	}
+
+	w, err := Get("stripe-dev")
+	if err != nil || w == nil || w.PID != 200 || !w.Notify {
+		t.Fatalf("Get(stripe-dev) = %+v, %v", w, err)
+	}
+
+	watchers, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(watchers) != 2 || watchers[0].Slug != "github-dev" || watchers[1].Slug != "stripe-dev" {
+		t.Fatalf("expected the two running watchers sorted by slug, got %+v", watchers)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".config/whk", watchDir, "gone.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the dead watcher's record to be removed, got %v", err)
+	}
+}
+
+func TestWatch_RemoveOnlyOwnRecord(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stubProcessAlive(t, map[int]bool{100: true, 200: true})
+
+	if err := Record(Watcher{Slug: "my-ep", PID: 200}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// An older watcher of the same endpoint exiting leaves the record alone
+	if err := Remove("my-ep", 100); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if w, _ := Get("my-ep"); w == nil || w.PID != 200 {
+		t.Fatalf("expected the record of pid 200 to remain, got %+v", w)
+	}
+	if err := Remove("my-ep", 200); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if w, _ := Get("my-ep"); w != nil {
+		t.Errorf("expected no watcher after Remove, got %+v", w)
+	}
+	if err := Remove("unknown", 1); err != nil {
+		t.Errorf("removing an unknown watcher should not fail, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+// Package bodyfmt recognizes structured request bodies that are opaque or
+// hard to read as raw text (GraphQL operations, gRPC-web frames, XML and
+// form-encoded payloads) so the CLI and dashboard can render them
+// meaningfully.
+package bodyfmt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// GraphQLOperation is a GraphQL-over-HTTP request.
+type GraphQLOperation struct {
+	// OperationName is the explicit operationName, or the name declared in
+	// the query when there is only one.
+	OperationName string
+	// OperationType is query, mutation or subscription.
+	OperationType string
+	Query         string
+	Variables     map[string]any
+}
+
+var operationPattern = regexp.MustCompile(`^\s*(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?`)
+
+// ParseGraphQL parses body as a GraphQL request. It accepts JSON bodies with
+// a "query" string (any JSON content type) and application/graphql bodies
+// holding the bare query. It reports false for anything else.
+func ParseGraphQL(contentType string, body []byte) (*GraphQLOperation, bool) {
+	mediaType := mediaType(contentType)
+
+	var op GraphQLOperation
+	switch {
+	case mediaType == "application/graphql":
+		op.Query = string(body)
+	case mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var payload struct {
+			Query         *string        `json:"query"`
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Query == nil {
+			return nil, false
+		}
+		op.Query = *payload.Query
+		op.OperationName = payload.OperationName
+		op.Variables = payload.Variables
+	default:
+		return nil, false
+	}
+
+	query := strings.TrimSpace(op.Query)
+	if query == "" {
+		return nil, false
+	}
+	if m := operationPattern.FindStringSubmatch(query); m != nil {
+		op.OperationType = m[1]
+		if op.OperationName == "" {
+			op.OperationName = m[2]
+		}
+	} else if strings.HasPrefix(query, "{") {
+		// Shorthand query without the keyword
+		op.OperationType = "query"
+	} else if !strings.HasPrefix(query, "fragment") {
+		return nil, false
+	}
+	return &op, true
+}
+
+// gRPC-web frame flags
+const (
+	grpcFlagCompressed = 0x01
+	grpcFlagTrailer    = 0x80
+)
+
+// GRPCFrame is one length-prefixed gRPC-web frame.
+type GRPCFrame struct {
+	Compressed bool
+	// Trailer frames carry the response status as HTTP/1 header lines.
+	Trailer bool
+	Data    []byte
+}
+
+// GRPCWebMessage is a decoded gRPC-web request.
+type GRPCWebMessage struct {
+	// Service and Method come from the /package.Service/Method path.
+	Service string
+	Method  string
+	Frames  []GRPCFrame
+}
+
+// ErrTruncatedFrame is returned when a gRPC-web frame is shorter than its
+// length prefix.
+var ErrTruncatedFrame = errors.New("truncated gRPC-web frame")
+
+// IsGRPCWeb reports whether contentType is a gRPC-web content type.
+func IsGRPCWeb(contentType string) bool {
+	return strings.HasPrefix(mediaType(contentType), "application/grpc-web")
+}
+
+// ParseGRPCWeb decodes a gRPC-web request body into its frames. Text-mode
+// bodies (application/grpc-web-text) are base64-decoded first.
+func ParseGRPCWeb(contentType, path string, body []byte) (*GRPCWebMessage, error) {
+	mt := mediaType(contentType)
+	if !strings.HasPrefix(mt, "application/grpc-web") {
+		return nil, errors.New("not a gRPC-web content type")
+	}
+	if strings.HasPrefix(mt, "application/grpc-web-text") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(body)))
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	msg := &GRPCWebMessage{}
+	if parts := strings.Split(strings.Trim(path, "/"), "/"); len(parts) >= 2 {
+		msg.Service = parts[len(parts)-2]
+		msg.Method = parts[len(parts)-1]
+	}
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, ErrTruncatedFrame
+		}
+		flags := body[0]
+		n := binary.BigEndian.Uint32(body[1:5])
+		if uint64(len(body)-5) < uint64(n) {
+			return nil, ErrTruncatedFrame
+		}
+		msg.Frames = append(msg.Frames, GRPCFrame{
+			Compressed: flags&grpcFlagCompressed != 0,
+			Trailer:    flags&grpcFlagTrailer != 0,
+			Data:       body[5 : 5+n],
+		})
+		body = body[5+n:]
+	}
+	return msg, nil
+}
+
+// IsXML reports whether a body with this content type should be treated as
+// XML. Bodies starting with an XML declaration count regardless of type,
+// since some providers send XML as text/plain.
+func IsXML(contentType string, body []byte) bool {
+	mt := mediaType(contentType)
+	if strings.HasSuffix(mt, "/xml") || strings.HasSuffix(mt, "+xml") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<?xml"))
+}
+
+// FormatXML re-indents an XML document with two spaces per level. Elements
+// holding only text stay on one line, and namespace prefixes are kept as
+// written. It returns an error if body is not well-formed.
+func FormatXML(body []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	var (
+		b     strings.Builder
+		depth int
+		// open is set while the current line ends with a start tag (or its
+		// inline text) whose element has no child elements yet
+		open bool
+	)
+	newline := func() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Repeat("  ", depth))
+	}
+
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			newline()
+			b.WriteString("<" + qualifiedName(t.Name))
+			for _, a := range t.Attr {
+				b.WriteString(" " + qualifiedName(a.Name) + `="`)
+				_ = xml.EscapeText(&b, []byte(a.Value))
+				b.WriteByte('"')
+			}
+			b.WriteByte('>')
+			depth++
+			open = true
+		case xml.EndElement:
+			if depth == 0 {
+				return "", errors.New("unexpected XML end element")
+			}
+			depth--
+			if !open {
+				newline()
+			}
+			b.WriteString("</" + qualifiedName(t.Name) + ">")
+			open = false
+		case xml.CharData:
+			text := bytes.TrimSpace(t)
+			if len(text) == 0 {
+				continue
+			}
+			if !open {
+				newline()
+			}
+			_ = xml.EscapeText(&b, text)
+		case xml.Comment:
+			newline()
+			b.WriteString("<!--" + string(t) + "-->")
+			open = false
+		case xml.ProcInst:
+			newline()
+			b.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+		case xml.Directive:
+			newline()
+			b.WriteString("<!" + string(t) + ">")
+		}
+	}
+	if depth != 0 {
+		return "", errors.New("unclosed XML element")
+	}
+	return b.String(), nil
+}
+
+func qualifiedName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// FormField is one decoded application/x-www-form-urlencoded pair.
+type FormField struct {
+	Name  string
+	Value string
+}
+
+// ParseForm decodes an application/x-www-form-urlencoded body into its
+// fields, in the order they were sent (which url.ParseQuery loses). It
+// reports false for other content types or malformed escapes.
+func ParseForm(contentType string, body []byte) ([]FormField, bool) {
+	if mediaType(contentType) != "application/x-www-form-urlencoded" {
+		return nil, false
+	}
+	var fields []FormField
+	for _, pair := range strings.Split(string(body), "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(name)
+		if err != nil {
+			return nil, false
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, false
+		}
+		fields = append(fields, FormField{Name: name, Value: value})
+	}
+	return fields, true
+}
+
+// mediaType returns the lowercased media type of a Content-Type value.
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mt))
+}
@@ -0,0 +1,185 @@
+package bodyfmt
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestParseGraphQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		ok          bool
+		opName      string
+		opType      string
+	}{
+		{"json with operationName", "application/json", `{"query":"query Q { viewer { id } }","operationName":"GetViewer","variables":{"id":1}}`, true, "GetViewer", "query"},
+		{"name from query", "application/json; charset=utf-8", `{"query":"mutation CreateUser($n: String!) { createUser(name: $n) { id } }"}`, true, "CreateUser", "mutation"},
+		{"shorthand", "", `{"query":"{ viewer { id } }"}`, true, "", "query"},
+		{"application/graphql", "application/graphql", "subscription OnEvent { event { id } }", true, "OnEvent", "subscription"},
+		{"plain json", "application/json", `{"event":"invoice.paid"}`, false, "", ""},
+		{"query field not graphql", "application/json", `{"query":"select * from users"}`, false, "", ""},
+		{"form body", "application/x-www-form-urlencoded", "query=x", false, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, ok := ParseGraphQL(tt.contentType, []byte(tt.body))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if op.OperationName != tt.opName || op.OperationType != tt.opType {
+				t.Errorf("got %q %q, want %q %q", op.OperationType, op.OperationName, tt.opType, tt.opName)
+			}
+		})
+	}
+}
+
+func TestParseGraphQL_Variables(t *testing.T) {
+	op, ok := ParseGraphQL("application/json", []byte(`{"query":"query { a }","variables":{"id":"cus_1"}}`))
+	if !ok || op.Variables["id"] != "cus_1" {
+		t.Errorf("unexpected operation: %+v", op)
+	}
+}
+
+func grpcFrame(flags byte, data string) []byte {
+	n := len(data)
+	return append([]byte{flags, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}, data...)
+}
+
+func TestParseGRPCWeb(t *testing.T) {
+	body := append(grpcFrame(0, "\x0a\x03abc"), grpcFrame(0x80, "grpc-status: 0\r\n")...)
+
+	msg, err := ParseGRPCWeb("application/grpc-web+proto", "/acme.billing.v1.Invoices/Create", body)
+	if err != nil {
+		t.Fatalf("ParseGRPCWeb: %v", err)
+	}
+	if msg.Service != "acme.billing.v1.Invoices" || msg.Method != "Create" {
+		t.Errorf("service/method = %q %q", msg.Service, msg.Method)
+	}
+	if len(msg.Frames) != 2 || msg.Frames[0].Trailer || !msg.Frames[1].Trailer {
+		t.Fatalf("unexpected frames: %+v", msg.Frames)
+	}
+	if string(msg.Frames[0].Data) != "\x0a\x03abc" {
+		t.Errorf("frame data = %q", msg.Frames[0].Data)
+	}
+}
+
+func TestParseGRPCWeb_Text(t *testing.T) {
+	body := base64.StdEncoding.EncodeToString(grpcFrame(1, "zz"))
+	msg, err := ParseGRPCWeb("application/grpc-web-text", "/svc.S/M", []byte(body))
+	if err != nil {
+		t.Fatalf("ParseGRPCWeb: %v", err)
+	}
+	if len(msg.Frames) != 1 || !msg.Frames[0].Compressed {
+		t.Errorf("unexpected frames: %+v", msg.Frames)
+	}
+}
+
+func TestParseGRPCWeb_Errors(t *testing.T) {
+	if _, err := ParseGRPCWeb("application/json", "/s/m", nil); err == nil {
+		t.Error("expected error for non-gRPC content type")
+	}
+	truncated := grpcFrame(0, "abcdef")[:7]
+	if _, err := ParseGRPCWeb("application/grpc-web", "/s/m", truncated); !errors.Is(err, ErrTruncatedFrame) {
+		t.Errorf("expected ErrTruncatedFrame, got %v", err)
+	}
+}
+
+func TestIsGRPCWeb(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"application/grpc-web":       true,
+		"application/grpc-web+proto": true,
+		"application/grpc-web-text":  true,
+		"application/grpc":           false,
+		"application/json":           false,
+		"":                           false,
+	} {
+		if got := IsGRPCWeb(ct); got != want {
+			t.Errorf("IsGRPCWeb(%q) = %v, want %v", ct, got, want)
+		}
+	}
+}
+
+func TestIsXML(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"application/xml", "<a/>", true},
+		{"text/xml; charset=utf-8", "<a/>", true},
+		{"application/soap+xml", "<a/>", true},
+		{"text/plain", "  <?xml version=\"1.0\"?><a/>", true},
+		{"application/json", `{"a":1}`, false},
+		{"text/html", "<html></html>", false},
+	}
+	for _, tt := range tests {
+		if got := IsXML(tt.contentType, []byte(tt.body)); got != tt.want {
+			t.Errorf("IsXML(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestFormatXML(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?><notification xmlns:pp="urn:paypal"><pp:event type="PAYMENT.CAPTURE"><id>WH-1</id><amount currency="USD">9.99</amount><empty></empty></pp:event><!-- sent by sandbox --></notification>`
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<notification xmlns:pp="urn:paypal">
+  <pp:event type="PAYMENT.CAPTURE">
+    <id>WH-1</id>
+    <amount currency="USD">9.99</amount>
+    <empty></empty>
+  </pp:event>
+  <!-- sent by sandbox -->
+</notification>`
+
+	got, err := FormatXML([]byte(body))
+	if err != nil {
+		t.Fatalf("FormatXML: %v", err)
+	}
+	if got != want {
+		t.Errorf("FormatXML:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatXML_Malformed(t *testing.T) {
+	for _, body := range []string{"<a><b></a>", "<a>", "</a>", "not xml <"} {
+		if _, err := FormatXML([]byte(body)); err == nil {
+			t.Errorf("expected error for %q", body)
+		}
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	body := "To=%2B15551234567&From=%2B15557654321&Body=Hello+world&Empty=&Flag"
+	fields, ok := ParseForm("application/x-www-form-urlencoded; charset=utf-8", []byte(body))
+	if !ok {
+		t.Fatal("expected form body to parse")
+	}
+	want := []FormField{
+		{"To", "+15551234567"},
+		{"From", "+15557654321"},
+		{"Body", "Hello world"},
+		{"Empty", ""},
+		{"Flag", ""},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields, want %d: %+v", len(fields), len(want), fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, fields[i], want[i])
+		}
+	}
+
+	if _, ok := ParseForm("application/json", []byte(body)); ok {
+		t.Error("expected non-form content type to be rejected")
+	}
+	if _, ok := ParseForm("application/x-www-form-urlencoded", []byte("a=%zz")); ok {
+		t.Error("expected malformed escape to be rejected")
+	}
+}
@@ -0,0 +1,187 @@
+// Package capturesig signs and verifies the capture batches the receiver
+// posts to the backend. The bearer token alone only proves the sender knows
+// the shared secret; a signature additionally binds each batch to its body,
+// a timestamp and a single-use nonce, with a separate signing secret, so a
+// leaked bearer secret can't be used to forge or replay capture traffic.
+//
+// The signature is hex(HMAC-SHA256(secret, "v1:" + timestamp + ":" + nonce +
+// ":" + body)), sent as "v1=<hex>" in SignatureHeader alongside
+// TimestampHeader (Unix seconds) and NonceHeader. SignatureHeader may carry
+// several comma-separated signatures while a secret is being rotated.
+package capturesig
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers carrying a capture batch signature.
+const (
+	TimestampHeader = "X-Capture-Timestamp"
+	NonceHeader     = "X-Capture-Nonce"
+	SignatureHeader = "X-Capture-Signature"
+)
+
+const (
+	// Version prefixes signatures and the signed payload.
+	Version = "v1"
+	// DefaultTolerance is how far a batch's timestamp may be from the
+	// verifier's clock.
+	DefaultTolerance = 5 * time.Minute
+	// nonceBytes is the size of generated nonces before hex encoding
+	nonceBytes = 16
+	// maxNonceLen rejects oversized nonces before they reach the cache
+	maxNonceLen = 64
+)
+
+// Verification errors. Verify wraps them with details.
+var (
+	ErrMissingSignature = errors.New("missing capture signature headers")
+	ErrStaleTimestamp   = errors.New("capture timestamp outside tolerance")
+	ErrBadSignature     = errors.New("capture signature mismatch")
+	ErrReplayed         = errors.New("capture nonce already used")
+)
+
+// Sign returns the "v1=<hex>" signature of a batch body.
+func Sign(secret []byte, timestamp int64, nonce string, body []byte) string {
+	return Version + "=" + hex.EncodeToString(mac(secret, timestamp, nonce, body))
+}
+
+func mac(secret []byte, timestamp int64, nonce string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	fmt.Fprintf(h, "%s:%d:%s:", Version, timestamp, nonce)
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// NewNonce returns a random hex nonce.
+func NewNonce() (string, error) {
+	b := make([]byte, nonceBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SignRequest sets the signature headers for a batch body sent at now.
+func SignRequest(header http.Header, secret, body []byte, now time.Time) error {
+	nonce, err := NewNonce()
+	if err != nil {
+		return err
+	}
+	ts := now.Unix()
+	header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	header.Set(NonceHeader, nonce)
+	header.Set(SignatureHeader, Sign(secret, ts, nonce, body))
+	return nil
+}
+
+// Verifier checks signed capture batches.
+type Verifier struct {
+	// Secrets are tried in order; any match is accepted, so a new secret
+	// can be deployed to verifiers before senders switch to it.
+	Secrets [][]byte
+	// Tolerance bounds clock skew and delivery delay; 0 means
+	// DefaultTolerance.
+	Tolerance time.Duration
+	// Nonces rejects replayed batches when set.
+	Nonces *NonceCache
+	// Now returns the current time; nil means time.Now.
+	Now func() time.Time
+}
+
+// Verify checks a batch's signature headers against its body.
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	tsHeader := header.Get(TimestampHeader)
+	nonce := header.Get(NonceHeader)
+	sigHeader := header.Get(SignatureHeader)
+	if tsHeader == "" || nonce == "" || sigHeader == "" {
+		return ErrMissingSignature
+	}
+	if len(nonce) > maxNonceLen {
+		return fmt.Errorf("%w: nonce too long", ErrBadSignature)
+	}
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrStaleTimestamp, tsHeader)
+	}
+
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	sent := time.Unix(ts, 0)
+	if skew := now.Sub(sent).Abs(); skew > tolerance {
+		return fmt.Errorf("%w: off by %s", ErrStaleTimestamp, skew.Round(time.Second))
+	}
+
+	if !v.matches(ts, nonce, sigHeader, body) {
+		return ErrBadSignature
+	}
+	// Record the nonce only once the signature is known to be genuine, so
+	// forged requests can't fill the cache. It must be remembered for as
+	// long as the timestamp would still be accepted.
+	if v.Nonces != nil && !v.Nonces.Add(nonce, now, sent.Add(tolerance)) {
+		return ErrReplayed
+	}
+	return nil
+}
+
+func (v *Verifier) matches(ts int64, nonce, sigHeader string, body []byte) bool {
+	for _, secret := range v.Secrets {
+		expected := mac(secret, ts, nonce, body)
+		for _, sig := range strings.Split(sigHeader, ",") {
+			hexSig, ok := strings.CutPrefix(strings.TrimSpace(sig), Version+"=")
+			if !ok {
+				continue
+			}
+			got, err := hex.DecodeString(hexSig)
+			if err == nil && hmac.Equal(got, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NonceCache remembers nonces until they expire. It is safe for concurrent
+// use.
+type NonceCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+// NewNonceCache creates an empty NonceCache.
+func NewNonceCache() *NonceCache {
+	return &NonceCache{nonces: map[string]time.Time{}}
+}
+
+// Add records a nonce until expires. It returns false if the nonce is
+// already recorded and hasn't expired by now.
+func (c *NonceCache) Add(nonce string, now, expires time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if exp, ok := c.nonces[nonce]; ok && now.Before(exp) {
+		return false
+	}
+	for n, exp := range c.nonces {
+		if !now.Before(exp) {
+			delete(c.nonces, n)
+		}
+	}
+	c.nonces[nonce] = expires
+	return true
+}
@@ -0,0 +1,138 @@
+package capturesig
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	secret = []byte("signing-secret")
+	now    = time.Unix(1_700_000_000, 0)
+)
+
+func signed(t *testing.T, body []byte) http.Header {
+	t.Helper()
+	h := http.Header{}
+	if err := SignRequest(h, secret, body, now); err != nil {
+		t.Fatalf("SignRequest: %v", err)
+	}
+	return h
+}
+
+func verifier() *Verifier {
+	return &Verifier{Secrets: [][]byte{secret}, Nonces: NewNonceCache(), Now: func() time.Time { return now }}
+}
+
+func TestSign_KnownValue(t *testing.T) {
+	// printf 'v1:1700000000:abc:{"a":1}' | openssl dgst -sha256 -hmac signing-secret
+	got := Sign(secret, 1_700_000_000, "abc", []byte(`{"a":1}`))
+	want := "v1=0551d33dd258dbb7b6012a117e91d8cd01cc3b6279d9719c9ca2509b619e9625"
+	if got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+	if Sign(secret, 1_700_000_000, "abd", []byte(`{"a":1}`)) == got {
+		t.Error("the nonce should be signed")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`[{"method":"POST"}]`)
+	h := signed(t, body)
+	if err := verifier().Verify(h, body); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if err := verifier().Verify(h, []byte(`[{"method":"GET"}]`)); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("tampered body: got %v, want ErrBadSignature", err)
+	}
+
+	other := &Verifier{Secrets: [][]byte{[]byte("bearer-secret")}, Now: func() time.Time { return now }}
+	if err := other.Verify(h, body); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("wrong secret: got %v, want ErrBadSignature", err)
+	}
+
+	forged := h.Clone()
+	forged.Set(TimestampHeader, strconv.FormatInt(now.Unix()+1, 10))
+	if err := verifier().Verify(forged, body); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("changed timestamp: got %v, want ErrBadSignature", err)
+	}
+
+	for _, name := range []string{TimestampHeader, NonceHeader, SignatureHeader} {
+		missing := h.Clone()
+		missing.Del(name)
+		if err := verifier().Verify(missing, body); !errors.Is(err, ErrMissingSignature) {
+			t.Errorf("without %s: got %v, want ErrMissingSignature", name, err)
+		}
+	}
+}
+
+func TestVerify_Tolerance(t *testing.T) {
+	body := []byte("batch")
+	h := signed(t, body)
+
+	late := verifier()
+	late.Now = func() time.Time { return now.Add(DefaultTolerance + time.Second) }
+	if err := late.Verify(h, body); !errors.Is(err, ErrStaleTimestamp) {
+		t.Errorf("late batch: got %v, want ErrStaleTimestamp", err)
+	}
+
+	early := verifier()
+	early.Now = func() time.Time { return now.Add(-time.Minute) }
+	if err := early.Verify(h, body); err != nil {
+		t.Errorf("small clock skew should be accepted: %v", err)
+	}
+}
+
+func TestVerify_Replay(t *testing.T) {
+	body := []byte("batch")
+	h := signed(t, body)
+	v := verifier()
+	if err := v.Verify(h, body); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := v.Verify(h, body); !errors.Is(err, ErrReplayed) {
+		t.Errorf("replayed batch: got %v, want ErrReplayed", err)
+	}
+}
+
+func TestVerify_RotatedSecrets(t *testing.T) {
+	body := []byte("batch")
+	newSecret := []byte("new-secret")
+
+	// A verifier that knows both secrets accepts either
+	v := verifier()
+	v.Secrets = [][]byte{newSecret, secret}
+	if err := v.Verify(signed(t, body), body); err != nil {
+		t.Errorf("old secret during rotation: %v", err)
+	}
+
+	// A sender may send signatures for both secrets
+	h := signed(t, body)
+	ts, _ := strconv.ParseInt(h.Get(TimestampHeader), 10, 64)
+	h.Set(SignatureHeader, Sign(newSecret, ts, h.Get(NonceHeader), body)+", "+h.Get(SignatureHeader))
+	v = verifier()
+	v.Secrets = [][]byte{secret}
+	if err := v.Verify(h, body); err != nil {
+		t.Errorf("multiple signatures: %v", err)
+	}
+}
+
+func TestNonceCache_Expires(t *testing.T) {
+	c := NewNonceCache()
+	if !c.Add("n1", now, now.Add(time.Minute)) {
+		t.Fatal("first use should be accepted")
+	}
+	if c.Add("n1", now.Add(30*time.Second), now.Add(time.Minute)) {
+		t.Error("reuse before expiry should be refused")
+	}
+	later := now.Add(2 * time.Minute)
+	if !c.Add("n1", later, later.Add(time.Minute)) {
+		t.Error("an expired nonce may be recorded again")
+	}
+	if len(c.nonces) != 1 {
+		t.Errorf("expired nonces should be pruned, have %d", len(c.nonces))
+	}
+}
@@ -0,0 +1,171 @@
+// Package pathpattern matches captured request paths against the path
+// patterns configured on an endpoint, such as /orders/:id/events, and
+// extracts the named segments as path parameters. Paths are relative to the
+// endpoint, i.e. the part after /w/{slug}.
+//
+// A pattern is a list of /-separated segments. A literal segment must match
+// exactly, :name matches any single non-empty segment, and a final *name (or
+// a bare *) matches the rest of the path, including further slashes.
+// Trailing slashes are ignored on both sides.
+package pathpattern
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// MaxPatterns is the maximum number of path patterns per endpoint.
+	MaxPatterns = 20
+	// MaxPatternLen is the maximum length of a single pattern.
+	MaxPatternLen = 256
+	// Wildcard is the parameter name used for a bare * segment.
+	Wildcard = "*"
+)
+
+// Pattern is a parsed path pattern.
+type Pattern struct {
+	raw      string
+	segments []segment
+}
+
+type segment struct {
+	literal string
+	param   string
+	rest    bool
+}
+
+// Parse parses a path pattern.
+func Parse(s string) (*Pattern, error) {
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("path pattern must start with /: %s", s)
+	}
+	if len(s) > MaxPatternLen {
+		return nil, fmt.Errorf("path pattern is longer than %d characters", MaxPatternLen)
+	}
+
+	p := &Pattern{raw: s}
+	seen := make(map[string]bool)
+	parts := split(s)
+	for i, part := range parts {
+		var seg segment
+		switch {
+		case part == "":
+			return nil, fmt.Errorf("path pattern has an empty segment: %s", s)
+		case part == Wildcard:
+			seg = segment{param: Wildcard, rest: true}
+		case strings.HasPrefix(part, ":"), strings.HasPrefix(part, "*"):
+			name := part[1:]
+			if !validName(name) {
+				return nil, fmt.Errorf("invalid parameter name %q in %s", name, s)
+			}
+			seg = segment{param: name, rest: part[0] == '*'}
+		default:
+			seg = segment{literal: part}
+		}
+		if seg.rest && i != len(parts)-1 {
+			return nil, fmt.Errorf("wildcard must be the last segment: %s", s)
+		}
+		if seg.param != "" {
+			if seen[seg.param] {
+				return nil, fmt.Errorf("duplicate parameter %q in %s", seg.param, s)
+			}
+			seen[seg.param] = true
+		}
+		p.segments = append(p.segments, seg)
+	}
+	return p, nil
+}
+
+// ParseAll parses a list of patterns, as stored on an endpoint.
+func ParseAll(patterns []string) ([]*Pattern, error) {
+	if len(patterns) > MaxPatterns {
+		return nil, fmt.Errorf("too many path patterns (max %d)", MaxPatterns)
+	}
+	parsed := make([]*Pattern, 0, len(patterns))
+	for _, s := range patterns {
+		p, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+// String returns the pattern as it was written.
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Match reports whether path matches the pattern and returns its named
+// parameters, unescaped. A query string on path is ignored.
+func (p *Pattern) Match(path string) (map[string]string, bool) {
+	path, _, _ = strings.Cut(path, "?")
+	parts := split(path)
+	params := make(map[string]string)
+	for i, seg := range p.segments {
+		if seg.rest {
+			params[seg.param] = unescape(strings.Join(parts[i:], "/"))
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		switch {
+		case seg.param != "":
+			if parts[i] == "" {
+				return nil, false
+			}
+			params[seg.param] = unescape(parts[i])
+		case seg.literal != parts[i]:
+			return nil, false
+		}
+	}
+	if len(parts) != len(p.segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+// Extract returns the parameters of the first pattern that matches path, or
+// nil if none does. Patterns are tried in order, so list specific patterns
+// before general ones.
+func Extract(patterns []*Pattern, path string) map[string]string {
+	for _, p := range patterns {
+		if params, ok := p.Match(path); ok {
+			return params
+		}
+	}
+	return nil
+}
+
+// split returns the segments of a path without its leading and trailing
+// slashes. The root path has no segments.
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func unescape(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package pathpattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"orders/:id",
+		"/orders//:id",
+		"/orders/:",
+		"/orders/:id/:id",
+		"/files/*/meta",
+		"/orders/:i.d",
+	}
+	for _, s := range tests {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected error", s)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		ok      bool
+		params  map[string]string
+	}{
+		{"/orders/:id/events", "/orders/42/events", true, map[string]string{"id": "42"}},
+		{"/orders/:id/events", "/orders/42/events/", true, map[string]string{"id": "42"}},
+		{"/orders/:id/events", "/orders/42/events?x=1", true, map[string]string{"id": "42"}},
+		{"/orders/:id/events", "/orders/42", false, nil},
+		{"/orders/:id/events", "/orders/42/events/extra", false, nil},
+		{"/orders/:id/events", "/customers/42/events", false, nil},
+		{"/orders/:id", "/orders/a%20b", true, map[string]string{"id": "a b"}},
+		{"/:tenant/:kind", "/acme/invoice", true, map[string]string{"tenant": "acme", "kind": "invoice"}},
+		{"/files/*path", "/files/a/b/c.txt", true, map[string]string{"path": "a/b/c.txt"}},
+		{"/files/*", "/files", true, map[string]string{"*": ""}},
+		{"/files/*", "/other", false, nil},
+		{"/", "/", true, map[string]string{}},
+		{"/", "/orders", false, nil},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.pattern)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.pattern, err)
+		}
+		params, ok := p.Match(tt.path)
+		if ok != tt.ok {
+			t.Errorf("%s matching %s: expected ok=%v, got %v", tt.pattern, tt.path, tt.ok, ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s matching %s: expected %v, got %v", tt.pattern, tt.path, tt.params, params)
+		}
+	}
+}
+
+func TestExtract_FirstMatchWins(t *testing.T) {
+	patterns, err := ParseAll([]string{"/orders/latest", "/orders/:id", "/*rest"})
+	if err != nil {
+		t.Fatalf("ParseAll: %v", err)
+	}
+
+	if got := Extract(patterns, "/orders/latest"); len(got) != 0 || got == nil {
+		t.Errorf("expected empty params from literal match, got %v", got)
+	}
+	if got := Extract(patterns, "/orders/7"); got["id"] != "7" {
+		t.Errorf("expected id=7, got %v", got)
+	}
+	if got := Extract(patterns, "/misc/path"); got["rest"] != "misc/path" {
+		t.Errorf("expected rest=misc/path, got %v", got)
+	}
+	if got := Extract(nil, "/orders/7"); got != nil {
+		t.Errorf("expected nil without patterns, got %v", got)
+	}
+}
@@ -0,0 +1,328 @@
+// Package search implements the request search language shared by the CLI,
+// the TUI and the API. A query is a list of space-separated terms that must
+// all match:
+//
+//	method:POST path:/stripe/* header.x-event-type:invoice.* body:"customer_123"
+//
+// Terms are field:value pairs or bare words. Values may be double-quoted and
+// may use * as a wildcard; a leading - negates a term. Supported fields are
+// method, path, ip, body, status, tag, header.<name>, query.<name> and
+// param.<name>, the latter matching path parameters extracted by the
+// endpoint's path patterns (see package pathpattern). tag matches if any of
+// the request's tags does. Methods, tags and header names are
+// case-insensitive; body and bare words match substrings, every other field
+// must match the whole value. status also accepts a comparison such as
+// status:>=400.
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"webhooks.cc/shared/types"
+)
+
+// Term fields
+const (
+	FieldText   = ""
+	FieldMethod = "method"
+	FieldPath   = "path"
+	FieldIP     = "ip"
+	FieldBody   = "body"
+	FieldStatus = "status"
+	FieldHeader = "header"
+	FieldQuery  = "query"
+	FieldParam  = "param"
+	FieldTag    = "tag"
+)
+
+// Term is one condition of a query.
+type Term struct {
+	// Field is one of the Field constants; FieldText for bare words.
+	Field string
+	// Key is the header, query or path parameter name for those fields.
+	Key string
+	// Op is a comparison (<, <=, >, >=) for status terms, or empty.
+	Op     string
+	Value  string
+	Negate bool
+}
+
+// Query is a parsed search. The zero Query matches every request.
+type Query struct {
+	Terms []Term
+}
+
+// Parse parses a search query. An empty string yields an empty query.
+func Parse(s string) (*Query, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for _, tok := range tokens {
+		term := Term{Negate: tok.negate, Value: tok.value}
+		if !tok.hasField && tok.value == "" {
+			return nil, fmt.Errorf("empty search term")
+		}
+		if tok.hasField {
+			field, key, _ := strings.Cut(tok.field, ".")
+			field = strings.ToLower(field)
+			switch field {
+			case FieldMethod, FieldPath, FieldIP, FieldBody, FieldStatus, FieldTag:
+				if key != "" {
+					return nil, fmt.Errorf("field %s does not take a name", field)
+				}
+			case FieldHeader, FieldQuery, FieldParam:
+				if key == "" {
+					return nil, fmt.Errorf("%s needs a name, e.g. %s.x-event-type:value", field, field)
+				}
+			default:
+				return nil, fmt.Errorf("unknown field: %s", tok.field)
+			}
+			if tok.value == "" {
+				return nil, fmt.Errorf("missing value for %s", tok.field)
+			}
+			term.Field = field
+			term.Key = key
+			if field == FieldHeader {
+				term.Key = strings.ToLower(key)
+			}
+			if field == FieldTag {
+				term.Value = strings.ToLower(term.Value)
+			}
+			if field == FieldStatus {
+				if err := parseStatus(&term); err != nil {
+					return nil, err
+				}
+			}
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	return q, nil
+}
+
+// Empty reports whether the query has no terms.
+func (q *Query) Empty() bool {
+	return q == nil || len(q.Terms) == 0
+}
+
+// Match reports whether req satisfies every term.
+func (q *Query) Match(req *types.CapturedRequest) bool {
+	if q == nil {
+		return true
+	}
+	for _, t := range q.Terms {
+		if t.match(req) == t.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the query in canonical form, suitable for sending to the API.
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+	parts := make([]string, len(q.Terms))
+	for i, t := range q.Terms {
+		var b strings.Builder
+		if t.Negate {
+			b.WriteByte('-')
+		}
+		if t.Field != FieldText {
+			b.WriteString(t.Field)
+			if t.Key != "" {
+				b.WriteString("." + t.Key)
+			}
+			b.WriteByte(':')
+		}
+		b.WriteString(t.Op + quote(t.Value))
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func (t Term) match(req *types.CapturedRequest) bool {
+	switch t.Field {
+	case FieldMethod:
+		return matchGlob(strings.ToUpper(t.Value), strings.ToUpper(req.Method))
+	case FieldPath:
+		return matchGlob(t.Value, req.Path)
+	case FieldIP:
+		return matchGlob(t.Value, req.IP)
+	case FieldBody:
+		return bodyContains(req, t.Value)
+	case FieldStatus:
+		return matchStatus(t, req.ResponseStatus)
+	case FieldTag:
+		for _, tag := range req.Tags {
+			if matchGlob(t.Value, strings.ToLower(tag)) {
+				return true
+			}
+		}
+		return false
+	case FieldHeader:
+		for _, v := range req.HeaderValues(t.Key) {
+			if matchGlob(t.Value, v) {
+				return true
+			}
+		}
+		return false
+	case FieldQuery:
+		v, ok := req.QueryParams[t.Key]
+		return ok && matchGlob(t.Value, v)
+	case FieldParam:
+		v, ok := req.PathParams[t.Key]
+		return ok && matchGlob(t.Value, v)
+	default:
+		return matchGlob("*"+t.Value+"*", req.Path) || bodyContains(req, t.Value)
+	}
+}
+
+// parseStatus splits a comparison operator off a status term and checks
+// that the value is a status code (or a glob such as 4*).
+func parseStatus(t *Term) error {
+	for _, op := range []string{"<=", ">=", "<", ">"} {
+		if rest, ok := strings.CutPrefix(t.Value, op); ok {
+			t.Op, t.Value = op, rest
+			break
+		}
+	}
+	if t.Op == "" && strings.Contains(t.Value, "*") {
+		return nil
+	}
+	if _, err := strconv.Atoi(t.Value); err != nil {
+		return fmt.Errorf("invalid status: %s", t.Op+t.Value)
+	}
+	return nil
+}
+
+// matchStatus compares a response status against a status term. Requests
+// without a recorded status never match.
+func matchStatus(t Term, status int) bool {
+	if status == 0 {
+		return false
+	}
+	want, _ := strconv.Atoi(t.Value)
+	switch t.Op {
+	case "<":
+		return status < want
+	case "<=":
+		return status <= want
+	case ">":
+		return status > want
+	case ">=":
+		return status >= want
+	default:
+		return matchGlob(t.Value, strconv.Itoa(status))
+	}
+}
+
+func bodyContains(req *types.CapturedRequest, pattern string) bool {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return false
+	}
+	return matchGlob("*"+pattern+"*", string(body))
+}
+
+// matchGlob reports whether s matches pattern in full, where * matches any
+// run of characters (including /).
+func matchGlob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+type token struct {
+	field    string
+	value    string
+	hasField bool
+	negate   bool
+}
+
+// tokenize splits s on unquoted whitespace. The first unquoted colon in a
+// token separates the field from the value; quotes and backslash escapes
+// inside quotes are removed.
+func tokenize(s string) ([]token, error) {
+	var (
+		tokens []token
+		cur    strings.Builder
+		tok    token
+		inTok  bool
+		quoted bool
+	)
+	flush := func() {
+		if !inTok {
+			return
+		}
+		tok.value = cur.String()
+		tokens = append(tokens, tok)
+		tok = token{}
+		cur.Reset()
+		inTok = false
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quoted && r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+		case quoted && r == '"':
+			quoted = false
+		case quoted:
+			cur.WriteRune(r)
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '"':
+			inTok = true
+			quoted = true
+		case r == '-' && !inTok:
+			inTok = true
+			tok.negate = true
+		case r == ':' && !tok.hasField && cur.Len() > 0:
+			tok.field = cur.String()
+			tok.hasField = true
+			cur.Reset()
+		default:
+			inTok = true
+			cur.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// quote wraps v in double quotes if the tokenizer would otherwise split or
+// reinterpret it.
+func quote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n\"\\:") && !strings.HasPrefix(v, "-") {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
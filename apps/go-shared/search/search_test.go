@@ -0,0 +1,192 @@
+package search
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"webhooks.cc/shared/types"
+)
+
+func testRequest() *types.CapturedRequest {
+	return &types.CapturedRequest{
+		Method:      "POST",
+		Path:        "/stripe/webhook",
+		IP:          "10.0.0.7",
+		Headers:     map[string]string{"X-Event-Type": "invoice.paid", "Stripe-Signature": "t=1,v1=abc"},
+		QueryParams: map[string]string{"env": "staging"},
+		PathParams:  map[string]string{"account": "acct_42"},
+		Body:        `{"customer":"customer_123","amount":2000}`,
+
+		ResponseStatus: 502,
+		Tags:           []string{"bug-1234", "stripe"},
+	}
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`method:POST path:/stripe/* header.X-Event-Type:invoice.* body:"customer 123" -ip:10.* refund`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Term{
+		{Field: FieldMethod, Value: "POST"},
+		{Field: FieldPath, Value: "/stripe/*"},
+		{Field: FieldHeader, Key: "x-event-type", Value: "invoice.*"},
+		{Field: FieldBody, Value: "customer 123"},
+		{Field: FieldIP, Value: "10.*", Negate: true},
+		{Field: FieldText, Value: "refund"},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("got %d terms, want %d: %+v", len(q.Terms), len(want), q.Terms)
+	}
+	for i := range want {
+		if q.Terms[i] != want[i] {
+			t.Errorf("term %d = %+v, want %+v", i, q.Terms[i], want[i])
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, s := range []string{
+		`status:abc`,
+		`status:>=`,
+		`header:foo`,
+		`method.x:POST`,
+		`method:`,
+		`body:"unterminated`,
+		`-`,
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected error", s)
+		}
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	q, err := Parse("   ")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !q.Empty() || !q.Match(testRequest()) {
+		t.Error("empty query should match everything")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`method:post`, true},
+		{`method:GET`, false},
+		{`method:P*`, true},
+		{`path:/stripe/*`, true},
+		{`path:/stripe`, false},
+		{`path:*/webhook`, true},
+		{`header.x-event-type:invoice.*`, true},
+		{`header.stripe-signature:*`, true},
+		{`header.x-missing:*`, false},
+		{`-header.x-missing:*`, true},
+		{`query.env:staging`, true},
+		{`query.env:prod`, false},
+		{`param.account:acct_*`, true},
+		{`param.account:acct_7`, false},
+		{`param.missing:*`, false},
+		{`body:customer_123`, true},
+		{`body:"amount\":2000"`, true},
+		{`body:customer_999`, false},
+		{`ip:10.0.*`, true},
+		{`tag:bug-1234`, true},
+		{`tag:BUG-*`, true},
+		{`tag:bug`, false},
+		{`tag:*`, true},
+		{`-tag:stripe`, false},
+		{`stripe`, true},
+		{`customer_123`, true},
+		{`nothing-here`, false},
+		{`method:POST -path:/stripe/*`, false},
+		{`status:502`, true},
+		{`status:5*`, true},
+		{`status:>=400`, true},
+		{`status:<400`, false},
+		{`status:>502`, false},
+		{`status:<=502`, true},
+	}
+	req := testRequest()
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		if got := q.Match(req); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatch_EncodedBodies(t *testing.T) {
+	req := testRequest()
+	req.Body = base64.StdEncoding.EncodeToString([]byte("binary customer_123"))
+	req.BodyEncoding = types.BodyEncodingBase64
+	q, _ := Parse(`body:customer_123`)
+	if !q.Match(req) {
+		t.Error("expected base64 body to be decoded before matching")
+	}
+}
+
+func TestMatch_NoStatus(t *testing.T) {
+	req := testRequest()
+	req.ResponseStatus = 0
+	q, _ := Parse(`status:<400`)
+	if q.Match(req) {
+		t.Error("requests without a recorded status should not match status terms")
+	}
+}
+
+func TestString_RoundTrip(t *testing.T) {
+	for _, s := range []string{
+		`method:POST path:/stripe/*`,
+		`body:"customer 123" -header.x-event-type:invoice.*`,
+		`body:"say \"hi\"" "a:b"`,
+		`status:>=400 -status:5*`,
+		`tag:bug-1234 -tag:env:*`,
+	} {
+		q, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		again, err := Parse(q.String())
+		if err != nil {
+			t.Fatalf("Parse(String()) = %q: %v", q.String(), err)
+		}
+		if again.String() != q.String() || len(again.Terms) != len(q.Terms) {
+			t.Errorf("round trip of %q: %q != %q", s, again.String(), q.String())
+		}
+		for i := range q.Terms {
+			if again.Terms[i] != q.Terms[i] {
+				t.Errorf("round trip of %q: term %d = %+v, want %+v", s, i, again.Terms[i], q.Terms[i])
+			}
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "abcd", false},
+		{"*", "", true},
+		{"a*c", "abbbc", true},
+		{"a*c", "abcd", false},
+		{"*b*", "abc", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxcyyb", false},
+		{"/a/*/c", "/a/b/d/c", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
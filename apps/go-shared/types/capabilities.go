@@ -0,0 +1,46 @@
+package types
+
+import "slices"
+
+// APIVersion is the version of the API wire format this code speaks. The
+// CLI sends it in the APIVersionHeader of every request, so the server can
+// keep answering older clients in the format they understand.
+const (
+	APIVersion       = "1"
+	APIVersionHeader = "Accept-Version"
+)
+
+// Optional server features listed in Capabilities. New API surfaces ship
+// at different times on different deployments, so clients check for them
+// before relying on them.
+const (
+	// FeatureStreamMultiplex is one SSE connection streaming several
+	// endpoints.
+	FeatureStreamMultiplex = "stream-multiplex"
+	// FeaturePagination is cursor pagination of request history.
+	FeaturePagination = "pagination"
+	// FeatureReplay is server-side replay of captured requests.
+	FeatureReplay = "replay"
+)
+
+// Capabilities is what a server reports about itself at /api/capabilities.
+type Capabilities struct {
+	// Versions are the wire format versions the server accepts in
+	// APIVersionHeader.
+	Versions []string `json:"versions"`
+	Features []string `json:"features"`
+}
+
+// Has reports whether the server supports feature.
+func (c *Capabilities) Has(feature string) bool {
+	return c != nil && slices.Contains(c.Features, feature)
+}
+
+// Supports reports whether the server accepts wire format version. A
+// server that lists no versions predates versioning and speaks version 1.
+func (c *Capabilities) Supports(version string) bool {
+	if c == nil || len(c.Versions) == 0 {
+		return version == "1"
+	}
+	return slices.Contains(c.Versions, version)
+}
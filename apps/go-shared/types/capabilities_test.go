@@ -0,0 +1,39 @@
+package types
+
+import "testing"
+
+func TestCapabilities_Has(t *testing.T) {
+	c := &Capabilities{Features: []string{FeaturePagination}}
+	if !c.Has(FeaturePagination) {
+		t.Error("expected pagination to be supported")
+	}
+	if c.Has(FeatureReplay) {
+		t.Error("expected replay not to be supported")
+	}
+	var none *Capabilities
+	if none.Has(FeaturePagination) {
+		t.Error("nil capabilities should support nothing")
+	}
+}
+
+func TestCapabilities_Supports(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     *Capabilities
+		version  string
+		expected bool
+	}{
+		{"listed", &Capabilities{Versions: []string{"1", "2"}}, "2", true},
+		{"not listed", &Capabilities{Versions: []string{"2"}}, "1", false},
+		{"unversioned server speaks 1", &Capabilities{}, "1", true},
+		{"unversioned server", &Capabilities{}, "2", false},
+		{"nil", nil, "1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.caps.Supports(tt.version); got != tt.expected {
+				t.Errorf("Supports(%q) = %v, want %v", tt.version, got, tt.expected)
+			}
+		})
+	}
+}
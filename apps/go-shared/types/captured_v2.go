@@ -0,0 +1,161 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaV2 is the version carried by v2 captured request payloads.
+const SchemaV2 = 2
+
+// Body encodings used by BodyEncoding. An empty encoding means UTF-8 text.
+const (
+	BodyEncodingUTF8   = "utf8"
+	BodyEncodingBase64 = "base64"
+)
+
+// HeaderField is a single header line. Repeated headers (Set-Cookie,
+// X-Forwarded-For chains) appear as separate fields in received order.
+type HeaderField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CapturedRequestV2 is the v2 wire format for a captured webhook request.
+// Unlike v1 it keeps repeated headers and can carry binary bodies as base64.
+// Every other field is shared with CapturedRequest; its Headers and
+// HeaderFields are replaced by the header list.
+type CapturedRequestV2 struct {
+	Version int `json:"version"`
+	CapturedRequest
+	Headers []HeaderField `json:"headers"`
+}
+
+// DecodeCapturedRequest decodes a captured request payload in either the v1
+// or v2 wire format.
+func DecodeCapturedRequest(data []byte) (*CapturedRequest, error) {
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Version >= SchemaV2 {
+		var v2 CapturedRequestV2
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, err
+		}
+		return v2.ToCapturedRequest(), nil
+	}
+
+	var req CapturedRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ToCapturedRequest converts a v2 payload to a CapturedRequest. Repeated
+// headers are joined with ", " in Headers and kept individually in HeaderFields.
+func (r *CapturedRequestV2) ToCapturedRequest() *CapturedRequest {
+	req := r.CapturedRequest
+	req.Headers = joinHeaderFields(r.Headers)
+	req.HeaderFields = r.Headers
+	return &req
+}
+
+// V2 converts the request to the v2 wire format. If HeaderFields is empty it
+// is derived from Headers, sorted by name.
+func (r *CapturedRequest) V2() *CapturedRequestV2 {
+	v2 := &CapturedRequestV2{Version: SchemaV2, CapturedRequest: *r, Headers: r.Fields()}
+	v2.CapturedRequest.Headers = nil
+	v2.CapturedRequest.HeaderFields = nil
+	return v2
+}
+
+// Fields returns every header line of the request. v1 requests, which only
+// have Headers, are returned sorted by name.
+func (r *CapturedRequest) Fields() []HeaderField {
+	if len(r.HeaderFields) > 0 {
+		return r.HeaderFields
+	}
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]HeaderField, len(names))
+	for i, name := range names {
+		fields[i] = HeaderField{Name: name, Value: r.Headers[name]}
+	}
+	return fields
+}
+
+// HeaderValues returns all values of the named header (case-insensitive).
+func (r *CapturedRequest) HeaderValues(name string) []string {
+	var values []string
+	for _, f := range r.Fields() {
+		if strings.EqualFold(f.Name, name) {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// SetHeader replaces every value of the named header (case-insensitive)
+// in both Headers and HeaderFields. It does not modify the existing map or
+// slice in place, so copies of the request are unaffected.
+func (r *CapturedRequest) SetHeader(name, value string) {
+	headers := make(map[string]string, len(r.Headers)+1)
+	for k, v := range r.Headers {
+		if !strings.EqualFold(k, name) {
+			headers[k] = v
+		}
+	}
+	headers[name] = value
+	r.Headers = headers
+
+	if len(r.HeaderFields) > 0 {
+		fields := make([]HeaderField, 0, len(r.HeaderFields)+1)
+		for _, f := range r.HeaderFields {
+			if !strings.EqualFold(f.Name, name) {
+				fields = append(fields, f)
+			}
+		}
+		r.HeaderFields = append(fields, HeaderField{Name: name, Value: value})
+	}
+}
+
+// BodyBytes returns the raw request body, decoding it according to BodyEncoding.
+func (r *CapturedRequest) BodyBytes() ([]byte, error) {
+	switch r.BodyEncoding {
+	case "", BodyEncodingUTF8:
+		return []byte(r.Body), nil
+	case BodyEncodingBase64:
+		return base64.StdEncoding.DecodeString(r.Body)
+	default:
+		return nil, fmt.Errorf("unsupported body encoding: %s", r.BodyEncoding)
+	}
+}
+
+// joinHeaderFields collapses header fields into one value per name, keeping
+// the casing of the first occurrence.
+func joinHeaderFields(fields []HeaderField) map[string]string {
+	headers := make(map[string]string, len(fields))
+	canonical := make(map[string]string, len(fields))
+	for _, f := range fields {
+		lower := strings.ToLower(f.Name)
+		name, seen := canonical[lower]
+		if !seen {
+			canonical[lower] = f.Name
+			headers[f.Name] = f.Value
+			continue
+		}
+		headers[name] += ", " + f.Value
+	}
+	return headers
+}
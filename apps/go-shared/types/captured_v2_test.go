@@ -0,0 +1,139 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCapturedRequest_V1(t *testing.T) {
+	data := `{"_id":"r1","method":"POST","path":"/hook","headers":{"Content-Type":"application/json"},"body":"{}","queryParams":{},"size":2,"receivedAt":1}`
+
+	req, err := DecodeCapturedRequest([]byte(data))
+	if err != nil {
+		t.Fatalf("DecodeCapturedRequest: %v", err)
+	}
+	if req.ID != "r1" || req.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if len(req.HeaderFields) != 0 {
+		t.Errorf("v1 payload should not populate HeaderFields, got %+v", req.HeaderFields)
+	}
+}
+
+func TestDecodeCapturedRequest_V2MultiValueHeaders(t *testing.T) {
+	data := `{"version":2,"_id":"r2","method":"POST","path":"/","headers":[
+		{"name":"Set-Cookie","value":"a=1"},
+		{"name":"Content-Type","value":"text/plain"},
+		{"name":"set-cookie","value":"b=2"}
+	],"queryParams":{},"size":0,"receivedAt":1}`
+
+	req, err := DecodeCapturedRequest([]byte(data))
+	if err != nil {
+		t.Fatalf("DecodeCapturedRequest: %v", err)
+	}
+	if got := req.HeaderValues("SET-COOKIE"); !reflect.DeepEqual(got, []string{"a=1", "b=2"}) {
+		t.Errorf("HeaderValues = %v, want [a=1 b=2]", got)
+	}
+	if req.Headers["Set-Cookie"] != "a=1, b=2" {
+		t.Errorf("joined Set-Cookie = %q", req.Headers["Set-Cookie"])
+	}
+	if _, ok := req.Headers["set-cookie"]; ok {
+		t.Error("repeated header should be merged under the first casing")
+	}
+	if len(req.HeaderFields) != 3 || req.HeaderFields[1].Name != "Content-Type" {
+		t.Errorf("header order not preserved: %+v", req.HeaderFields)
+	}
+}
+
+func TestDecodeCapturedRequest_InvalidJSON(t *testing.T) {
+	if _, err := DecodeCapturedRequest([]byte("not-json")); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func TestBodyBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		encoding string
+		want     []byte
+		wantErr  bool
+	}{
+		{"default", "hello", "", []byte("hello"), false},
+		{"utf8", "hello", BodyEncodingUTF8, []byte("hello"), false},
+		{"base64", "AP8B", BodyEncodingBase64, []byte{0x00, 0xff, 0x01}, false},
+		{"bad base64", "%%%", BodyEncodingBase64, nil, true},
+		{"unknown encoding", "x", "gzip", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CapturedRequest{Body: tt.body, BodyEncoding: tt.encoding}
+			got, err := req.BodyBytes()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BodyBytes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetHeader_ReplacesAllValues(t *testing.T) {
+	fields := []HeaderField{
+		{Name: "X-Trace", Value: "1"},
+		{Name: "Accept", Value: "*/*"},
+		{Name: "x-trace", Value: "2"},
+	}
+	req := (&CapturedRequestV2{Headers: fields}).ToCapturedRequest()
+
+	req.SetHeader("X-TRACE", "3")
+
+	if got := req.HeaderValues("x-trace"); !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("HeaderValues = %v, want [3]", got)
+	}
+	if req.Headers["X-TRACE"] != "3" || len(req.Headers) != 2 {
+		t.Errorf("unexpected Headers map: %v", req.Headers)
+	}
+	if len(fields) != 3 || fields[0].Value != "1" {
+		t.Error("SetHeader must not modify the original slice")
+	}
+}
+
+func TestV2_RoundTrip(t *testing.T) {
+	orig := &CapturedRequest{
+		ID:      "r3",
+		Method:  "PUT",
+		Headers: map[string]string{"B": "2", "A": "1"},
+		Body:    "AAE=",
+		// Binary bodies travel base64-encoded.
+		BodyEncoding: BodyEncodingBase64,
+		Tags:         []string{"bug-1234", "stripe"},
+	}
+
+	data, err := json.Marshal(orig.V2())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "headerFields") {
+		t.Errorf("v2 payload should carry headers as a list only: %s", data)
+	}
+	got, err := DecodeCapturedRequest(data)
+	if err != nil {
+		t.Fatalf("DecodeCapturedRequest: %v", err)
+	}
+	if got.HeaderFields[0].Name != "A" || got.HeaderFields[1].Name != "B" {
+		t.Errorf("v1 headers should be sorted by name, got %+v", got.HeaderFields)
+	}
+	if !reflect.DeepEqual(got.Headers, orig.Headers) {
+		t.Errorf("Headers = %v, want %v", got.Headers, orig.Headers)
+	}
+	if got.BodyEncoding != BodyEncodingBase64 || got.Body != orig.Body {
+		t.Errorf("body not preserved: %q (%s)", got.Body, got.BodyEncoding)
+	}
+	if !reflect.DeepEqual(got.Tags, orig.Tags) {
+		t.Errorf("Tags = %v, want %v", got.Tags, orig.Tags)
+	}
+}
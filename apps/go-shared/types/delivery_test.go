@@ -0,0 +1,29 @@
+package types
+
+import "testing"
+
+func TestDeliveryHistory_Record(t *testing.T) {
+	h := &DeliveryHistory{RequestID: "r1"}
+	if h.Last() != nil {
+		t.Fatal("Last should be nil for empty history")
+	}
+
+	h.Record(ForwardAttempt{Target: "http://localhost:3000", Error: "connection refused"})
+	h.Record(ForwardAttempt{Target: "http://localhost:3000", StatusCode: 200, LatencyMs: 12})
+
+	if len(h.Attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(h.Attempts))
+	}
+	if h.Attempts[0].Attempt != 1 || h.Attempts[1].Attempt != 2 {
+		t.Errorf("attempts not numbered sequentially: %+v", h.Attempts)
+	}
+	if h.Attempts[0].RequestID != "r1" {
+		t.Errorf("RequestID not inherited: %q", h.Attempts[0].RequestID)
+	}
+	if h.Attempts[0].Delivered() {
+		t.Error("failed attempt should not be delivered")
+	}
+	if last := h.Last(); last == nil || !last.Delivered() || last.StatusCode != 200 {
+		t.Errorf("unexpected last attempt: %+v", last)
+	}
+}
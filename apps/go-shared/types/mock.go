@@ -0,0 +1,36 @@
+package types
+
+// DefaultMockResponse is what the receiver answers with when the endpoint
+// has no mock response.
+var DefaultMockResponse = MockResponse{Status: 200, Body: "OK"}
+
+// MockResult is how an endpoint answers a request, as worked out by
+// ResolveMock.
+type MockResult struct {
+	// Route is the route whose prefix matched the request path, or nil
+	Route *EndpointRoute
+	// Response is the response sent
+	Response MockResponse
+}
+
+// ResolveMock works out how an endpoint with the given mock response and
+// routes answers req. The route with the longest matching prefix picks the
+// mock response, falling back to the endpoint's, then to
+// DefaultMockResponse.
+func ResolveMock(mock *MockResponse, routes []EndpointRoute, req *CapturedRequest) MockResult {
+	var result MockResult
+	result.Route = MatchRoute(routes, req.Path)
+	if result.Route != nil && result.Route.MockResponse != nil {
+		mock = result.Route.MockResponse
+	}
+	if mock == nil {
+		mock = &DefaultMockResponse
+	}
+
+	resp := MockResponse{Status: mock.Status, Body: mock.Body, Headers: map[string]string{}, Delay: mock.Delay}
+	for k, v := range mock.Headers {
+		resp.Headers[k] = v
+	}
+	result.Response = resp
+	return result
+}
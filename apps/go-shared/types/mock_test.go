@@ -0,0 +1,54 @@
+package types
+
+import "testing"
+
+func TestResolveMock(t *testing.T) {
+	mock := &MockResponse{
+		Status:  200,
+		Body:    `{"ok":true}`,
+		Headers: map[string]string{"Content-Type": "application/json"},
+	}
+	routes := []EndpointRoute{
+		{Prefix: "/github", MockResponse: &MockResponse{Status: 204}},
+		{Prefix: "/stripe", Name: "stripe"},
+	}
+
+	t.Run("endpoint mock", func(t *testing.T) {
+		got := ResolveMock(mock, routes, &CapturedRequest{Method: "POST", Path: "/"})
+		if got.Route != nil {
+			t.Fatalf("unexpected route: %+v", got.Route)
+		}
+		if got.Response.Status != 200 || got.Response.Headers["Content-Type"] != "application/json" {
+			t.Errorf("unexpected response: %+v", got.Response)
+		}
+	})
+
+	t.Run("route mock", func(t *testing.T) {
+		got := ResolveMock(mock, routes, &CapturedRequest{Method: "POST", Path: "/github/push"})
+		if got.Route == nil || got.Route.Prefix != "/github" || got.Response.Status != 204 {
+			t.Errorf("expected the /github route's 204, got %+v", got)
+		}
+	})
+
+	t.Run("route without mock falls back", func(t *testing.T) {
+		got := ResolveMock(mock, routes, &CapturedRequest{Method: "POST", Path: "/stripe"})
+		if got.Route == nil || got.Route.Name != "stripe" || got.Response.Status != 200 {
+			t.Errorf("expected the endpoint's mock via the stripe route, got %+v", got)
+		}
+	})
+
+	t.Run("default response", func(t *testing.T) {
+		got := ResolveMock(nil, nil, &CapturedRequest{Method: "POST", Path: "/"})
+		if got.Response.Status != 200 || got.Response.Body != "OK" {
+			t.Errorf("expected the default 200 OK, got %+v", got.Response)
+		}
+	})
+
+	t.Run("does not share the mock's headers", func(t *testing.T) {
+		got := ResolveMock(mock, nil, &CapturedRequest{Method: "POST", Path: "/"})
+		got.Response.Headers["X-Changed"] = "yes"
+		if _, ok := mock.Headers["X-Changed"]; ok {
+			t.Error("ResolveMock returned the mock's own header map")
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package types
+
+import "strings"
+
+// EndpointRoute is a logical sub-endpoint: requests whose path (relative to
+// the endpoint) starts with Prefix get the route's mock response instead of
+// the endpoint's, are forwarded to ForwardURL when it is set, and are
+// captured with the route's Tags. This lets one capture URL stand in for a
+// provider that posts to many paths.
+type EndpointRoute struct {
+	Prefix       string        `json:"prefix"`
+	Name         string        `json:"name,omitempty"`
+	MockResponse *MockResponse `json:"mockResponse,omitempty"`
+	ForwardURL   string        `json:"forwardUrl,omitempty"`
+	Tags         []string      `json:"tags,omitempty"`
+}
+
+// Matches reports whether path falls under the route's prefix. Prefixes
+// match whole segments, so /stripe matches /stripe and /stripe/events but
+// not /stripe-connect.
+func (r EndpointRoute) Matches(path string) bool {
+	prefix := strings.TrimSuffix(r.Prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	rest, ok := strings.CutPrefix(path, prefix)
+	return ok && (rest == "" || rest[0] == '/' || rest[0] == '?')
+}
+
+// MatchRoute returns the route with the longest prefix that matches path,
+// or nil if none does.
+func MatchRoute(routes []EndpointRoute, path string) *EndpointRoute {
+	var best *EndpointRoute
+	for i := range routes {
+		r := &routes[i]
+		if r.Matches(path) && (best == nil || len(r.Prefix) > len(best.Prefix)) {
+			best = r
+		}
+	}
+	return best
+}
@@ -0,0 +1,36 @@
+package types
+
+import "testing"
+
+func TestMatchRoute(t *testing.T) {
+	routes := []EndpointRoute{
+		{Prefix: "/", Name: "default"},
+		{Prefix: "/stripe", Name: "stripe"},
+		{Prefix: "/stripe/connect/", Name: "connect"},
+		{Prefix: "/github", Name: "github"},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/stripe", "stripe"},
+		{"/stripe/events", "stripe"},
+		{"/stripe?livemode=true", "stripe"},
+		{"/stripe/connect/accounts", "connect"},
+		{"/stripe/connect", "connect"},
+		{"/stripe-connect", "default"},
+		{"/github/push", "github"},
+		{"/", "default"},
+	}
+	for _, tt := range tests {
+		got := MatchRoute(routes, tt.path)
+		if got == nil || got.Name != tt.want {
+			t.Errorf("MatchRoute(%q): expected %s, got %+v", tt.path, tt.want, got)
+		}
+	}
+
+	if got := MatchRoute(routes[1:], "/other"); got != nil {
+		t.Errorf("expected no match without a catch-all route, got %+v", got)
+	}
+}
@@ -1,18 +1,45 @@
 package types
 
-// CapturedRequest represents a captured webhook request
+// CapturedRequest represents a captured webhook request, decoded from either
+// wire format (see DecodeCapturedRequest). Fields the server doesn't send are
+// left at their zero value.
 type CapturedRequest struct {
-	ID          string            `json:"_id"`
-	EndpointID  string            `json:"endpointId"`
-	Method      string            `json:"method"`
-	Path        string            `json:"path"`
-	Headers     map[string]string `json:"headers"`
-	Body        string            `json:"body,omitempty"`
-	QueryParams map[string]string `json:"queryParams"`
+	ID         string `json:"_id"`
+	EndpointID string `json:"endpointId"`
+	Method     string `json:"method"`
+	// Path is the request path after /w/<slug>
+	Path string `json:"path"`
+	// Headers holds one value per header name; repeated headers are joined
+	// with ", "
+	Headers map[string]string `json:"headers"`
+	// HeaderFields holds every header line in received order. Only v2
+	// payloads carry it; see Fields for a version-independent view.
+	HeaderFields []HeaderField `json:"headerFields,omitempty"`
+	// Body is the request body, encoded as BodyEncoding says
+	Body string `json:"body,omitempty"`
+	// BodyEncoding is BodyEncodingBase64 for binary bodies; empty or
+	// BodyEncodingUTF8 means Body is the text itself
+	BodyEncoding string            `json:"bodyEncoding,omitempty"`
+	QueryParams  map[string]string `json:"queryParams"`
+	// PathParams holds the named segments of the endpoint path pattern that
+	// Path matched, when the server sends them (see package pathpattern)
+	PathParams  map[string]string `json:"pathParams,omitempty"`
 	ContentType string            `json:"contentType,omitempty"`
 	IP          string            `json:"ip"`
-	Size        int               `json:"size"`
-	ReceivedAt  int64             `json:"receivedAt"`
+	// Size is the body size in bytes
+	Size int `json:"size"`
+	// ReceivedAt is a Unix timestamp in milliseconds
+	ReceivedAt int64 `json:"receivedAt"`
+	// Pinned requests are kept when retention cleanup runs
+	Pinned bool `json:"pinned,omitempty"`
+	// Note is a free-form annotation left by a user
+	Note string `json:"note,omitempty"`
+	// ResponseStatus is the status the endpoint answered with, or 0 if
+	// the server didn't record it
+	ResponseStatus int `json:"responseStatus,omitempty"`
+	// Tags label the request for later filtering (see
+	// validation.NormalizeTag)
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Endpoint represents a webhook endpoint
@@ -27,11 +54,14 @@ type Endpoint struct {
 	CreatedAt    int64         `json:"createdAt"`
 }
 
-// MockResponse defines what the endpoint should return
+// MockResponse defines what the endpoint should return.
+// Delay is how long the receiver waits before answering, in milliseconds
+// (at most 30000).
 type MockResponse struct {
 	Status  int               `json:"status"`
 	Body    string            `json:"body"`
 	Headers map[string]string `json:"headers"`
+	Delay   int64             `json:"delay,omitempty"`
 }
 
 // User represents a user account
@@ -45,3 +75,74 @@ type User struct {
 	PeriodEnd           int64  `json:"periodEnd,omitempty"`
 	CancelAtPeriodEnd   bool   `json:"cancelAtPeriodEnd,omitempty"`
 }
+
+// Forward attempt sources
+const (
+	ForwardSourceTunnel   = "tunnel"
+	ForwardSourceReceiver = "receiver"
+)
+
+// ForwardAttempt records one attempt to deliver a captured request to a
+// forward target, either by the CLI tunnel or by server-side forwarding.
+// Error is set when no HTTP response was received.
+type ForwardAttempt struct {
+	RequestID   string `json:"requestId"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	LatencyMs   int64  `json:"latencyMs"`
+	Error       string `json:"error,omitempty"`
+	AttemptedAt int64  `json:"attemptedAt"`
+}
+
+// Delivered reports whether the target returned an HTTP response.
+func (a ForwardAttempt) Delivered() bool {
+	return a.Error == "" && a.StatusCode != 0
+}
+
+// DeliveryHistory lists the forward attempts for a captured request, oldest first.
+type DeliveryHistory struct {
+	RequestID string           `json:"requestId"`
+	Attempts  []ForwardAttempt `json:"attempts"`
+}
+
+// Last returns the most recent attempt, or nil if there are none.
+func (h *DeliveryHistory) Last() *ForwardAttempt {
+	if len(h.Attempts) == 0 {
+		return nil
+	}
+	return &h.Attempts[len(h.Attempts)-1]
+}
+
+// Record appends an attempt, numbering it after the previous one.
+func (h *DeliveryHistory) Record(a ForwardAttempt) {
+	a.Attempt = len(h.Attempts) + 1
+	if a.RequestID == "" {
+		a.RequestID = h.RequestID
+	}
+	h.Attempts = append(h.Attempts, a)
+}
+
+// Platform event types reported on an endpoint's event stream
+const (
+	EventQuotaDenied    = "quota_denied"
+	EventMockServed     = "mock_served"
+	EventForwardAttempt = "forward_attempt"
+	// EventSizeWarning is reported when a captured payload approaches the
+	// maximum body size, before captures start failing. Its details carry
+	// the payload size and the limit, in bytes.
+	EventSizeWarning = "size_warning"
+)
+
+// PlatformEvent is a structured record of something the platform did with a
+// delivery to an endpoint, separate from the captured request itself.
+// RequestID is empty when the delivery was rejected before being captured.
+type PlatformEvent struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	RequestID string            `json:"requestId,omitempty"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	Timestamp int64             `json:"timestamp"`
+}
@@ -0,0 +1,225 @@
+// Package validation holds the slug, header, and mock-response rules shared
+// by the receiver and the CLI, so both treat captured requests identically.
+// The rules mirror the receiver's handlers/webhook.rs and the backend's
+// SLUG_REGEX; keep them in sync when either side changes.
+package validation
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// MaxSlugLen is the maximum length of an endpoint slug.
+	MaxSlugLen = 50
+	// MinCustomSlugLen is the minimum length of a user-chosen slug or alias.
+	// Generated slugs are not subject to it.
+	MinCustomSlugLen = 3
+	// MaxHeaderKeyLen is the maximum length of a mock response header name.
+	MaxHeaderKeyLen = 256
+	// MaxHeaderValueLen is the maximum length of a mock response header value.
+	MaxHeaderValueLen = 8192
+	// MaxMockDelay is the longest a mock response may be delayed.
+	MaxMockDelay = 30 * time.Second
+	// DefaultMockStatus is used when a mock response has an invalid status.
+	DefaultMockStatus = 200
+	// MaxRoutes is the maximum number of sub-endpoint routes per endpoint.
+	MaxRoutes = 20
+	// MaxRoutePrefixLen is the maximum length of a route's path prefix.
+	MaxRoutePrefixLen = 256
+	// MaxBodySize is the largest request body the receiver captures.
+	MaxBodySize = 1024 * 1024 // 1MB
+	// SizeWarningPercent is how close to MaxBodySize, in percent, an
+	// endpoint's payloads may get before a size warning event is emitted.
+	SizeWarningPercent = 80
+	// MaxTagLen is the maximum length of a captured request's tag.
+	MaxTagLen = 64
+	// MaxTags is the maximum number of tags on a request or route.
+	MaxTags = 20
+)
+
+// ProxyHeaders are added by our infrastructure (Cloudflare + Caddy) and are
+// not part of the original sender's request, so they are never stored.
+var ProxyHeaders = map[string]bool{
+	"accept-encoding":         true,
+	"cdn-loop":                true,
+	"cf-connecting-ip":        true,
+	"cf-ipcountry":            true,
+	"cf-ray":                  true,
+	"cf-visitor":              true,
+	"via":                     true,
+	"x-forwarded-for":         true,
+	"x-forwarded-host":        true,
+	"x-forwarded-proto":       true,
+	"x-real-ip":               true,
+	"true-client-ip":          true,
+	"x-webhooks-cc-test-send": true,
+}
+
+// ReservedSlugs can't be chosen as custom slugs or aliases, because they
+// name parts of the service or could be mistaken for them.
+var ReservedSlugs = map[string]bool{
+	"admin":    true,
+	"api":      true,
+	"app":      true,
+	"docs":     true,
+	"health":   true,
+	"internal": true,
+	"status":   true,
+	"webhooks": true,
+	"www":      true,
+}
+
+// BlockedResponseHeaders must never be sent from a mock response.
+var BlockedResponseHeaders = map[string]bool{
+	"set-cookie":                true,
+	"strict-transport-security": true,
+	"content-security-policy":   true,
+	"x-frame-options":           true,
+}
+
+// IsValidSlug reports whether slug is 1-50 characters of [a-zA-Z0-9_-].
+func IsValidSlug(slug string) bool {
+	if slug == "" || len(slug) > MaxSlugLen {
+		return false
+	}
+	for i := 0; i < len(slug); i++ {
+		c := slug[i]
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeSlug lowercases slug (slugs match case-insensitively) and
+// reports whether the result is valid.
+func NormalizeSlug(slug string) (string, bool) {
+	slug = strings.ToLower(slug)
+	return slug, IsValidSlug(slug)
+}
+
+// IsValidCustomSlug reports whether slug may be chosen by a user as an
+// endpoint slug or alias: a lowercase valid slug of at least
+// MinCustomSlugLen characters that starts and ends with a letter or digit
+// and is not reserved. Callers should normalize with NormalizeSlug first.
+func IsValidCustomSlug(slug string) bool {
+	if len(slug) < MinCustomSlugLen || !IsValidSlug(slug) || slug != strings.ToLower(slug) {
+		return false
+	}
+	isAlnum := func(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') }
+	if !isAlnum(slug[0]) || !isAlnum(slug[len(slug)-1]) {
+		return false
+	}
+	return !ReservedSlugs[slug]
+}
+
+// IsValidRoutePrefix reports whether prefix can be used as a sub-endpoint
+// route: a path starting with / and at most MaxRoutePrefixLen characters,
+// without a query string, wildcards, or whitespace.
+func IsValidRoutePrefix(prefix string) bool {
+	if !strings.HasPrefix(prefix, "/") || len(prefix) > MaxRoutePrefixLen {
+		return false
+	}
+	return !strings.ContainsAny(prefix, "?#* \t\r\n")
+}
+
+// NormalizeTag lowercases and trims tag (tags match case-insensitively) and
+// reports whether the result is valid: 1 to MaxTagLen characters of
+// [a-z0-9._:/-], starting with a letter or digit.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || len(tag) > MaxTagLen {
+		return tag, false
+	}
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+		if !isAlnum && (i == 0 || !strings.ContainsRune("._:/-", rune(c))) {
+			return tag, false
+		}
+	}
+	return tag, true
+}
+
+// IsProxyHeader reports whether name (case-insensitive) is an
+// infrastructure header that should be dropped from captures.
+func IsProxyHeader(name string) bool {
+	return ProxyHeaders[strings.ToLower(name)]
+}
+
+// FilterRequestHeaders returns a copy of headers without proxy headers.
+func FilterRequestHeaders(headers map[string]string) map[string]string {
+	filtered := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if !IsProxyHeader(k) {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// IsSafeResponseHeader reports whether a mock response header may be sent:
+// it must be within the length limits, not blocked, and free of CR/LF.
+func IsSafeResponseHeader(key, value string) bool {
+	if key == "" || len(key) > MaxHeaderKeyLen || len(value) > MaxHeaderValueLen {
+		return false
+	}
+	if BlockedResponseHeaders[strings.ToLower(key)] {
+		return false
+	}
+	return !strings.ContainsAny(key, "\r\n") && !strings.ContainsAny(value, "\r\n")
+}
+
+// SanitizeResponseHeaders returns a copy of headers containing only the
+// entries accepted by IsSafeResponseHeader.
+func SanitizeResponseHeaders(headers map[string]string) map[string]string {
+	sanitized := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if IsSafeResponseHeader(k, v) {
+			sanitized[k] = v
+		}
+	}
+	return sanitized
+}
+
+// NearSizeLimit reports whether a body of size bytes is within
+// SizeWarningPercent of MaxBodySize.
+func NearSizeLimit(size int) bool {
+	return size*100 >= MaxBodySize*SizeWarningPercent
+}
+
+// IsValidStatus reports whether status can be sent as a mock response
+// status: any three-digit code (100-999), the range the receiver's
+// StatusCode::from_u16 accepts, not just the registered 1xx-5xx ones.
+func IsValidStatus(status int) bool {
+	return status >= 100 && status <= 999
+}
+
+// ClampStatus returns status if it is valid (see IsValidStatus), and
+// DefaultMockStatus otherwise.
+func ClampStatus(status int) int {
+	if !IsValidStatus(status) {
+		return DefaultMockStatus
+	}
+	return status
+}
+
+// IsValidDelay reports whether a mock response delay in milliseconds is
+// within [0, MaxMockDelay].
+func IsValidDelay(ms int64) bool {
+	return ms >= 0 && ms <= MaxMockDelay.Milliseconds()
+}
+
+// ClampDelay limits a mock response delay in milliseconds to [0, MaxMockDelay].
+func ClampDelay(ms int64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	d := time.Duration(ms) * time.Millisecond
+	if d > MaxMockDelay {
+		return MaxMockDelay
+	}
+	return d
+}
@@ -0,0 +1,201 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsValidSlug(t *testing.T) {
+	tests := []struct {
+		slug string
+		want bool
+	}{
+		{"abc", true},
+		{"my-endpoint", true},
+		{"test_123", true},
+		{"A", true},
+		{strings.Repeat("a", 50), true},
+		{"", false},
+		{strings.Repeat("a", 51), false},
+		{"has space", false},
+		{"has/slash", false},
+		{"has.dot", false},
+		{"ünïcode", false},
+		{"tab\there", false},
+		{"../etc", false},
+		{"semi;colon", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidSlug(tt.slug); got != tt.want {
+			t.Errorf("IsValidSlug(%q) = %v, want %v", tt.slug, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	got, ok := NormalizeSlug("My-Endpoint")
+	if !ok || got != "my-endpoint" {
+		t.Errorf("NormalizeSlug(My-Endpoint) = %q, %v", got, ok)
+	}
+	if _, ok := NormalizeSlug("bad slug"); ok {
+		t.Error("NormalizeSlug should reject invalid slugs")
+	}
+}
+
+func TestIsValidCustomSlug(t *testing.T) {
+	valid := []string{"my-stripe-dev", "abc", "pay_2024", strings.Repeat("a", MaxSlugLen)}
+	for _, slug := range valid {
+		if !IsValidCustomSlug(slug) {
+			t.Errorf("IsValidCustomSlug(%q) = false, want true", slug)
+		}
+	}
+	invalid := []string{"", "ab", "-leading", "trailing_", "Upper", "api", "has space", strings.Repeat("a", MaxSlugLen+1)}
+	for _, slug := range invalid {
+		if IsValidCustomSlug(slug) {
+			t.Errorf("IsValidCustomSlug(%q) = true, want false", slug)
+		}
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	valid := map[string]string{
+		"bug-1234":                     "bug-1234",
+		" Stripe ":                     "stripe",
+		"env:staging":                  "env:staging",
+		"team/payments":                "team/payments",
+		"v1.2_rc":                      "v1.2_rc",
+		"2024":                         "2024",
+		strings.Repeat("a", MaxTagLen): strings.Repeat("a", MaxTagLen),
+	}
+	for in, want := range valid {
+		if got, ok := NormalizeTag(in); !ok || got != want {
+			t.Errorf("NormalizeTag(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "  ", "-leading", "has space", "comma,tag", "star*", strings.Repeat("a", MaxTagLen+1)} {
+		if _, ok := NormalizeTag(in); ok {
+			t.Errorf("NormalizeTag(%q) should be invalid", in)
+		}
+	}
+}
+
+func TestIsValidRoutePrefix(t *testing.T) {
+	valid := []string{"/", "/stripe", "/stripe/connect/", "/v1/events_2024"}
+	for _, prefix := range valid {
+		if !IsValidRoutePrefix(prefix) {
+			t.Errorf("IsValidRoutePrefix(%q) = false, want true", prefix)
+		}
+	}
+	invalid := []string{"", "stripe", "/stripe?x=1", "/stripe/*", "/has space", "/" + strings.Repeat("a", MaxRoutePrefixLen)}
+	for _, prefix := range invalid {
+		if IsValidRoutePrefix(prefix) {
+			t.Errorf("IsValidRoutePrefix(%q) = true, want false", prefix)
+		}
+	}
+}
+
+func TestFilterRequestHeaders(t *testing.T) {
+	in := map[string]string{
+		"Content-Type":    "application/json",
+		"X-Custom":        "hello",
+		"CF-Ray":          "abc123",
+		"x-forwarded-for": "1.2.3.4",
+		"Accept-Encoding": "gzip",
+	}
+	got := FilterRequestHeaders(in)
+	if len(got) != 2 || got["Content-Type"] != "application/json" || got["X-Custom"] != "hello" {
+		t.Errorf("unexpected filtered headers: %v", got)
+	}
+	if len(in) != 5 {
+		t.Error("FilterRequestHeaders must not modify its input")
+	}
+}
+
+func TestIsSafeResponseHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		key, value string
+		want       bool
+	}{
+		{"plain", "Content-Type", "text/plain", true},
+		{"custom", "X-Custom", "allowed", true},
+		{"empty key", "", "value", false},
+		{"set-cookie", "Set-Cookie", "session=abc", false},
+		{"hsts", "strict-transport-security", "max-age=1", false},
+		{"csp", "Content-Security-Policy", "default-src 'self'", false},
+		{"frame options", "X-Frame-Options", "DENY", false},
+		{"crlf value", "X-Bad", "value\r\nInjected: header", false},
+		{"lf value", "X-Bad", "value\nInjected: header", false},
+		{"crlf key", "bad\r\nkey", "value", false},
+		{"key at limit", strings.Repeat("k", MaxHeaderKeyLen), "v", true},
+		{"key too long", strings.Repeat("k", MaxHeaderKeyLen+1), "v", false},
+		{"value at limit", "X-Long", strings.Repeat("v", MaxHeaderValueLen), true},
+		{"value too long", "X-Long", strings.Repeat("v", MaxHeaderValueLen+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSafeResponseHeader(tt.key, tt.value); got != tt.want {
+				t.Errorf("IsSafeResponseHeader(%q, %q) = %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeResponseHeaders(t *testing.T) {
+	got := SanitizeResponseHeaders(map[string]string{
+		"good-header": "safe-value",
+		"bad-header":  "value\r\nInjected: header",
+		"set-cookie":  "session=abc; HttpOnly",
+	})
+	if len(got) != 1 || got["good-header"] != "safe-value" {
+		t.Errorf("unexpected sanitized headers: %v", got)
+	}
+}
+
+func TestNearSizeLimit(t *testing.T) {
+	if NearSizeLimit(0) || NearSizeLimit(MaxBodySize/2) {
+		t.Error("small bodies should not be near the limit")
+	}
+	if !NearSizeLimit(MaxBodySize*9/10) || !NearSizeLimit(MaxBodySize) {
+		t.Error("bodies at 90% and 100% of the limit should be near it")
+	}
+}
+
+func TestClampStatus(t *testing.T) {
+	tests := []struct{ in, want int }{
+		{200, 200},
+		{100, 100},
+		{599, 599},
+		{404, 404},
+		{600, 600},
+		{999, 999},
+		{0, DefaultMockStatus},
+		{99, DefaultMockStatus},
+		{1000, DefaultMockStatus},
+		{-1, DefaultMockStatus},
+	}
+	for _, tt := range tests {
+		if got := ClampStatus(tt.in); got != tt.want {
+			t.Errorf("ClampStatus(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestClampDelay(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want time.Duration
+	}{
+		{0, 0},
+		{-5, 0},
+		{250, 250 * time.Millisecond},
+		{30000, MaxMockDelay},
+		{60000, MaxMockDelay},
+	}
+	for _, tt := range tests {
+		if got := ClampDelay(tt.in); got != tt.want {
+			t.Errorf("ClampDelay(%d) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,5 @@
 import { authenticateSessionRequest, type AuthResult } from "@/lib/api-auth";
+import { parseApiKeyScopes } from "@/lib/api-key-scopes";
 import { createAdminClient } from "@/lib/supabase/admin";
 import { generateApiKey, hashApiKey, MAX_KEYS_PER_USER } from "@/lib/supabase/api-keys";
 
@@ -11,7 +12,7 @@ export async function GET(request: Request) {
   const admin = createAdminClient();
   const { data, error } = await admin
     .from("api_keys")
-    .select("id, name, key_prefix, created_at, expires_at, last_used_at")
+    .select("id, name, key_prefix, scopes, created_at, expires_at, last_used_at")
     .eq("user_id", auth.userId)
     .order("created_at", { ascending: false });
 
@@ -27,9 +28,9 @@ export async function POST(request: Request) {
   const auth = await authenticateSessionRequest(request);
   if (!auth.success) return auth.response;
 
-  let body: { name?: string };
+  let body: { name?: string; scopes?: unknown; expiresAt?: unknown };
   try {
-    body = (await request.json()) as { name?: string };
+    body = (await request.json()) as { name?: string; scopes?: unknown; expiresAt?: unknown };
   } catch {
     return Response.json({ error: "Invalid request body" }, { status: 400 });
   }
@@ -39,6 +40,29 @@ export async function POST(request: Request) {
     return Response.json({ error: "Name is required" }, { status: 400 });
   }
 
+  const scopes = parseApiKeyScopes(body.scopes);
+  if (!scopes) {
+    return Response.json({ error: "Invalid scopes: must be read or capture" }, { status: 400 });
+  }
+
+  // expiresAt is a Unix timestamp in milliseconds, no later than the default TTL
+  const maxExpiresAt = Date.now() + DEFAULT_TTL_DAYS * 86_400_000;
+  let expiresAtMs = maxExpiresAt;
+  if (body.expiresAt !== undefined) {
+    if (
+      typeof body.expiresAt !== "number" ||
+      !Number.isInteger(body.expiresAt) ||
+      body.expiresAt <= Date.now() ||
+      body.expiresAt > maxExpiresAt
+    ) {
+      return Response.json(
+        { error: `Invalid expiresAt: must be within ${DEFAULT_TTL_DAYS} days` },
+        { status: 400 }
+      );
+    }
+    expiresAtMs = body.expiresAt;
+  }
+
   const admin = createAdminClient();
 
   const { count, error: countError } = await admin
@@ -61,13 +85,14 @@ export async function POST(request: Request) {
   const rawKey = generateApiKey();
   const keyHash = hashApiKey(rawKey);
   const keyPrefix = rawKey.slice(0, 12);
-  const expiresAt = new Date(Date.now() + DEFAULT_TTL_DAYS * 86_400_000).toISOString();
+  const expiresAt = new Date(expiresAtMs).toISOString();
 
   const { error: insertError } = await admin.from("api_keys").insert({
     user_id: auth.userId,
     name,
     key_hash: keyHash,
     key_prefix: keyPrefix,
+    scopes,
     expires_at: expiresAt,
   });
 
@@ -76,7 +101,7 @@ export async function POST(request: Request) {
     return Response.json({ error: "Failed to create API key" }, { status: 500 });
   }
 
-  return Response.json({ key: rawKey, name, keyPrefix, expiresAt });
+  return Response.json({ key: rawKey, name, keyPrefix, scopes, expiresAt });
 }
 
 export async function DELETE(request: Request) {
@@ -22,6 +22,7 @@ export async function POST(request: Request) {
       apiKey: result.apiKey,
       userId: result.userId,
       email: result.email,
+      scopes: result.scopes,
     });
   } catch (error) {
     // Distinguish expected claim failures (expired, already used, etc.) from server errors
@@ -1,4 +1,6 @@
+import { parseApiKeyScopes, type ApiKeyScope } from "@/lib/api-key-scopes";
 import { checkRateLimit } from "@/lib/rate-limit";
+import { parseJsonBody } from "@/lib/request-validation";
 import { createDeviceCodeRecord } from "@/lib/supabase/device-auth";
 import { sendError } from "@appsignal/nodejs";
 
@@ -6,8 +8,21 @@ export async function POST(request: Request) {
   const rateLimited = checkRateLimit(request, 10);
   if (rateLimited) return rateLimited;
 
+  // The body is optional: without one the issued key has full access
+  let scopes: ApiKeyScope[] = [];
+  if (request.body && request.headers.get("Content-Length") !== "0") {
+    const parsed = await parseJsonBody(request, 1024);
+    if ("error" in parsed) return parsed.error;
+    const body = parsed.data as Record<string, unknown>;
+    const requested = parseApiKeyScopes(body.scopes);
+    if (!requested) {
+      return Response.json({ error: "Invalid scopes: must be read or capture" }, { status: 400 });
+    }
+    scopes = requested;
+  }
+
   try {
-    const result = await createDeviceCodeRecord();
+    const result = await createDeviceCodeRecord(scopes);
 
     const appUrl = process.env.NEXT_PUBLIC_APP_URL || "https://webhooks.cc";
 
@@ -16,6 +31,7 @@ export async function POST(request: Request) {
       userCode: result.userCode,
       expiresAt: result.expiresAt,
       verificationUrl: `${appUrl}/cli/verify`,
+      scopes: result.scopes,
     });
   } catch (err) {
     if (err instanceof Error && err.message.includes("Too many pending device codes")) {
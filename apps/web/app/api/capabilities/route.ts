@@ -0,0 +1,15 @@
+/**
+ * What this server supports, for clients that check before relying on newer
+ * API surfaces. Mirrors types.Capabilities in apps/go-shared: the CLI sends
+ * a wire format version in the Accept-Version header and looks up optional
+ * features by name.
+ */
+const API_VERSIONS = ["1"];
+const FEATURES = ["pagination"];
+
+export function GET() {
+  return Response.json(
+    { versions: API_VERSIONS, features: FEATURES },
+    { headers: { "Cache-Control": "public, max-age=300" } }
+  );
+}
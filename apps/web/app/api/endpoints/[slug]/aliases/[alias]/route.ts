@@ -0,0 +1,24 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { removeEndpointAliasForUser } from "@/lib/supabase/endpoints";
+
+export async function DELETE(
+  request: Request,
+  { params }: { params: Promise<{ slug: string; alias: string }> }
+) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { slug, alias } = await params;
+
+  try {
+    const removed = await removeEndpointAliasForUser(auth.userId, slug, alias);
+    if (!removed) {
+      return Response.json({ error: "Alias not found" }, { status: 404 });
+    }
+
+    return new Response(null, { status: 204 });
+  } catch (error) {
+    console.error("Failed to remove endpoint alias:", error);
+    return Response.json({ error: "Internal server error" }, { status: 500 });
+  }
+}
@@ -0,0 +1,44 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { addEndpointAliasForUser, normalizeEndpointAlias } from "@/lib/supabase/endpoints";
+
+export async function POST(request: Request, { params }: { params: Promise<{ slug: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { slug } = await params;
+
+  let body: { alias?: unknown };
+  try {
+    body = (await request.json()) as { alias?: unknown };
+  } catch {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+
+  const alias = normalizeEndpointAlias(body.alias);
+  if (!alias) {
+    return Response.json(
+      {
+        error:
+          "Invalid alias: use 3-50 characters of a-z, 0-9, - and _, starting and ending with a letter or digit",
+      },
+      { status: 400 }
+    );
+  }
+
+  try {
+    const result = await addEndpointAliasForUser(auth.userId, slug, alias);
+    switch (result) {
+      case "not_found":
+        return Response.json({ error: "Endpoint not found" }, { status: 404 });
+      case "taken":
+        return Response.json({ error: "Slug is already in use" }, { status: 409 });
+      case "limit_reached":
+        return Response.json({ error: "Too many aliases for this endpoint" }, { status: 400 });
+    }
+
+    return Response.json({ alias }, { status: 201 });
+  } catch (error) {
+    console.error("Failed to add endpoint alias:", error);
+    return Response.json({ error: "Internal server error" }, { status: 500 });
+  }
+}
@@ -0,0 +1,35 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { jsonWithETag } from "@/lib/http";
+import { listEndpointEventsForUser } from "@/lib/supabase/events";
+
+export async function GET(request: Request, { params }: { params: Promise<{ slug: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { slug } = await params;
+  const url = new URL(request.url);
+
+  const limit = url.searchParams.get("limit");
+  const parsedLimit = limit ? Number(limit) : undefined;
+
+  if (parsedLimit !== undefined && (!Number.isFinite(parsedLimit) || parsedLimit < 1)) {
+    return Response.json({ error: "invalid_limit" }, { status: 400 });
+  }
+
+  try {
+    const data = await listEndpointEventsForUser({
+      userId: auth.userId,
+      slug,
+      limit: parsedLimit,
+    });
+
+    if (!data) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    return await jsonWithETag(request, data);
+  } catch (error) {
+    console.error("Failed to list endpoint events:", error);
+    return Response.json({ error: "Failed to list endpoint events" }, { status: 500 });
+  }
+}
@@ -1,4 +1,5 @@
 import { authenticateRequest } from "@/lib/api-auth";
+import { jsonWithETag } from "@/lib/http";
 import { listPaginatedRequestsForEndpointByUser } from "@/lib/supabase/requests";
 
 export async function GET(request: Request, { params }: { params: Promise<{ slug: string }> }) {
@@ -28,7 +29,7 @@ export async function GET(request: Request, { params }: { params: Promise<{ slug
       return Response.json({ error: "not_found" }, { status: 404 });
     }
 
-    return Response.json(page);
+    return await jsonWithETag(request, page);
   } catch (error) {
     if (error instanceof Error && error.message === "invalid_cursor") {
       return Response.json({ error: "invalid_cursor" }, { status: 400 });
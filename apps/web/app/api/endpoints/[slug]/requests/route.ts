@@ -1,4 +1,5 @@
 import { authenticateRequest } from "@/lib/api-auth";
+import { jsonWithETag } from "@/lib/http";
 import {
   clearRequestsForEndpointByUser,
   listRequestsForEndpointByUser,
@@ -13,6 +14,7 @@ export async function GET(request: Request, { params }: { params: Promise<{ slug
 
   const limit = url.searchParams.get("limit");
   const since = url.searchParams.get("since");
+  const pinned = url.searchParams.get("pinned") === "true";
   const parsedLimit = limit ? Number(limit) : undefined;
   const parsedSince = since ? Number(since) : undefined;
 
@@ -29,13 +31,14 @@ export async function GET(request: Request, { params }: { params: Promise<{ slug
       slug,
       limit: parsedLimit,
       since: parsedSince,
+      pinned,
     });
 
     if (!data) {
       return Response.json({ error: "not_found" }, { status: 404 });
     }
 
-    return Response.json(data);
+    return await jsonWithETag(request, data);
   } catch (error) {
     console.error("Failed to list requests:", error);
     return Response.json({ error: "Failed to list requests" }, { status: 500 });
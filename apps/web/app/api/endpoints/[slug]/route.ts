@@ -56,7 +56,7 @@ export async function PATCH(request: Request, { params }: { params: Promise<{ sl
     const mr = body.mockResponse as Record<string, unknown>;
     if (
       mr.status !== undefined &&
-      (typeof mr.status !== "number" || mr.status < 100 || mr.status > 599)
+      (typeof mr.status !== "number" || mr.status < 100 || mr.status > 999)
     ) {
       return Response.json({ error: "Invalid status code" }, { status: 400 });
     }
@@ -3,6 +3,7 @@ import {
   extractBearerToken,
   validateBearerTokenWithPlan,
 } from "@/lib/api-auth";
+import { jsonWithETag } from "@/lib/http";
 import { parseJsonBody } from "@/lib/request-validation";
 import { checkRateLimitByKeyWithInfo, applyRateLimitHeaders } from "@/lib/rate-limit";
 import { createEndpointForUser, listEndpointsForUser } from "@/lib/supabase/endpoints";
@@ -43,7 +44,7 @@ export async function GET(request: Request) {
       fromTeam: ep.fromTeam,
     }));
 
-    return Response.json({ owned, shared });
+    return await jsonWithETag(request, { owned, shared });
   } catch (error) {
     console.error("Failed to list endpoints:", error);
     return Response.json({ error: "Internal server error" }, { status: 500 });
@@ -92,7 +93,7 @@ export async function POST(request: Request) {
     if (
       typeof mr.status !== "number" ||
       mr.status < 100 ||
-      mr.status > 599 ||
+      mr.status > 999 ||
       !Number.isInteger(mr.status)
     ) {
       return Response.json({ error: "Invalid status code" }, { status: 400 });
@@ -0,0 +1,191 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { serverEnv } from "@/lib/env";
+import { resolveEndpointAccess } from "@/lib/supabase/teams";
+import type { Database } from "@/lib/supabase/database";
+import { toPlatformEvent } from "@/lib/supabase/events";
+import { sendError } from "@appsignal/nodejs";
+import { createClient, type RealtimeChannel } from "@supabase/supabase-js";
+
+export const dynamic = "force-dynamic";
+
+const KEEPALIVE_INTERVAL_MS = 30_000;
+const MAX_CONNECTION_DURATION_MS = 30 * 60 * 1000;
+
+type EndpointEventRow = Database["public"]["Tables"]["endpoint_events"]["Row"];
+
+function createRealtimeAdminClient() {
+  const env = serverEnv();
+  return createClient<Database>(env.SUPABASE_URL, env.SUPABASE_SERVICE_ROLE_KEY, {
+    auth: {
+      autoRefreshToken: false,
+      persistSession: false,
+    },
+  });
+}
+
+async function waitForSubscribed(channel: RealtimeChannel): Promise<void> {
+  await new Promise<void>((resolve, reject) => {
+    const timeout = setTimeout(() => {
+      reject(new Error("Timed out waiting for realtime subscription"));
+    }, 10_000);
+
+    channel.subscribe((status) => {
+      if (status === "SUBSCRIBED") {
+        clearTimeout(timeout);
+        resolve();
+      }
+
+      if (status === "CHANNEL_ERROR" || status === "TIMED_OUT") {
+        clearTimeout(timeout);
+        reject(new Error(`Realtime subscription failed with status ${status}`));
+      }
+    });
+  });
+}
+
+/**
+ * Streams an endpoint's platform events as they are recorded (see
+ * migration 00022). Past events are listed by /api/endpoints/[slug]/events.
+ */
+export async function GET(request: Request, { params }: { params: Promise<{ slug: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { slug } = await params;
+
+  const access = await resolveEndpointAccess(auth.userId, slug);
+  if (!access) {
+    return Response.json({ error: "Endpoint not found" }, { status: 404 });
+  }
+  const endpointId = access.endpointId;
+
+  const encoder = new TextEncoder();
+  const connectionStart = Date.now();
+
+  const stream = new ReadableStream({
+    async start(controller) {
+      controller.enqueue(
+        encoder.encode(`event: connected\ndata: ${JSON.stringify({ slug, endpointId })}\n\n`)
+      );
+
+      const abortSignal = request.signal;
+      const supabase = createRealtimeAdminClient();
+      let keepaliveTimer: ReturnType<typeof setInterval> | null = null;
+      let durationTimer: ReturnType<typeof setTimeout> | null = null;
+      let closed = false;
+      let eventsChannel: RealtimeChannel | null = null;
+      let endpointChannel: RealtimeChannel | null = null;
+
+      const cleanup = () => {
+        if (keepaliveTimer) {
+          clearInterval(keepaliveTimer);
+          keepaliveTimer = null;
+        }
+        if (durationTimer) {
+          clearTimeout(durationTimer);
+          durationTimer = null;
+        }
+        if (eventsChannel) {
+          void supabase.removeChannel(eventsChannel);
+          eventsChannel = null;
+        }
+        if (endpointChannel) {
+          void supabase.removeChannel(endpointChannel);
+          endpointChannel = null;
+        }
+        void supabase.realtime.disconnect();
+      };
+
+      const closeStream = () => {
+        if (closed) return;
+        closed = true;
+        cleanup();
+        try {
+          controller.close();
+        } catch {
+          // Stream may already be closed.
+        }
+      };
+
+      abortSignal.addEventListener("abort", closeStream);
+
+      keepaliveTimer = setInterval(() => {
+        if (closed || abortSignal.aborted) return;
+        try {
+          controller.enqueue(encoder.encode(": keepalive\n\n"));
+        } catch {
+          closeStream();
+        }
+      }, KEEPALIVE_INTERVAL_MS);
+
+      durationTimer = setTimeout(() => {
+        if (closed || abortSignal.aborted) return;
+        try {
+          controller.enqueue(
+            encoder.encode(
+              `event: timeout\ndata: ${JSON.stringify({ reason: "max_duration" })}\n\n`
+            )
+          );
+        } catch {
+          // Stream may already be closed.
+        }
+        closeStream();
+      }, MAX_CONNECTION_DURATION_MS);
+
+      eventsChannel = supabase.channel(`events:${endpointId}:${connectionStart}`).on(
+        "postgres_changes",
+        {
+          event: "INSERT",
+          schema: "public",
+          table: "endpoint_events",
+          filter: `endpoint_id=eq.${endpointId}`,
+        },
+        (payload) => {
+          if (closed) return;
+          try {
+            const event = toPlatformEvent(payload.new as EndpointEventRow);
+            controller.enqueue(encoder.encode(`event: event\ndata: ${JSON.stringify(event)}\n\n`));
+          } catch (error) {
+            sendError(error instanceof Error ? error : new Error(String(error)));
+          }
+        }
+      );
+
+      endpointChannel = supabase.channel(`events:endpoint:${endpointId}:${connectionStart}`).on(
+        "postgres_changes",
+        {
+          event: "DELETE",
+          schema: "public",
+          table: "endpoints",
+          filter: `id=eq.${endpointId}`,
+        },
+        () => {
+          try {
+            controller.enqueue(
+              encoder.encode(`event: endpoint_deleted\ndata: ${JSON.stringify({ slug })}\n\n`)
+            );
+          } catch {
+            // Stream may already be closed.
+          }
+          closeStream();
+        }
+      );
+
+      try {
+        await Promise.all([waitForSubscribed(eventsChannel), waitForSubscribed(endpointChannel)]);
+      } catch (error) {
+        sendError(error instanceof Error ? error : new Error(String(error)));
+        console.error("Failed to initialize event stream:", error);
+        closeStream();
+      }
+    },
+  });
+
+  return new Response(stream, {
+    headers: {
+      "Content-Type": "text/event-stream",
+      "Cache-Control": "no-cache, no-transform",
+      Connection: "keep-alive",
+    },
+  });
+}
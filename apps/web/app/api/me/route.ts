@@ -0,0 +1,19 @@
+import { authenticateRequest, extractBearerToken } from "@/lib/api-auth";
+import { getMeForToken } from "@/lib/supabase/account";
+
+export async function GET(request: Request) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  try {
+    const me = await getMeForToken(auth.userId, extractBearerToken(request) ?? "");
+    if (!me) {
+      return Response.json({ error: "User not found" }, { status: 404 });
+    }
+
+    return Response.json(me);
+  } catch (error) {
+    console.error("Failed to fetch account:", error);
+    return Response.json({ error: "Internal server error" }, { status: 500 });
+  }
+}
@@ -0,0 +1,114 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import {
+  type ForwardAttemptRecord,
+  listForwardAttemptsForUser,
+  recordForwardAttemptForUser,
+} from "@/lib/supabase/requests";
+
+const MAX_TARGET_LENGTH = 2048;
+const MAX_ERROR_LENGTH = 1000;
+
+function isNonNegativeInteger(value: unknown): value is number {
+  return typeof value === "number" && Number.isInteger(value) && value >= 0;
+}
+
+/**
+ * Validate a reported attempt; returns an error message if it's invalid.
+ * Clients can only report tunnel attempts: "receiver" attempts are recorded
+ * by the platform itself, so a client can't pass its own off as one.
+ */
+function validateAttempt(body: Record<string, unknown>): string | null {
+  if (body.source !== undefined && body.source !== "tunnel") {
+    return 'Invalid source: only "tunnel" attempts can be reported';
+  }
+  if (typeof body.target !== "string" || !body.target || body.target.length > MAX_TARGET_LENGTH) {
+    return `Invalid target: must be a string of at most ${MAX_TARGET_LENGTH} characters`;
+  }
+  if (!isNonNegativeInteger(body.attempt) || body.attempt < 1) {
+    return "Invalid attempt: must be a positive integer";
+  }
+  if (
+    body.statusCode !== undefined &&
+    (!isNonNegativeInteger(body.statusCode) || body.statusCode < 100 || body.statusCode > 599)
+  ) {
+    return "Invalid statusCode: must be an HTTP status code";
+  }
+  if (!isNonNegativeInteger(body.latencyMs)) {
+    return "Invalid latencyMs: must be a non-negative integer";
+  }
+  if (
+    body.error !== undefined &&
+    (typeof body.error !== "string" || body.error.length > MAX_ERROR_LENGTH)
+  ) {
+    return `Invalid error: must be a string of at most ${MAX_ERROR_LENGTH} characters`;
+  }
+  if (!isNonNegativeInteger(body.attemptedAt)) {
+    return "Invalid attemptedAt: must be a timestamp in milliseconds";
+  }
+  return null;
+}
+
+export async function GET(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  try {
+    const attempts = await listForwardAttemptsForUser(auth.userId, id);
+    if (!attempts) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    return Response.json({ requestId: id, attempts });
+  } catch (error) {
+    console.error("Failed to list forward attempts:", error);
+    return Response.json({ error: "Failed to list forward attempts" }, { status: 500 });
+  }
+}
+
+export async function POST(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  let body: unknown;
+  try {
+    body = await request.json();
+  } catch {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+
+  if (typeof body !== "object" || body === null) {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+  const fields = body as Record<string, unknown>;
+  const invalid = validateAttempt(fields);
+  if (invalid) {
+    return Response.json({ error: invalid }, { status: 400 });
+  }
+
+  const attempt: ForwardAttemptRecord = {
+    requestId: id,
+    source: "tunnel",
+    target: fields.target as string,
+    attempt: fields.attempt as number,
+    statusCode: fields.statusCode as number | undefined,
+    latencyMs: fields.latencyMs as number,
+    error: fields.error as string | undefined,
+    attemptedAt: fields.attemptedAt as number,
+  };
+
+  try {
+    const recorded = await recordForwardAttemptForUser(auth.userId, attempt);
+    if (!recorded) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    return new Response(null, { status: 204 });
+  } catch (error) {
+    console.error("Failed to record forward attempt:", error);
+    return Response.json({ error: "Failed to record forward attempt" }, { status: 500 });
+  }
+}
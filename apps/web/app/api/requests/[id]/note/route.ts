@@ -0,0 +1,38 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { MAX_REQUEST_NOTE_LENGTH, setRequestNoteForUser } from "@/lib/supabase/requests";
+
+export async function PUT(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  let body: { note?: unknown };
+  try {
+    body = (await request.json()) as { note?: unknown };
+  } catch {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+
+  if (typeof body.note !== "string" || body.note.length > MAX_REQUEST_NOTE_LENGTH) {
+    return Response.json(
+      { error: `Invalid note: must be a string of at most ${MAX_REQUEST_NOTE_LENGTH} characters` },
+      { status: 400 }
+    );
+  }
+
+  // An empty note removes it
+  const note = body.note.trim() || null;
+
+  try {
+    const updated = await setRequestNoteForUser(auth.userId, id, note);
+    if (!updated) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    return new Response(null, { status: 204 });
+  } catch (error) {
+    console.error("Failed to update request note:", error);
+    return Response.json({ error: "Failed to update request note" }, { status: 500 });
+  }
+}
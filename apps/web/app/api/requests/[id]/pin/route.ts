@@ -0,0 +1,39 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { setRequestPinnedForUser } from "@/lib/supabase/requests";
+
+async function setPinned(
+  request: Request,
+  params: Promise<{ id: string }>,
+  pinned: boolean
+): Promise<Response> {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  try {
+    const result = await setRequestPinnedForUser(auth.userId, id, pinned);
+    switch (result) {
+      case "not_found":
+        return Response.json({ error: "not_found" }, { status: 404 });
+      case "limit_reached":
+        return Response.json(
+          { error: "Pin limit reached for this plan: unpin a request first" },
+          { status: 400 }
+        );
+    }
+
+    return new Response(null, { status: 204 });
+  } catch (error) {
+    console.error("Failed to update request pin:", error);
+    return Response.json({ error: "Failed to update request pin" }, { status: 500 });
+  }
+}
+
+export async function PUT(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  return setPinned(request, params, true);
+}
+
+export async function DELETE(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  return setPinned(request, params, false);
+}
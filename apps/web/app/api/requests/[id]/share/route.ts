@@ -0,0 +1,49 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import { createRequestShareForUser, MAX_SHARE_TTL_MS } from "@/lib/supabase/requests";
+
+export async function POST(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  let body: { expiresAt?: unknown };
+  try {
+    body = (await request.json()) as { expiresAt?: unknown };
+  } catch {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+
+  // expiresAt is a Unix timestamp in milliseconds, at most 30 days away
+  const now = Date.now();
+  if (
+    typeof body.expiresAt !== "number" ||
+    !Number.isInteger(body.expiresAt) ||
+    body.expiresAt <= now ||
+    body.expiresAt > now + MAX_SHARE_TTL_MS
+  ) {
+    return Response.json(
+      { error: "Invalid expiresAt: must be a future timestamp within 30 days" },
+      { status: 400 }
+    );
+  }
+
+  try {
+    const token = await createRequestShareForUser(auth.userId, id, body.expiresAt);
+    if (!token) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    const appUrl = process.env.NEXT_PUBLIC_APP_URL || "https://webhooks.cc";
+    return Response.json(
+      {
+        url: `${appUrl}/api/shares/${token}`,
+        expiresAt: new Date(body.expiresAt).toISOString(),
+      },
+      { status: 201 }
+    );
+  } catch (error) {
+    console.error("Failed to share request:", error);
+    return Response.json({ error: "Failed to share request" }, { status: 500 });
+  }
+}
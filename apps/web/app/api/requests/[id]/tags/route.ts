@@ -0,0 +1,64 @@
+import { authenticateRequest } from "@/lib/api-auth";
+import {
+  MAX_REQUEST_TAGS,
+  normalizeRequestTag,
+  updateRequestTagsForUser,
+} from "@/lib/supabase/requests";
+
+/** Normalize a list of tags from the request body, or null if it isn't one. */
+function parseTags(value: unknown): string[] | null {
+  if (value === undefined) return [];
+  if (!Array.isArray(value) || value.length > MAX_REQUEST_TAGS) return null;
+
+  const tags: string[] = [];
+  for (const item of value) {
+    const tag = typeof item === "string" ? normalizeRequestTag(item) : null;
+    if (!tag) return null;
+    if (!tags.includes(tag)) tags.push(tag);
+  }
+  return tags;
+}
+
+export async function PATCH(request: Request, { params }: { params: Promise<{ id: string }> }) {
+  const auth = await authenticateRequest(request);
+  if (!auth.success) return auth.response;
+
+  const { id } = await params;
+
+  let body: { add?: unknown; remove?: unknown };
+  try {
+    body = (await request.json()) as { add?: unknown; remove?: unknown };
+  } catch {
+    return Response.json({ error: "Invalid JSON body" }, { status: 400 });
+  }
+
+  const add = parseTags(body.add);
+  const remove = parseTags(body.remove);
+  if (!add || !remove) {
+    return Response.json(
+      { error: "Invalid tags: expected arrays of tags of 1-64 characters of [a-z0-9._:/-]" },
+      { status: 400 }
+    );
+  }
+  if (add.some((tag) => remove.includes(tag))) {
+    return Response.json({ error: "A tag can't be both added and removed" }, { status: 400 });
+  }
+
+  try {
+    const result = await updateRequestTagsForUser(auth.userId, id, add, remove);
+    switch (result.status) {
+      case "not_found":
+        return Response.json({ error: "not_found" }, { status: 404 });
+      case "limit_reached":
+        return Response.json(
+          { error: `A request can have at most ${MAX_REQUEST_TAGS} tags` },
+          { status: 400 }
+        );
+    }
+
+    return Response.json({ tags: result.tags });
+  } catch (error) {
+    console.error("Failed to update request tags:", error);
+    return Response.json({ error: "Failed to update request tags" }, { status: 500 });
+  }
+}
@@ -1,4 +1,5 @@
 import { extractBearerToken, validateBearerTokenWithPlan } from "@/lib/api-auth";
+import { scopesAllowRequest } from "@/lib/api-key-scopes";
 import { checkRateLimitByKeyWithInfo, applyRateLimitHeaders, type RateLimitInfo } from "@/lib/rate-limit";
 import { countSearchRequestsForUser } from "@/lib/supabase/search";
 import { sendError } from "@appsignal/nodejs";
@@ -32,6 +33,12 @@ export async function GET(request: Request) {
     if (!validated) {
       return Response.json({ error: "Invalid token" }, { status: 401 });
     }
+    if (!scopesAllowRequest(validated.scopes, request)) {
+      return Response.json(
+        { error: "This API key's scope does not allow this request" },
+        { status: 403 }
+      );
+    }
 
     const userId = validated.userId;
     const plan = validated.plan;
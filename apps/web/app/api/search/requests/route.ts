@@ -1,4 +1,6 @@
 import { extractBearerToken, validateBearerTokenWithPlan } from "@/lib/api-auth";
+import { jsonWithETag } from "@/lib/http";
+import { scopesAllowRequest } from "@/lib/api-key-scopes";
 import { checkRateLimitByKeyWithInfo, applyRateLimitHeaders, type RateLimitInfo } from "@/lib/rate-limit";
 import { searchRequestsForUser } from "@/lib/supabase/search";
 import { sendError } from "@appsignal/nodejs";
@@ -32,6 +34,12 @@ export async function GET(request: Request) {
     if (!validated) {
       return Response.json({ error: "Invalid token" }, { status: 401 });
     }
+    if (!scopesAllowRequest(validated.scopes, request)) {
+      return Response.json(
+        { error: "This API key's scope does not allow this request" },
+        { status: 403 }
+      );
+    }
 
     const userId = validated.userId;
     const plan = validated.plan;
@@ -73,7 +81,7 @@ export async function GET(request: Request) {
       order: order === "asc" ? "asc" : "desc",
     });
 
-    return applyRateLimitHeaders(Response.json(data), rateLimit);
+    return applyRateLimitHeaders(await jsonWithETag(request, data), rateLimit);
   } catch (err) {
     sendError(err instanceof Error ? err : new Error(String(err)));
     console.error("Search API route error:", err);
@@ -0,0 +1,23 @@
+import { checkRateLimit } from "@/lib/rate-limit";
+import { getSharedRequest } from "@/lib/supabase/requests";
+
+// Public: anyone with the link may view the request until it expires
+
+export async function GET(request: Request, { params }: { params: Promise<{ token: string }> }) {
+  const rateLimited = checkRateLimit(request, 60);
+  if (rateLimited) return rateLimited;
+
+  const { token } = await params;
+
+  try {
+    const shared = await getSharedRequest(token);
+    if (!shared) {
+      return Response.json({ error: "not_found" }, { status: 404 });
+    }
+
+    return Response.json(shared, { headers: { "Cache-Control": "private, no-store" } });
+  } catch (error) {
+    console.error("Failed to load shared request:", error);
+    return Response.json({ error: "Internal server error" }, { status: 500 });
+  }
+}
@@ -50,6 +50,8 @@ function toRequestRecord(row: RequestRow): RequestRecord {
     ip: row.ip,
     size: row.size,
     receivedAt: parseMillis(row.received_at),
+    responseStatus: row.response_status ?? undefined,
+    tags: row.tags.length > 0 ? row.tags : undefined,
   };
 }
 
@@ -67,6 +69,8 @@ function toStreamRequest(record: RequestRecord) {
     ip: record.ip,
     size: record.size,
     receivedAt: record.receivedAt,
+    responseStatus: record.responseStatus,
+    tags: record.tags,
   };
 }
 
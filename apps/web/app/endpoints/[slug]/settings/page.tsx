@@ -368,7 +368,7 @@ function EndpointSettingsForm() {
                 onChange={(e) => setMockStatus(e.target.value)}
                 placeholder="200"
                 min={100}
-                max={599}
+                max={999}
               />
             </div>
 
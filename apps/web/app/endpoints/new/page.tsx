@@ -101,7 +101,7 @@ function NewEndpointForm() {
                 onChange={(e) => setMockStatus(e.target.value)}
                 placeholder="200"
                 min={100}
-                max={599}
+                max={999}
               />
             </div>
 
@@ -3,6 +3,7 @@
  *
  * Validates API keys and Supabase session tokens against Supabase.
  */
+import { scopesAllowRequest } from "./api-key-scopes";
 import { createAdminClient } from "./supabase/admin";
 import { validateApiKeyWithMetadata } from "./supabase/api-keys";
 
@@ -11,6 +12,8 @@ export type UserPlan = "free" | "pro";
 export interface ApiKeyValidation {
   userId: string;
   plan?: UserPlan;
+  /** Scopes of an API key; empty or missing means full access. */
+  scopes?: string[];
 }
 
 async function validateSupabaseSessionWithPlan(
@@ -76,6 +79,7 @@ export async function validateApiKeyWithPlan(apiKey: string): Promise<ApiKeyVali
 
 /**
  * Authenticate a request using a Bearer API key or Supabase session token.
+ * Scoped API keys are rejected with 403 for requests outside their scopes.
  * Returns { success: true, userId } on success, or { success: false, response } on failure.
  */
 export type AuthResult = { success: true; userId: string } | { success: false; response: Response };
@@ -92,8 +96,8 @@ export async function authenticateRequest(request: Request): Promise<AuthResult>
     };
   }
 
-  const userId = await validateApiKey(token);
-  if (!userId) {
+  const validation = await validateBearerTokenWithPlan(token);
+  if (!validation) {
     return {
       success: false,
       response: new Response(JSON.stringify({ error: "Invalid token" }), {
@@ -103,7 +107,22 @@ export async function authenticateRequest(request: Request): Promise<AuthResult>
     };
   }
 
-  return { success: true, userId };
+  if (!scopesAllowRequest(validation.scopes, request)) {
+    return {
+      success: false,
+      response: apiKeyScopeError(),
+    };
+  }
+
+  return { success: true, userId: validation.userId };
+}
+
+/** 403 response for an API key whose scopes don't cover the request. */
+function apiKeyScopeError(): Response {
+  return new Response(
+    JSON.stringify({ error: "This API key's scope does not allow this request" }),
+    { status: 403, headers: { "Content-Type": "application/json" } }
+  );
 }
 
 /**
@@ -0,0 +1,50 @@
+/**
+ * @fileoverview API key scopes.
+ *
+ * A key with no scopes has full access. "read" keys may only make GET
+ * requests; "capture" keys may only read a single endpoint, its requests
+ * and its stream, and report forward attempts, which is what `whk listen`
+ * and `whk tunnel` need. Any key may describe itself with /api/me. API keys themselves are only managed with a
+ * session token (see authenticateSessionRequest).
+ */
+
+export const API_KEY_SCOPES = ["read", "capture"] as const;
+export type ApiKeyScope = (typeof API_KEY_SCOPES)[number];
+
+const CAPTURE_PATHS = [
+  /^\/api\/me$/,
+  /^\/api\/endpoints\/[^/]+$/,
+  /^\/api\/endpoints\/[^/]+\/requests$/,
+  /^\/api\/stream\/[^/]+$/,
+];
+
+const CAPTURE_REPORT_PATH = /^\/api\/requests\/[^/]+\/forwards$/;
+
+function isApiKeyScope(value: unknown): value is ApiKeyScope {
+  return typeof value === "string" && (API_KEY_SCOPES as readonly string[]).includes(value);
+}
+
+/**
+ * Parse the scopes of a key being created. Missing scopes mean full access.
+ * Returns null if the value is not an array of known scopes.
+ */
+export function parseApiKeyScopes(value: unknown): ApiKeyScope[] | null {
+  if (value === undefined || value === null) return [];
+  if (!Array.isArray(value) || !value.every(isApiKeyScope)) return null;
+  return [...new Set(value)];
+}
+
+/** Whether a key with these scopes may make this request. */
+export function scopesAllowRequest(scopes: readonly string[] | undefined, request: Request): boolean {
+  if (!scopes || scopes.length === 0) return true;
+  const { pathname } = new URL(request.url);
+  if (request.method === "POST") {
+    return scopes.includes("capture") && CAPTURE_REPORT_PATH.test(pathname);
+  }
+  if (request.method !== "GET" && request.method !== "HEAD") return false;
+  if (scopes.includes("read")) return true;
+  if (scopes.includes("capture")) {
+    return CAPTURE_PATHS.some((pattern) => pattern.test(pathname));
+  }
+  return false;
+}
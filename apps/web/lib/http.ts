@@ -2,14 +2,14 @@
  * HTTP-related utility functions.
  */
 
-/** Valid HTTP status code range */
+/** Status codes the receiver can send: any three-digit code */
 const MIN_STATUS_CODE = 100;
-const MAX_STATUS_CODE = 599;
+const MAX_STATUS_CODE = 999;
 
 /**
  * Parses a string value into an HTTP status code.
  * Returns the default value if parsing fails, value is NaN,
- * or the value is outside the valid HTTP status code range (100-599).
+ * or the value is outside the status code range (100-999).
  *
  * @param value - String representation of a status code
  * @param defaultValue - Default status code if parsing fails (default: 200)
@@ -22,3 +22,36 @@ export function parseStatusCode(value: string, defaultValue = 200): number {
   }
   return parsed;
 }
+
+/** Whether an If-None-Match header value matches etag (weak comparison). */
+function ifNoneMatchIncludes(header: string | null, etag: string): boolean {
+  if (!header) return false;
+  return header.split(",").some((candidate) => {
+    const tag = candidate.trim();
+    return tag === "*" || tag.replace(/^W\//, "") === etag;
+  });
+}
+
+/**
+ * Builds a JSON response with an ETag derived from its body, answering
+ * 304 Not Modified when the request's If-None-Match already has it. Clients
+ * such as the CLI revalidate cached list responses this way instead of
+ * downloading them again.
+ *
+ * @param request - The request being answered
+ * @param data - The JSON response body
+ * @returns A 200 JSON response, or an empty 304 if the client's copy is current
+ */
+export async function jsonWithETag(request: Request, data: unknown): Promise<Response> {
+  const body = JSON.stringify(data);
+  const digest = await crypto.subtle.digest("SHA-256", new TextEncoder().encode(body));
+  const hex = Array.from(new Uint8Array(digest).slice(0, 16), (byte) =>
+    byte.toString(16).padStart(2, "0")
+  ).join("");
+  const headers = { ETag: `"${hex}"`, "Cache-Control": "private, no-cache" };
+
+  if (ifNoneMatchIncludes(request.headers.get("If-None-Match"), headers.ETag)) {
+    return new Response(null, { status: 304, headers });
+  }
+  return new Response(body, { headers: { ...headers, "Content-Type": "application/json" } });
+}
@@ -1,4 +1,42 @@
 import { createAdminClient } from "./admin";
+import { getApiKeyDetails, type UserPlan } from "./api-keys";
+
+export interface MeInfo {
+  email: string;
+  plan: UserPlan;
+  /** Scopes of the API key making the request; empty means full access. */
+  scopes: string[];
+  /** When the API key expires (Unix ms); missing for keys that don't and for sessions. */
+  expiresAt?: number;
+}
+
+/**
+ * Describe the account and the bearer token a request was made with.
+ * Session tokens have full access and no expiry to report.
+ */
+export async function getMeForToken(userId: string, token: string): Promise<MeInfo | null> {
+  const admin = createAdminClient();
+  const { data: user, error } = await admin
+    .from("users")
+    .select("email, plan")
+    .eq("id", userId)
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+  if (!user || (user.plan !== "free" && user.plan !== "pro")) {
+    return null;
+  }
+
+  const key = token.startsWith("whcc_") ? await getApiKeyDetails(token) : null;
+  return {
+    email: user.email,
+    plan: user.plan,
+    scopes: key?.scopes ?? [],
+    expiresAt: key?.expiresAt ?? undefined,
+  };
+}
 
 export async function deleteAccountForUser(userId: string): Promise<void> {
   const admin = createAdminClient();
@@ -13,6 +13,15 @@ const generateApiKeyBody = customAlphabet(
 export interface ApiKeyValidationResult {
   userId: string;
   plan: UserPlan;
+  /** Scopes the key is limited to; empty means full access. */
+  scopes: string[];
+}
+
+export interface ApiKeyDetails {
+  /** Scopes the key is limited to; empty means full access. */
+  scopes: string[];
+  /** Unix ms, or null if the key doesn't expire. */
+  expiresAt: number | null;
 }
 
 export function generateApiKey(): string {
@@ -36,7 +45,7 @@ export async function validateApiKeyWithMetadata(
 
   const { data: keyRow, error: keyError } = await admin
     .from("api_keys")
-    .select("id, user_id, expires_at")
+    .select("id, user_id, expires_at, scopes")
     .eq("key_hash", keyHash)
     .maybeSingle();
 
@@ -74,5 +83,28 @@ export async function validateApiKeyWithMetadata(
   return {
     userId: keyRow.user_id,
     plan: userRow.plan,
+    scopes: keyRow.scopes ?? [],
+  };
+}
+
+/** Look up the scopes and expiry of an API key. Returns null for unknown keys. */
+export async function getApiKeyDetails(apiKey: string): Promise<ApiKeyDetails | null> {
+  const admin = createAdminClient();
+  const { data, error } = await admin
+    .from("api_keys")
+    .select("scopes, expires_at")
+    .eq("key_hash", hashApiKey(apiKey))
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+  if (!data) {
+    return null;
+  }
+
+  return {
+    scopes: data.scopes ?? [],
+    expiresAt: data.expires_at ? Date.parse(data.expires_at) : null,
   };
 }
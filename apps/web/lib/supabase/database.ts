@@ -12,6 +12,7 @@ export interface Database {
           name: string;
           last_used_at: string | null;
           expires_at: string | null;
+          scopes: string[];
           created_at: string;
         };
         Insert: {
@@ -22,6 +23,7 @@ export interface Database {
           name: string;
           last_used_at?: string | null;
           expires_at?: string | null;
+          scopes?: string[];
           created_at?: string;
         };
         Update: {
@@ -32,6 +34,7 @@ export interface Database {
           name?: string;
           last_used_at?: string | null;
           expires_at?: string | null;
+          scopes?: string[];
           created_at?: string;
         };
         Relationships: [];
@@ -113,6 +116,7 @@ export interface Database {
           expires_at: string;
           status: "pending" | "authorized";
           user_id: string | null;
+          scopes: string[];
           created_at: string;
         };
         Insert: {
@@ -122,6 +126,7 @@ export interface Database {
           expires_at: string;
           status?: "pending" | "authorized";
           user_id?: string | null;
+          scopes?: string[];
           created_at?: string;
         };
         Update: {
@@ -131,6 +136,55 @@ export interface Database {
           expires_at?: string;
           status?: "pending" | "authorized";
           user_id?: string | null;
+          scopes?: string[];
+          created_at?: string;
+        };
+        Relationships: [];
+      };
+      endpoint_aliases: {
+        Row: {
+          alias: string;
+          endpoint_id: string;
+          created_at: string;
+        };
+        Insert: {
+          alias: string;
+          endpoint_id: string;
+          created_at?: string;
+        };
+        Update: {
+          alias?: string;
+          endpoint_id?: string;
+          created_at?: string;
+        };
+        Relationships: [];
+      };
+      endpoint_events: {
+        Row: {
+          id: string;
+          endpoint_id: string;
+          type: "quota_denied" | "mock_served" | "forward_attempt" | "size_warning";
+          request_id: string | null;
+          message: string;
+          details: Json;
+          created_at: string;
+        };
+        Insert: {
+          id?: string;
+          endpoint_id: string;
+          type: "quota_denied" | "mock_served" | "forward_attempt" | "size_warning";
+          request_id?: string | null;
+          message: string;
+          details?: Json;
+          created_at?: string;
+        };
+        Update: {
+          id?: string;
+          endpoint_id?: string;
+          type?: "quota_denied" | "mock_served" | "forward_attempt" | "size_warning";
+          request_id?: string | null;
+          message?: string;
+          details?: Json;
           created_at?: string;
         };
         Relationships: [];
@@ -171,6 +225,66 @@ export interface Database {
         };
         Relationships: [];
       };
+      forward_attempts: {
+        Row: {
+          id: string;
+          request_id: string;
+          source: "tunnel" | "receiver";
+          target: string;
+          attempt: number;
+          status_code: number | null;
+          latency_ms: number;
+          error: string | null;
+          attempted_at: string;
+        };
+        Insert: {
+          id?: string;
+          request_id: string;
+          source: "tunnel" | "receiver";
+          target: string;
+          attempt: number;
+          status_code?: number | null;
+          latency_ms: number;
+          error?: string | null;
+          attempted_at?: string;
+        };
+        Update: {
+          id?: string;
+          request_id?: string;
+          source?: "tunnel" | "receiver";
+          target?: string;
+          attempt?: number;
+          status_code?: number | null;
+          latency_ms?: number;
+          error?: string | null;
+          attempted_at?: string;
+        };
+        Relationships: [];
+      };
+      request_shares: {
+        Row: {
+          token_hash: string;
+          request_id: string;
+          created_by: string;
+          expires_at: string;
+          created_at: string;
+        };
+        Insert: {
+          token_hash: string;
+          request_id: string;
+          created_by: string;
+          expires_at: string;
+          created_at?: string;
+        };
+        Update: {
+          token_hash?: string;
+          request_id?: string;
+          created_by?: string;
+          expires_at?: string;
+          created_at?: string;
+        };
+        Relationships: [];
+      };
       requests: {
         Row: {
           id: string;
@@ -185,6 +299,10 @@ export interface Database {
           ip: string;
           size: number;
           received_at: string;
+          pinned: boolean;
+          note: string | null;
+          response_status: number | null;
+          tags: string[];
         };
         Insert: {
           id?: string;
@@ -199,6 +317,10 @@ export interface Database {
           ip: string;
           size?: number;
           received_at?: string;
+          pinned?: boolean;
+          note?: string | null;
+          response_status?: number | null;
+          tags?: string[];
         };
         Update: {
           id?: string;
@@ -213,6 +335,10 @@ export interface Database {
           ip?: string;
           size?: number;
           received_at?: string;
+          pinned?: boolean;
+          note?: string | null;
+          response_status?: number | null;
+          tags?: string[];
         };
         Relationships: [];
       };
@@ -309,6 +435,14 @@ export interface Database {
         };
         Returns: number;
       };
+      update_request_tags: {
+        Args: {
+          p_request_id: string;
+          p_add: string[];
+          p_remove: string[];
+        };
+        Returns: string[] | null;
+      };
     };
     Enums: Record<string, never>;
     CompositeTypes: Record<string, never>;
@@ -1,4 +1,5 @@
 import { customAlphabet } from "nanoid";
+import type { ApiKeyScope } from "@/lib/api-key-scopes";
 import { createAdminClient } from "./admin";
 import { generateApiKey, hashApiKey, MAX_KEYS_PER_USER } from "./api-keys";
 
@@ -16,6 +17,7 @@ export interface DeviceCodeRecord {
   deviceCode: string;
   userCode: string;
   expiresAt: number;
+  scopes: ApiKeyScope[];
 }
 
 export interface DeviceCodeStatus {
@@ -31,6 +33,7 @@ export interface ClaimedDeviceCode {
   apiKey: string;
   userId: string;
   email: string;
+  scopes: string[];
 }
 
 type DeviceCodeRow = {
@@ -40,6 +43,7 @@ type DeviceCodeRow = {
   expires_at: string;
   status: "pending" | "authorized";
   user_id: string | null;
+  scopes: string[];
 };
 
 function isExpired(timestamp: string): boolean {
@@ -50,7 +54,7 @@ async function findDeviceCodeByUserCode(userCode: string): Promise<DeviceCodeRow
   const admin = createAdminClient();
   const { data, error } = await admin
     .from("device_codes")
-    .select("id, device_code, user_code, expires_at, status, user_id")
+    .select("id, device_code, user_code, expires_at, status, user_id, scopes")
     .eq("user_code", userCode.toUpperCase())
     .maybeSingle();
 
@@ -65,7 +69,7 @@ async function findDeviceCodeByCode(deviceCode: string): Promise<DeviceCodeRow |
   const admin = createAdminClient();
   const { data, error } = await admin
     .from("device_codes")
-    .select("id, device_code, user_code, expires_at, status, user_id")
+    .select("id, device_code, user_code, expires_at, status, user_id, scopes")
     .eq("device_code", deviceCode)
     .maybeSingle();
 
@@ -76,7 +80,13 @@ async function findDeviceCodeByCode(deviceCode: string): Promise<DeviceCodeRow |
   return data;
 }
 
-export async function createDeviceCodeRecord(): Promise<DeviceCodeRecord> {
+/**
+ * Start a device authorization. The key issued when the code is claimed is
+ * limited to scopes; none means full access.
+ */
+export async function createDeviceCodeRecord(
+  scopes: ApiKeyScope[] = []
+): Promise<DeviceCodeRecord> {
   const admin = createAdminClient();
   const { count, error: countError } = await admin
     .from("device_codes")
@@ -99,6 +109,7 @@ export async function createDeviceCodeRecord(): Promise<DeviceCodeRecord> {
     device_code: deviceCode,
     user_code: userCode,
     expires_at: new Date(expiresAt).toISOString(),
+    scopes,
   });
 
   if (error) {
@@ -109,6 +120,7 @@ export async function createDeviceCodeRecord(): Promise<DeviceCodeRecord> {
     deviceCode,
     userCode,
     expiresAt,
+    scopes,
   };
 }
 
@@ -226,6 +238,7 @@ export async function claimDeviceCode(deviceCode: string): Promise<ClaimedDevice
     key_prefix: rawKey.slice(0, 12),
     name: "CLI (device auth)",
     expires_at: new Date(Date.now() + API_KEY_TTL_MS).toISOString(),
+    scopes: code.scopes,
   });
 
   if (insertError) {
@@ -246,5 +259,6 @@ export async function claimDeviceCode(deviceCode: string): Promise<ClaimedDevice
     apiKey: rawKey,
     userId: code.user_id,
     email: user?.email ?? "",
+    scopes: code.scopes,
   };
 }
@@ -5,6 +5,22 @@ import type { Database, Json } from "./database";
 const DEFAULT_EPHEMERAL_TTL_MS = 12 * 60 * 60 * 1000;
 const MAX_EPHEMERAL_ENDPOINTS = 500;
 const MAX_SLUG_ATTEMPTS = 5;
+const MIN_CUSTOM_SLUG_LENGTH = 3;
+const MAX_SLUG_LENGTH = 50;
+const MAX_ALIASES_PER_ENDPOINT = 10;
+// Names that can't be chosen as aliases, because they name parts of the
+// service or could be mistaken for them. Kept in sync with the CLI.
+const RESERVED_SLUGS = new Set([
+  "admin",
+  "api",
+  "app",
+  "docs",
+  "health",
+  "internal",
+  "status",
+  "webhooks",
+  "www",
+]);
 const nanoidSlug = customAlphabet("0123456789abcdefghijklmnopqrstuvwxyz", 10);
 
 type EndpointRow = Database["public"]["Tables"]["endpoints"]["Row"];
@@ -36,9 +52,12 @@ export interface EndpointRecord {
   };
   isEphemeral?: boolean;
   expiresAt?: number;
+  aliases?: string[];
   createdAt: number;
 }
 
+export type AddEndpointAliasResult = "added" | "not_found" | "taken" | "limit_reached";
+
 interface CreateEndpointInput {
   userId?: string;
   name?: string;
@@ -107,22 +126,67 @@ function normalizeEndpoint(row: SelectedEndpointRow): EndpointRecord {
   };
 }
 
-async function generateUniqueSlug(): Promise<string> {
+/**
+ * Normalize an alias chosen by a user. Returns null unless it is 3-50
+ * characters of a-z, 0-9, - and _, starts and ends with a letter or digit,
+ * and is not reserved.
+ */
+export function normalizeEndpointAlias(value: unknown): string | null {
+  if (typeof value !== "string") return null;
+  const alias = value.toLowerCase();
+  if (alias.length < MIN_CUSTOM_SLUG_LENGTH || alias.length > MAX_SLUG_LENGTH) return null;
+  if (!/^[a-z0-9][a-z0-9_-]*[a-z0-9]$/.test(alias)) return null;
+  return RESERVED_SLUGS.has(alias) ? null : alias;
+}
+
+/** Whether slug is already used as an endpoint slug or alias. */
+async function isSlugInUse(slug: string): Promise<boolean> {
+  const admin = createAdminClient();
+  const normalized = slug.toLowerCase();
+
+  const { data: endpoint, error } = await admin
+    .from("endpoints")
+    .select("id")
+    .eq("slug", normalized)
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+  if (endpoint) return true;
+
+  const { data: alias, error: aliasError } = await admin
+    .from("endpoint_aliases")
+    .select("alias")
+    .eq("alias", normalized)
+    .maybeSingle();
+
+  if (aliasError) {
+    throw aliasError;
+  }
+
+  return !!alias;
+}
+
+async function listAliases(endpointId: string): Promise<string[]> {
   const admin = createAdminClient();
+  const { data, error } = await admin
+    .from("endpoint_aliases")
+    .select("alias")
+    .eq("endpoint_id", endpointId)
+    .order("created_at", { ascending: true });
+
+  if (error) {
+    throw error;
+  }
+
+  return (data ?? []).map((row) => row.alias);
+}
 
+async function generateUniqueSlug(): Promise<string> {
   for (let attempt = 0; attempt < MAX_SLUG_ATTEMPTS; attempt += 1) {
     const slug = nanoidSlug();
-    const { data, error } = await admin
-      .from("endpoints")
-      .select("id")
-      .eq("slug", slug.toLowerCase())
-      .maybeSingle();
-
-    if (error) {
-      throw error;
-    }
-
-    if (!data) {
+    if (!(await isSlugInUse(slug))) {
       return slug;
     }
   }
@@ -198,8 +262,10 @@ export async function getEndpointBySlugForUser(
   if (error) {
     throw error;
   }
+  if (!data) return null;
 
-  return data ? normalizeEndpoint(data) : null;
+  const aliases = await listAliases(data.id);
+  return { ...normalizeEndpoint(data), ...(aliases.length > 0 ? { aliases } : {}) };
 }
 
 export async function createEndpointForUser({
@@ -363,3 +429,74 @@ export async function deleteEndpointBySlugForUser(userId: string, slug: string):
 
   return !!data;
 }
+
+/**
+ * Add an alias to an endpoint the user owns. Requests to /w/<alias> are then
+ * captured by the endpoint. The alias must already be normalized.
+ */
+export async function addEndpointAliasForUser(
+  userId: string,
+  slug: string,
+  alias: string
+): Promise<AddEndpointAliasResult> {
+  const admin = createAdminClient();
+  const endpoint = await findOwnedEndpoint(userId, slug);
+
+  if (!endpoint) {
+    return "not_found";
+  }
+
+  if (await isSlugInUse(alias)) {
+    return "taken";
+  }
+
+  const existing = await listAliases(endpoint.id);
+  if (existing.length >= MAX_ALIASES_PER_ENDPOINT) {
+    return "limit_reached";
+  }
+
+  const { error } = await admin
+    .from("endpoint_aliases")
+    .insert({ alias, endpoint_id: endpoint.id });
+
+  if (error) {
+    // Lost a race with another request for the same alias
+    if (error.code === "23505") {
+      return "taken";
+    }
+    throw error;
+  }
+
+  return "added";
+}
+
+/**
+ * Remove an alias from an endpoint the user owns. Returns false if the
+ * endpoint or alias doesn't exist.
+ */
+export async function removeEndpointAliasForUser(
+  userId: string,
+  slug: string,
+  alias: string
+): Promise<boolean> {
+  const admin = createAdminClient();
+  const endpoint = await findOwnedEndpoint(userId, slug);
+
+  if (!endpoint) {
+    return false;
+  }
+
+  const { data, error } = await admin
+    .from("endpoint_aliases")
+    .delete()
+    .eq("endpoint_id", endpoint.id)
+    .eq("alias", alias.toLowerCase())
+    .select("alias")
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+
+  return !!data;
+}
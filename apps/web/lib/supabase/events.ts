@@ -0,0 +1,91 @@
+import { createAdminClient } from "./admin";
+import type { Database, Json } from "./database";
+import { resolveEndpointAccess } from "./teams";
+
+const DEFAULT_EVENT_LIMIT = 50;
+const MAX_EVENT_LIMIT = 500;
+
+type EndpointEventRow = Database["public"]["Tables"]["endpoint_events"]["Row"];
+export type EndpointEventType = EndpointEventRow["type"];
+
+/** A platform event as returned by the events routes (see types.PlatformEvent). */
+export interface PlatformEventRecord {
+  id: string;
+  type: EndpointEventType;
+  requestId?: string;
+  message: string;
+  details?: Record<string, string>;
+  timestamp: number;
+}
+
+function asStringRecord(value: Json): Record<string, string> {
+  if (!value || typeof value !== "object" || Array.isArray(value)) {
+    return {};
+  }
+
+  return Object.fromEntries(
+    Object.entries(value).filter(([, item]) => typeof item === "string")
+  ) as Record<string, string>;
+}
+
+export function toPlatformEvent(row: EndpointEventRow): PlatformEventRecord {
+  const details = asStringRecord(row.details);
+  return {
+    id: row.id,
+    type: row.type,
+    ...(row.request_id ? { requestId: row.request_id } : {}),
+    message: row.message,
+    ...(Object.keys(details).length > 0 ? { details } : {}),
+    timestamp: Date.parse(row.created_at),
+  };
+}
+
+/** Record a platform event for an endpoint. */
+export async function recordEndpointEvent(event: {
+  endpointId: string;
+  type: EndpointEventType;
+  requestId?: string;
+  message: string;
+  details?: Record<string, string>;
+}): Promise<void> {
+  const admin = createAdminClient();
+  const { error } = await admin.from("endpoint_events").insert({
+    endpoint_id: event.endpointId,
+    type: event.type,
+    request_id: event.requestId ?? null,
+    message: event.message,
+    details: event.details ?? {},
+  });
+  if (error) {
+    throw error;
+  }
+}
+
+/**
+ * List an endpoint's most recent platform events, oldest first. Returns null
+ * if the endpoint doesn't exist or isn't accessible.
+ */
+export async function listEndpointEventsForUser(input: {
+  userId: string;
+  slug: string;
+  limit?: number;
+}): Promise<PlatformEventRecord[] | null> {
+  const access = await resolveEndpointAccess(input.userId, input.slug);
+  if (!access) return null;
+
+  const limit = Math.min(
+    Math.max(1, Math.floor(input.limit ?? DEFAULT_EVENT_LIMIT)),
+    MAX_EVENT_LIMIT
+  );
+  const { data, error } = await createAdminClient()
+    .from("endpoint_events")
+    .select("id, endpoint_id, type, request_id, message, details, created_at")
+    .eq("endpoint_id", access.endpointId)
+    .order("created_at", { ascending: false })
+    .limit(limit);
+  if (error) {
+    throw error;
+  }
+
+  return (data ?? []).reverse().map(toPlatformEvent);
+}
@@ -1,10 +1,22 @@
+import { createHash } from "node:crypto";
+import { customAlphabet } from "nanoid";
 import { createAdminClient } from "./admin";
 import type { Database, Json } from "./database";
+import { recordEndpointEvent } from "./events";
 import { resolveEndpointAccess } from "./teams";
 
 const FREE_RETENTION_MS = 7 * 24 * 60 * 60 * 1000;
 const PRO_RETENTION_MS = 30 * 24 * 60 * 60 * 1000;
 const MAX_LIST_LIMIT = 1000;
+// Pinned requests outlive retention, so each account may only keep so many
+export const FREE_PIN_LIMIT = 25;
+export const PRO_PIN_LIMIT = 1000;
+export const MAX_SHARE_TTL_MS = 30 * 24 * 60 * 60 * 1000;
+
+const generateShareToken = customAlphabet(
+  "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+  32
+);
 
 type RequestRow = Database["public"]["Tables"]["requests"]["Row"];
 type SelectedRequestRow = Pick<
@@ -20,6 +32,10 @@ type SelectedRequestRow = Pick<
   | "ip"
   | "size"
   | "received_at"
+  | "pinned"
+  | "note"
+  | "response_status"
+  | "tags"
 >;
 type OwnedEndpointRow = Pick<Database["public"]["Tables"]["endpoints"]["Row"], "id" | "slug">;
 type UserPlan = Database["public"]["Tables"]["users"]["Row"]["plan"];
@@ -36,8 +52,20 @@ export interface RequestRecord {
   ip: string;
   size: number;
   receivedAt: number;
+  pinned?: boolean;
+  note?: string;
+  /** Status the endpoint answered with; missing for requests captured before migration 00023. */
+  responseStatus?: number;
+  tags?: string[];
 }
 
+/** Longest note accepted by setRequestNoteForUser (see migration 00018). */
+export const MAX_REQUEST_NOTE_LENGTH = 2000;
+
+/** Most tags a request can have (see migration 00025). */
+export const MAX_REQUEST_TAGS = 20;
+const REQUEST_TAG_PATTERN = /^[a-z0-9][a-z0-9._:/-]{0,63}$/;
+
 export interface PaginatedRequestPage {
   items: RequestRecord[];
   cursor?: string;
@@ -76,6 +104,10 @@ function normalizeRequest(row: SelectedRequestRow): RequestRecord {
     ip: row.ip,
     size: row.size,
     receivedAt: parseMillis(row.received_at),
+    pinned: row.pinned,
+    note: row.note ?? undefined,
+    responseStatus: row.response_status ?? undefined,
+    tags: row.tags.length > 0 ? row.tags : undefined,
   };
 }
 
@@ -147,7 +179,7 @@ async function getAccessibleEndpoint(
   return { id: access.endpointId, slug, ownerId: access.ownerId };
 }
 
-async function getUserCutoff(userId: string): Promise<number> {
+async function getUserPlan(userId: string): Promise<UserPlan | undefined> {
   const admin = createAdminClient();
   const { data: user, error } = await admin
     .from("users")
@@ -159,7 +191,12 @@ async function getUserCutoff(userId: string): Promise<number> {
     throw error;
   }
 
-  const retentionMs = user?.plan === "pro" ? PRO_RETENTION_MS : FREE_RETENTION_MS;
+  return user?.plan;
+}
+
+async function getUserCutoff(userId: string): Promise<number> {
+  const plan = await getUserPlan(userId);
+  const retentionMs = plan === "pro" ? PRO_RETENTION_MS : FREE_RETENTION_MS;
   return Date.now() - retentionMs;
 }
 
@@ -173,7 +210,7 @@ export async function getRequestByIdForUser(
   const { data, error } = await admin
     .from("requests")
     .select(
-      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at"
+      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at, pinned, note, response_status, tags"
     )
     .eq("id", requestId)
     .returns<SelectedRequestRow>()
@@ -198,19 +235,358 @@ export async function getRequestByIdForUser(
   const access = await resolveEndpointAccess(userId, endpointData.data.slug);
   if (!access) return null;
 
+  // Pinned requests outlive the retention window
   const cutoff = await getUserCutoff(access.ownerId);
-  if (parseMillis(row.received_at) < cutoff) {
+  if (!row.pinned && parseMillis(row.received_at) < cutoff) {
     return null;
   }
 
   return normalizeRequest(row);
 }
 
+/**
+ * Look up a request's id if the user owns its endpoint or has team access to
+ * it. Returns null if the request doesn't exist or isn't accessible.
+ */
+async function getAccessibleRequestId(userId: string, requestId: string): Promise<string | null> {
+  const request = await getAccessibleRequest(userId, requestId);
+  return request?.id ?? null;
+}
+
+/** Like getAccessibleRequestId, but also returns the request's endpoint id. */
+async function getAccessibleRequest(
+  userId: string,
+  requestId: string
+): Promise<{ id: string; endpointId: string } | null> {
+  const admin = createAdminClient();
+
+  const { data: row, error } = await admin
+    .from("requests")
+    .select("id, endpoint_id")
+    .eq("id", requestId)
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+  if (!row) return null;
+
+  const { data: endpoint, error: endpointError } = await admin
+    .from("endpoints")
+    .select("slug")
+    .eq("id", row.endpoint_id)
+    .maybeSingle();
+
+  if (endpointError) {
+    throw endpointError;
+  }
+  if (!endpoint) return null;
+
+  const access = await resolveEndpointAccess(userId, endpoint.slug);
+  return access ? { id: row.id, endpointId: row.endpoint_id } : null;
+}
+
+/**
+ * Update a request's pin or note. Returns false if the request doesn't exist
+ * or isn't accessible.
+ */
+async function updateRequestForUser(
+  userId: string,
+  requestId: string,
+  update: Pick<Database["public"]["Tables"]["requests"]["Update"], "pinned" | "note">
+): Promise<boolean> {
+  const id = await getAccessibleRequestId(userId, requestId);
+  if (!id) return false;
+
+  const { error } = await createAdminClient().from("requests").update(update).eq("id", id);
+  if (error) {
+    throw error;
+  }
+
+  return true;
+}
+
+export type SetRequestPinnedResult = "updated" | "not_found" | "limit_reached";
+
+/**
+ * Pin or unpin a request. Pinning fails with "limit_reached" once the
+ * endpoint owner has as many pinned requests as their plan allows.
+ */
+export async function setRequestPinnedForUser(
+  userId: string,
+  requestId: string,
+  pinned: boolean
+): Promise<SetRequestPinnedResult> {
+  if (pinned) {
+    const id = await getAccessibleRequestId(userId, requestId);
+    if (!id) return "not_found";
+
+    const admin = createAdminClient();
+    const { data: row, error } = await admin
+      .from("requests")
+      .select("user_id, pinned")
+      .eq("id", id)
+      .maybeSingle();
+
+    if (error) {
+      throw error;
+    }
+    if (!row) return "not_found";
+
+    // Ephemeral endpoints have no owner and are deleted with their requests
+    if (!row.pinned && row.user_id) {
+      const plan = await getUserPlan(row.user_id);
+      const limit = plan === "pro" ? PRO_PIN_LIMIT : FREE_PIN_LIMIT;
+      const { count, error: countError } = await admin
+        .from("requests")
+        .select("id", { count: "exact", head: true })
+        .eq("user_id", row.user_id)
+        .eq("pinned", true);
+
+      if (countError) {
+        throw countError;
+      }
+      if ((count ?? 0) >= limit) return "limit_reached";
+    }
+  }
+
+  const updated = await updateRequestForUser(userId, requestId, { pinned });
+  return updated ? "updated" : "not_found";
+}
+
+/** Set a request's note; null removes it. */
+export async function setRequestNoteForUser(
+  userId: string,
+  requestId: string,
+  note: string | null
+): Promise<boolean> {
+  return updateRequestForUser(userId, requestId, { note });
+}
+
+/**
+ * Lowercase and trim a tag, as the CLI does. Returns null if the result
+ * isn't 1-64 characters of [a-z0-9._:/-] starting with a letter or digit.
+ */
+export function normalizeRequestTag(tag: string): string | null {
+  const normalized = tag.trim().toLowerCase();
+  return REQUEST_TAG_PATTERN.test(normalized) ? normalized : null;
+}
+
+export type UpdateRequestTagsResult =
+  | { status: "updated"; tags: string[] }
+  | { status: "not_found" }
+  | { status: "limit_reached" };
+
+/**
+ * Add and remove normalized tags on a request. Fails with "limit_reached"
+ * if the request would have more than MAX_REQUEST_TAGS tags.
+ */
+export async function updateRequestTagsForUser(
+  userId: string,
+  requestId: string,
+  add: string[],
+  remove: string[]
+): Promise<UpdateRequestTagsResult> {
+  const id = await getAccessibleRequestId(userId, requestId);
+  if (!id) return { status: "not_found" };
+
+  const admin = createAdminClient();
+  const { data, error } = await admin.rpc("update_request_tags", {
+    p_request_id: id,
+    p_add: add,
+    p_remove: remove,
+  });
+
+  // requests_tags_count check violation
+  if (error?.code === "23514") {
+    return { status: "limit_reached" };
+  }
+  if (error) {
+    throw error;
+  }
+  if (!data) return { status: "not_found" };
+
+  return { status: "updated", tags: data };
+}
+
+export const FORWARD_SOURCES = ["tunnel", "receiver"] as const;
+
+export interface ForwardAttemptRecord {
+  requestId: string;
+  source: (typeof FORWARD_SOURCES)[number];
+  target: string;
+  attempt: number;
+  statusCode?: number;
+  latencyMs: number;
+  error?: string;
+  attemptedAt: number;
+}
+
+/**
+ * Record the outcome of forwarding a request, e.g. by `whk tunnel`, and a
+ * forward_attempt event on its endpoint. Returns false if the request
+ * doesn't exist or isn't accessible.
+ */
+export async function recordForwardAttemptForUser(
+  userId: string,
+  attempt: ForwardAttemptRecord
+): Promise<boolean> {
+  const request = await getAccessibleRequest(userId, attempt.requestId);
+  if (!request) return false;
+  const id = request.id;
+
+  const admin = createAdminClient();
+  const { error } = await admin.from("forward_attempts").insert({
+    request_id: id,
+    source: attempt.source,
+    target: attempt.target,
+    attempt: attempt.attempt,
+    status_code: attempt.statusCode ?? null,
+    latency_ms: attempt.latencyMs,
+    error: attempt.error ?? null,
+    attempted_at: new Date(attempt.attemptedAt).toISOString(),
+  });
+  if (error) {
+    throw error;
+  }
+
+  const outcome =
+    attempt.error ??
+    (attempt.statusCode !== undefined ? `HTTP ${attempt.statusCode}` : "no response");
+  await recordEndpointEvent({
+    endpointId: request.endpointId,
+    type: "forward_attempt",
+    requestId: id,
+    message: `Forwarded to ${attempt.target}: ${outcome}`,
+    details: {
+      source: attempt.source,
+      attempt: String(attempt.attempt),
+      latencyMs: String(attempt.latencyMs),
+      ...(attempt.statusCode !== undefined ? { status: String(attempt.statusCode) } : {}),
+    },
+  });
+
+  return true;
+}
+
+/**
+ * List a request's forward attempts, oldest first. Returns null if the request
+ * doesn't exist or isn't accessible.
+ */
+export async function listForwardAttemptsForUser(
+  userId: string,
+  requestId: string
+): Promise<ForwardAttemptRecord[] | null> {
+  const id = await getAccessibleRequestId(userId, requestId);
+  if (!id) return null;
+
+  const { data, error } = await createAdminClient()
+    .from("forward_attempts")
+    .select("source, target, attempt, status_code, latency_ms, error, attempted_at")
+    .eq("request_id", id)
+    .order("attempted_at", { ascending: true });
+  if (error) {
+    throw error;
+  }
+
+  return (data ?? []).map((row) => ({
+    requestId: id,
+    source: row.source,
+    target: row.target,
+    attempt: row.attempt,
+    ...(row.status_code !== null ? { statusCode: row.status_code } : {}),
+    latencyMs: row.latency_ms,
+    ...(row.error !== null ? { error: row.error } : {}),
+    attemptedAt: parseMillis(row.attempted_at),
+  }));
+}
+
+function hashShareToken(token: string): string {
+  return createHash("sha256").update(token).digest("hex");
+}
+
+/**
+ * Create a share link token for a request that is valid until expiresAt
+ * (Unix milliseconds). Returns null if the request doesn't exist or isn't
+ * accessible. Only the token's hash is stored.
+ */
+export async function createRequestShareForUser(
+  userId: string,
+  requestId: string,
+  expiresAt: number
+): Promise<string | null> {
+  const id = await getAccessibleRequestId(userId, requestId);
+  if (!id) return null;
+
+  const token = generateShareToken();
+  const admin = createAdminClient();
+  const { error } = await admin.from("request_shares").insert({
+    token_hash: hashShareToken(token),
+    request_id: id,
+    created_by: userId,
+    expires_at: new Date(expiresAt).toISOString(),
+  });
+  if (error) {
+    throw error;
+  }
+
+  return token;
+}
+
+/** A request as shown through a share link, without the sender's IP or the note. */
+export type SharedRequestRecord = Omit<RequestRecord, "ip" | "pinned" | "note" | "tags">;
+
+/** Look up the request behind a share token. Returns null if the link is unknown or expired. */
+export async function getSharedRequest(token: string): Promise<SharedRequestRecord | null> {
+  const admin = createAdminClient();
+  const { data: share, error } = await admin
+    .from("request_shares")
+    .select("request_id, expires_at")
+    .eq("token_hash", hashShareToken(token))
+    .maybeSingle();
+
+  if (error) {
+    throw error;
+  }
+  if (!share || parseMillis(share.expires_at) <= Date.now()) return null;
+
+  const { data, error: requestError } = await admin
+    .from("requests")
+    .select(
+      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at, pinned, note, response_status, tags"
+    )
+    .eq("id", share.request_id)
+    .returns<SelectedRequestRow>()
+    .maybeSingle();
+
+  if (requestError) {
+    throw requestError;
+  }
+  const row = data as SelectedRequestRow | null;
+  if (!row) return null;
+
+  const record = normalizeRequest(row);
+  return {
+    id: record.id,
+    endpointId: record.endpointId,
+    method: record.method,
+    path: record.path,
+    headers: record.headers,
+    body: record.body,
+    queryParams: record.queryParams,
+    contentType: record.contentType,
+    size: record.size,
+    receivedAt: record.receivedAt,
+    responseStatus: record.responseStatus,
+  };
+}
+
 export async function listRequestsForEndpointByUser(input: {
   userId: string;
   slug: string;
   limit?: number;
   since?: number;
+  pinned?: boolean;
 }): Promise<RequestRecord[] | null> {
   const admin = createAdminClient();
   const endpoint = await getAccessibleEndpoint(input.userId, input.slug);
@@ -219,15 +595,25 @@ export async function listRequestsForEndpointByUser(input: {
   }
 
   const cutoff = await getUserCutoff(endpoint.ownerId);
-  const floor = input.since === undefined ? cutoff : Math.max(input.since, cutoff);
 
-  const { data, error } = await admin
+  let query = admin
     .from("requests")
     .select(
-      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at"
+      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at, pinned, note, response_status, tags"
     )
-    .eq("endpoint_id", endpoint.id)
-    .gte("received_at", new Date(floor).toISOString())
+    .eq("endpoint_id", endpoint.id);
+
+  // Pinned requests outlive the retention window
+  if (input.pinned) {
+    query = query.eq("pinned", true);
+  } else {
+    query = query.or(`received_at.gte.${new Date(cutoff).toISOString()},pinned.is.true`);
+  }
+  if (input.since !== undefined) {
+    query = query.gte("received_at", new Date(input.since).toISOString());
+  }
+
+  const { data, error } = await query
     .order("received_at", { ascending: false })
     .limit(clampLimit(input.limit, 50))
     .returns<SelectedRequestRow[]>();
@@ -257,7 +643,7 @@ export async function listNewRequestsForEndpointByUser(input: {
   const { data, error } = await admin
     .from("requests")
     .select(
-      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at"
+      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at, pinned, note, response_status, tags"
     )
     .eq("endpoint_id", endpoint.id)
     .gt("received_at", new Date(floor).toISOString())
@@ -296,7 +682,7 @@ export async function listPaginatedRequestsForEndpointByUser(input: {
   const { data, error } = await admin
     .from("requests")
     .select(
-      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at"
+      "id, endpoint_id, method, path, headers, body, query_params, content_type, ip, size, received_at, pinned, note, response_status, tags"
     )
     .eq("endpoint_id", endpoint.id)
     .gte("received_at", new Date(cutoff).toISOString())
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- Migration 00017: API key scopes
+--
+-- Lets an API key be limited to a subset of the API:
+--   read     GET requests only (list endpoints and requests, stream, search)
+--   capture  GET requests to a single endpoint and its stream (listen, tunnel)
+--
+-- An empty array keeps the previous behaviour: full access. Enforcement
+-- lives in the application layer (lib/api-key-scopes.ts).
+-- ============================================================================
+
+alter table public.api_keys
+  add column scopes text[] not null default '{}'
+  constraint api_keys_scopes_valid check (scopes <@ array['read', 'capture']::text[]);
@@ -0,0 +1,48 @@
+-- ============================================================================
+-- Migration 00018: Pinned requests and request notes
+--
+-- Pinned requests are kept by the retention cleanup jobs and stay readable
+-- past the plan's retention window. A note is free-form text shown with the
+-- request. Both are set through /api/requests/[id]/pin and /note.
+-- ============================================================================
+
+alter table public.requests
+  add column pinned boolean not null default false,
+  add column note text constraint requests_note_length check (char_length(note) <= 2000);
+
+create index requests_pinned on public.requests(endpoint_id, received_at desc)
+  where pinned;
+
+-- Retention cleanup skips pinned requests
+create or replace function public.cleanup_free_user_requests()
+returns integer
+language plpgsql
+security definer set search_path = ''
+as $$
+declare
+  deleted integer;
+begin
+  delete from public.requests
+  where user_id in (select id from public.users where plan = 'free')
+    and received_at < now() - interval '7 days'
+    and not pinned;
+  get diagnostics deleted = row_count;
+  return deleted;
+end;
+$$;
+
+create or replace function public.cleanup_old_requests()
+returns integer
+language plpgsql
+security definer set search_path = public
+as $$
+declare
+  deleted integer;
+begin
+  delete from public.requests
+  where received_at < now() - interval '31 days'
+    and not pinned;
+  get diagnostics deleted = row_count;
+  return deleted;
+end;
+$$;
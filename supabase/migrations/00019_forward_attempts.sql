@@ -0,0 +1,28 @@
+-- ============================================================================
+-- Migration 00019: Forward attempts
+--
+-- Records each attempt to deliver a captured request to a forward target,
+-- reported by `whk tunnel --report`. Written and read through
+-- /api/requests/[id]/forwards with the admin client; attempts are deleted
+-- with their request.
+-- ============================================================================
+
+create table public.forward_attempts (
+  id uuid primary key default gen_random_uuid(),
+  request_id uuid not null references public.requests(id) on delete cascade,
+  source text not null check (source in ('tunnel', 'receiver')),
+  target text not null,
+  attempt integer not null check (attempt > 0),
+  status_code integer,
+  latency_ms integer not null,
+  error text,
+  attempted_at timestamptz not null default now()
+);
+
+alter table public.forward_attempts enable row level security;
+create policy forward_attempts_deny_all_select on public.forward_attempts for select using (false);
+create policy forward_attempts_deny_all_insert on public.forward_attempts for insert with check (false);
+create policy forward_attempts_deny_all_update on public.forward_attempts for update using (false);
+create policy forward_attempts_deny_all_delete on public.forward_attempts for delete using (false);
+
+create index forward_attempts_request on public.forward_attempts(request_id, attempted_at);
@@ -0,0 +1,45 @@
+-- ============================================================================
+-- Migration 00021: Request share links
+--
+-- A share link lets anyone view one captured request until it expires,
+-- without an account. Links are created through /api/requests/[id]/share
+-- and opened through /api/shares/[token]. Only the SHA-256 hash of the
+-- token is stored, like API keys; shares are deleted with their request.
+-- ============================================================================
+
+create table public.request_shares (
+  token_hash text primary key,
+  request_id uuid not null references public.requests(id) on delete cascade,
+  created_by uuid not null references public.users(id) on delete cascade,
+  expires_at timestamptz not null,
+  created_at timestamptz not null default now()
+);
+
+alter table public.request_shares enable row level security;
+create policy request_shares_deny_all_select on public.request_shares for select using (false);
+create policy request_shares_deny_all_insert on public.request_shares for insert with check (false);
+create policy request_shares_deny_all_update on public.request_shares for update using (false);
+create policy request_shares_deny_all_delete on public.request_shares for delete using (false);
+
+create index request_shares_expires_at on public.request_shares(expires_at);
+
+create or replace function public.cleanup_expired_request_shares()
+returns integer
+language plpgsql
+security definer set search_path = ''
+as $$
+declare
+  deleted integer;
+begin
+  delete from public.request_shares
+  where expires_at <= now();
+  get diagnostics deleted = row_count;
+  return deleted;
+end;
+$$;
+
+select cron.schedule(
+  'cleanup-expired-request-shares-hourly',
+  '15 * * * *',
+  'select public.cleanup_expired_request_shares();'
+);
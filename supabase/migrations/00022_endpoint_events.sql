@@ -0,0 +1,248 @@
+-- ============================================================================
+-- Migration 00022: Endpoint events
+--
+-- Platform events record what happened to deliveries to an endpoint,
+-- separately from the captured requests: capture_webhook records quota
+-- denials, mock responses served and payloads near the body size limit,
+-- and /api/requests/[id]/forwards records the forward attempts `whk tunnel
+-- --report` sends. Read through /api/endpoints/[slug]/events and streamed
+-- by /api/events/[slug] over realtime. Events are kept for 7 days.
+-- ============================================================================
+
+create table public.endpoint_events (
+  id uuid primary key default gen_random_uuid(),
+  endpoint_id uuid not null references public.endpoints(id) on delete cascade,
+  type text not null check (type in ('quota_denied', 'mock_served', 'forward_attempt', 'size_warning')),
+  request_id uuid references public.requests(id) on delete set null,
+  message text not null,
+  details jsonb not null default '{}'::jsonb,
+  created_at timestamptz not null default now()
+);
+
+alter table public.endpoint_events enable row level security;
+create policy endpoint_events_deny_all_select on public.endpoint_events for select using (false);
+create policy endpoint_events_deny_all_insert on public.endpoint_events for insert with check (false);
+create policy endpoint_events_deny_all_update on public.endpoint_events for update using (false);
+create policy endpoint_events_deny_all_delete on public.endpoint_events for delete using (false);
+
+create index endpoint_events_endpoint_time on public.endpoint_events(endpoint_id, created_at desc);
+create index endpoint_events_created_at on public.endpoint_events(created_at);
+
+do $$
+begin
+  alter publication supabase_realtime add table public.endpoint_events;
+exception
+  when duplicate_object then null;
+end
+$$;
+
+create or replace function public.record_endpoint_event(
+  p_endpoint_id uuid,
+  p_type        text,
+  p_request_id  uuid,
+  p_message     text,
+  p_details     jsonb
+)
+returns void
+language sql
+security definer set search_path = ''
+as $$
+  insert into public.endpoint_events (endpoint_id, type, request_id, message, details)
+  values (p_endpoint_id, p_type, p_request_id, p_message, coalesce(p_details, '{}'::jsonb));
+$$;
+
+revoke all on function public.record_endpoint_event(uuid, text, uuid, text, jsonb) from public, anon, authenticated;
+grant execute on function public.record_endpoint_event(uuid, text, uuid, text, jsonb) to service_role;
+
+create or replace function public.cleanup_old_endpoint_events()
+returns integer
+language plpgsql
+security definer set search_path = ''
+as $$
+declare
+  deleted integer;
+begin
+  delete from public.endpoint_events
+  where created_at < now() - interval '7 days';
+  get diagnostics deleted = row_count;
+  return deleted;
+end;
+$$;
+
+select cron.schedule(
+  'cleanup-old-endpoint-events-daily',
+  '45 1 * * *',
+  'select public.cleanup_old_endpoint_events();'
+);
+
+-- capture_webhook records quota denials, mock responses and size warnings
+create or replace function public.capture_webhook(
+  p_slug        text,
+  p_method      text,
+  p_path        text,
+  p_headers     jsonb,
+  p_body        text,
+  p_query_params jsonb,
+  p_content_type text,
+  p_ip          text,
+  p_received_at timestamptz
+)
+returns jsonb
+language plpgsql
+security definer set search_path = ''
+as $$
+declare
+  v_endpoint    record;
+  v_user        record;
+  v_quota       record;
+  v_period      record;
+  v_retry_after bigint;
+  v_size        integer;
+  v_mock        jsonb;
+  v_slug        text;
+  v_request_id  uuid;
+begin
+  -- Normalize slug to lowercase for case-insensitive lookup
+  v_slug := lower(p_slug);
+
+  -- 1. Look up endpoint by slug or alias
+  select id, user_id, is_ephemeral, expires_at, mock_response, request_count
+    into v_endpoint
+    from public.endpoints
+   where slug = v_slug;
+
+  -- Fall back to the endpoint's aliases
+  if not found then
+    select e.id, e.user_id, e.is_ephemeral, e.expires_at, e.mock_response, e.request_count
+      into v_endpoint
+      from public.endpoint_aliases a
+      join public.endpoints e on e.id = a.endpoint_id
+     where a.alias = v_slug;
+  end if;
+
+  if not found then
+    return jsonb_build_object('status', 'not_found');
+  end if;
+
+  -- 2. Check expiry
+  if v_endpoint.expires_at is not null and v_endpoint.expires_at <= now() then
+    return jsonb_build_object('status', 'expired');
+  end if;
+
+  -- 3. Quota check (branching by endpoint type)
+  if v_endpoint.is_ephemeral and v_endpoint.user_id is null then
+    -- Ephemeral endpoint: atomic increment with 25-request cap
+    select request_count into v_quota
+      from public.check_and_increment_ephemeral(v_endpoint.id);
+
+    if not found then
+      perform public.record_endpoint_event(
+        v_endpoint.id, 'quota_denied', null,
+        'Rejected ' || p_method || ' ' || p_path || ': ephemeral endpoint request limit reached',
+        jsonb_build_object('method', p_method, 'path', p_path)
+      );
+      return jsonb_build_object('status', 'quota_exceeded');
+    end if;
+
+  elsif v_endpoint.user_id is not null then
+    -- Owned endpoint: check user quota
+    select id, plan, request_limit, requests_used, period_end
+      into v_user
+      from public.users
+     where id = v_endpoint.user_id;
+
+    if not found then
+      return jsonb_build_object('status', 'not_found');
+    end if;
+
+    -- Free user with expired or unstarted period: start a new one
+    if v_user.plan = 'free' and (v_user.period_end is null or v_user.period_end <= now()) then
+      select remaining, quota_limit, period_end_ts into v_period
+        from public.start_free_period(v_endpoint.user_id);
+
+      if not found then
+        -- Period start failed (shouldn't happen, but handle gracefully)
+        return jsonb_build_object('status', 'quota_exceeded');
+      end if;
+
+      -- Refresh user row after period reset
+      select id, plan, request_limit, requests_used, period_end
+        into v_user
+        from public.users
+       where id = v_endpoint.user_id;
+    end if;
+
+    -- Atomic quota check + decrement
+    select remaining, quota_limit, period_end_ts into v_quota
+      from public.check_and_decrement_quota(v_endpoint.user_id, 1);
+
+    if not found then
+      -- Quota exceeded
+      v_retry_after := null;
+      if v_user.period_end is not null and v_user.period_end > now() then
+        v_retry_after := extract(epoch from (v_user.period_end - now()))::bigint * 1000;
+      end if;
+
+      perform public.record_endpoint_event(
+        v_endpoint.id, 'quota_denied', null,
+        'Rejected ' || p_method || ' ' || p_path || ': request quota exceeded',
+        jsonb_build_object('method', p_method, 'path', p_path, 'plan', v_user.plan)
+      );
+      return jsonb_build_object(
+        'status', 'quota_exceeded',
+        'retry_after', v_retry_after
+      );
+    end if;
+
+  end if;
+  -- else: owned endpoint with null user_id but not ephemeral — allow through (no quota)
+
+  -- 4. Insert the request
+  v_size := coalesce(octet_length(p_body), 0);
+
+  insert into public.requests (
+    endpoint_id, user_id, method, path, headers, body,
+    query_params, content_type, ip, size, received_at
+  ) values (
+    v_endpoint.id, v_endpoint.user_id, p_method, p_path, p_headers, p_body,
+    p_query_params, p_content_type, p_ip, v_size, p_received_at
+  )
+  returning id into v_request_id;
+
+  -- Warn before payloads reach the receiver's 1MB body limit (80%)
+  if v_size * 100 >= 1048576 * 80 then
+    perform public.record_endpoint_event(
+      v_endpoint.id, 'size_warning', v_request_id,
+      'Payload is close to the 1MB body size limit',
+      jsonb_build_object('size', v_size::text, 'limit', '1048576')
+    );
+  end if;
+
+  -- 5. Increment endpoint request count (ephemeral already incremented above)
+  if not (v_endpoint.is_ephemeral and v_endpoint.user_id is null) then
+    perform public.increment_endpoint_request_count(v_endpoint.id, 1);
+  end if;
+
+  -- User requests_used already incremented by check_and_decrement_quota
+
+  -- 6. Build response
+  v_mock := null;
+  if v_endpoint.mock_response is not null
+     and jsonb_typeof(v_endpoint.mock_response) = 'object'
+     and (v_endpoint.mock_response ? 'status')
+  then
+    v_mock := v_endpoint.mock_response;
+    perform public.record_endpoint_event(
+      v_endpoint.id, 'mock_served', v_request_id,
+      'Mock response served',
+      jsonb_build_object('status', v_mock->>'status', 'method', p_method, 'path', p_path)
+    );
+  end if;
+
+  return jsonb_build_object(
+    'status', 'ok',
+    'mock_response', v_mock,
+    'retry_after', null::bigint
+  );
+end;
+$$;
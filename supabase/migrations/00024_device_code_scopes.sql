@@ -0,0 +1,11 @@
+-- ============================================================================
+-- Migration 00024: Device code scopes
+--
+-- `whk auth login --scope` asks for a limited API key (see migration 00017).
+-- The requested scopes are stored with the device code and copied to the key
+-- issued when the code is claimed. An empty array requests full access.
+-- ============================================================================
+
+alter table public.device_codes
+  add column scopes text[] not null default '{}'
+  constraint device_codes_scopes_valid check (scopes <@ array['read', 'capture']::text[]);
@@ -0,0 +1,42 @@
+-- ============================================================================
+-- Migration 00025: Request tags
+--
+-- Tags label captured requests for an investigation, e.g. bug-1234 or
+-- env:staging. The application layer normalizes them (lowercase, up to 64
+-- characters of [a-z0-9._:/-]); a request has at most 20. Updated through
+-- PATCH /api/requests/[id]/tags.
+-- ============================================================================
+
+alter table public.requests
+  add column tags text[] not null default '{}'
+  constraint requests_tags_count check (cardinality(tags) <= 20);
+
+create index requests_tags on public.requests using gin (tags);
+
+-- Adds and removes tags in one statement, so concurrent updates don't
+-- overwrite each other. Existing tags keep their order and new ones are
+-- appended. Returns the request's tags afterwards, or null if it doesn't
+-- exist; more than 20 tags fails the requests_tags_count check.
+create or replace function public.update_request_tags(
+  p_request_id uuid,
+  p_add        text[],
+  p_remove     text[]
+)
+returns text[]
+language sql
+security definer set search_path = ''
+as $$
+  update public.requests r
+     set tags = array(
+       select t
+         from unnest(r.tags || p_add) with ordinality as u(t, ord)
+        where t <> all(p_remove)
+        group by t
+        order by min(ord)
+     )
+   where r.id = p_request_id
+  returning r.tags;
+$$;
+
+revoke all on function public.update_request_tags(uuid, text[], text[]) from public, anon, authenticated;
+grant execute on function public.update_request_tags(uuid, text[], text[]) to service_role;